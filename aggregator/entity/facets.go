@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+type FacetValue struct {
+	Value string `json:"value" bson:"_id"`
+	Count int    `json:"count" bson:"count"`
+}
+
+type FacetDate struct {
+	Date  time.Time `json:"date" bson:"_id"`
+	Count int       `json:"count" bson:"count"`
+}
+
+type Facets struct {
+	Sources    []FacetValue `json:"sources" bson:"sources"`
+	Tags       []FacetValue `json:"tags" bson:"tags"`
+	Authors    []FacetValue `json:"authors" bson:"authors"`
+	Categories []FacetValue `json:"categories" bson:"categories"`
+	Dates      []FacetDate  `json:"dates" bson:"dates"`
+}
@@ -3,18 +3,31 @@ package entity
 import "time"
 
 type NewsHead struct {
-	ID          string    `json:"id" bson:"_id,omitempty"`
-	Title       string    `json:"title" bson:"title"`
-	Description string    `json:"description" bson:"description"`
-	Source      string    `json:"source" bson:"source"`
-	PublishedAt time.Time `json:"published_at" bson:"published_at"`
+	ID          string      `json:"id" bson:"_id,omitempty"`
+	UID         string      `json:"uid" bson:"uid"`
+	Title       string      `json:"title" bson:"title"`
+	Description string      `json:"description" bson:"description"`
+	Source      string      `json:"source" bson:"source"`
+	PublishedAt time.Time   `json:"published_at" bson:"published_at"`
+	ModifiedAt  time.Time   `json:"modified_at" bson:"modified_at"`
+	Changed     bool        `json:"changed" bson:"changed"`
+	Highlights  *Highlights `json:"highlights,omitempty" bson:"-"`
+}
+
+// Highlights contains text fragments with marked search matches.
+type Highlights struct {
+	Title       []string `json:"title,omitempty"`
+	Description []string `json:"description,omitempty"`
+	Content     []string `json:"content,omitempty"`
 }
 
 type News struct {
 	NewsHead   `bson:"inline"`
-	Link       string   `json:"link" bson:"link"`
-	Authors    []string `json:"authors" bson:"authors"`
-	Tags       []string `json:"tags" bson:"tags"`
-	Categories []string `json:"categories" bson:"categories"`
-	Content    string   `json:"content" bson:"content"`
+	Link       string    `json:"link" bson:"link"`
+	Authors    []string  `json:"authors" bson:"authors"`
+	Tags       []string  `json:"tags" bson:"tags"`
+	Categories []string  `json:"categories" bson:"categories"`
+	Content    string    `json:"content" bson:"content"`
+	FetchedAt  time.Time `json:"fetched_at" bson:"fetched_at"`
+	IngestedAt time.Time `json:"ingested_at" bson:"ingested_at"`
 }
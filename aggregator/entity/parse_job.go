@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+type ParseJobState string
+
+const (
+	ParseJobQueued  ParseJobState = "queued"
+	ParseJobRunning ParseJobState = "running"
+	ParseJobDone    ParseJobState = "done"
+	ParseJobFailed  ParseJobState = "failed"
+)
+
+func (s ParseJobState) IsFinished() bool {
+	return s == ParseJobDone || s == ParseJobFailed
+}
+
+// ParseJob tracks parse request sent to parsers.
+type ParseJob struct {
+	ID        string                    `json:"id" bson:"_id"`
+	Query     string                    `json:"query" bson:"query"`
+	Targets   []string                  `json:"targets" bson:"targets"`
+	State     ParseJobState             `json:"state" bson:"state"`
+	Sources   map[string]ParseJobSource `json:"sources" bson:"sources"`
+	Error     string                    `json:"error,omitempty" bson:"error,omitempty"`
+	CreatedAt time.Time                 `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time                 `json:"updated_at" bson:"updated_at"`
+}
+
+// ParseJobSource is progress of parse job in parser of source.
+type ParseJobSource struct {
+	State ParseJobState `json:"state" bson:"state"`
+	// Count is number of news parsed, New is number of them stored as new or updated.
+	Count      int        `json:"count" bson:"count"`
+	New        int        `json:"new" bson:"new"`
+	Error      string     `json:"error,omitempty" bson:"error,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty" bson:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty" bson:"finished_at,omitempty"`
+}
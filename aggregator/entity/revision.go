@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+// Revision is a stored version of news with changes relative to previous version.
+type Revision struct {
+	ID          string      `json:"id" bson:"_id,omitempty"`
+	NewsID      string      `json:"news_id" bson:"news_id"`
+	Version     int         `json:"version" bson:"version"`
+	Title       string      `json:"title" bson:"title"`
+	Description string      `json:"description" bson:"description"`
+	Authors     []string    `json:"authors" bson:"authors"`
+	Tags        []string    `json:"tags" bson:"tags"`
+	Categories  []string    `json:"categories" bson:"categories"`
+	Content     string      `json:"content" bson:"content"`
+	PublishedAt time.Time   `json:"published_at" bson:"published_at"`
+	ModifiedAt  time.Time   `json:"modified_at" bson:"modified_at"`
+	FetchedAt   time.Time   `json:"fetched_at" bson:"fetched_at"`
+	CreatedAt   time.Time   `json:"created_at" bson:"created_at"`
+	Diff        []FieldDiff `json:"diff,omitempty" bson:"diff,omitempty"`
+}
+
+type FieldDiff struct {
+	Field   string       `json:"field" bson:"field"`
+	Changes []DiffChange `json:"changes" bson:"changes"`
+}
+
+type DiffChange struct {
+	Op   string `json:"op" bson:"op"`
+	Text string `json:"text" bson:"text"`
+}
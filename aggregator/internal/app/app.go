@@ -13,7 +13,10 @@ import (
 	"github.com/qsoulior/news/aggregator/internal/service"
 	"github.com/qsoulior/news/aggregator/internal/transport/amqp"
 	"github.com/qsoulior/news/aggregator/internal/transport/http"
+	"github.com/qsoulior/news/aggregator/pkg/bleveindex"
+	"github.com/qsoulior/news/aggregator/pkg/highlight"
 	"github.com/qsoulior/news/aggregator/pkg/httpserver"
+	"github.com/qsoulior/news/aggregator/pkg/message"
 	"github.com/qsoulior/news/aggregator/pkg/mongodb"
 	"github.com/qsoulior/news/aggregator/pkg/rabbitmq"
 	"github.com/qsoulior/news/aggregator/pkg/rabbitmq/consumer"
@@ -65,6 +68,45 @@ func Run(cfg *Config) {
 	db := mongo.Client.Database("app")
 	newsRepo := repo.NewNewsMongo(db)
 
+	// search index
+	var (
+		newsSearch repo.Search
+		index      *bleveindex.Index
+	)
+
+	highlightOpts := highlight.Options{
+		FragmentSize: cfg.Search.Highlight.FragmentSize,
+		MaxFragments: cfg.Search.Highlight.MaxFragments,
+		PreTag:       cfg.Search.Highlight.PreTag,
+		PostTag:      cfg.Search.Highlight.PostTag,
+	}
+
+	if cfg.Search.Path != "" {
+		searchLog := logger.With().Str("module", "search").Logger()
+		index, err = bleveindex.New(&bleveindex.Config{
+			Path:    cfg.Search.Path,
+			Mapping: repo.NewsBleveMapping(),
+			Version: repo.NewsBleveVersion,
+		})
+		if err != nil {
+			searchLog.Error().Err(err).Send()
+			return
+		}
+		searchLog.Info().Str("path", cfg.Search.Path).Bool("created", index.Created).Msg("started")
+
+		defer func() {
+			// search index graceful shutdown
+			err := index.Close()
+			if err != nil {
+				searchLog.Error().Err(err).Msg("graceful shutdown")
+				return
+			}
+			searchLog.Info().Msg("graceful shutdown")
+		}()
+
+		newsSearch = repo.NewNewsBleve(index.Index, highlightOpts)
+	}
+
 	// rabbit connection
 	rmqLog := logger.With().Str("module", "rmq").Logger()
 	rmqConn, err := runRMQ(rmqLog.WithContext(ctx), cfg.RabbitMQ)
@@ -77,17 +119,50 @@ func Run(cfg *Config) {
 	// rabbit producer
 	rmqProducer := producer.New(rmqConn)
 	newsService := service.NewNews(service.NewsConfig{
-		Producer:   rmqProducer,
-		Exchange:   "query",
-		RoutingKey: "",
-		Repo:       newsRepo,
+		Producer:       rmqProducer,
+		Exchange:       message.ParseExchange,
+		LegacyExchange: message.LegacyParseExchange,
+		Repo:           newsRepo,
+		Search:         newsSearch,
+		Highlight:      highlightOpts,
 	})
 
+	jobService := service.NewParseJob(service.ParseJobConfig{
+		Repo:     repo.NewParseJobMongo(db),
+		News:     newsService,
+		Sources:  cfg.Parse.Sources,
+		Timeout:  cfg.Parse.Timeout,
+		Throttle: repo.NewParseThrottleMongo(db),
+		Cooldown: cfg.Parse.Cooldown,
+		Budget:   cfg.Parse.Budget,
+	})
+
+	// identifiers of news are set before news are consumed
+	// so news stored before them are not duplicated
+	if err := backfillUID(ctx, newsService); err != nil {
+		return
+	}
+
+	// news stored before timestamps were introduced are filtered and sorted by them
+	if err := backfillTimestamps(ctx, newsService); err != nil {
+		return
+	}
+
+	// search reindex runs until it is completed, it is continued after restart,
+	// then news failed to be indexed are indexed again
+	if index != nil {
+		runReindexer(ctx, newsService)
+	}
+
 	// rabbit consumer
-	runConsumer(ctx, newsService, rmqConn)
+	runConsumer(ctx, amqp.Config{
+		NewsService: newsService,
+		JobService:  jobService,
+		Producer:    rmqProducer,
+	}, rmqConn)
 
 	// http server
-	runServer(ctx, newsService, cfg.HTTP)
+	runServer(ctx, newsService, jobService, cfg.HTTP)
 
 	wg.Wait()
 }
@@ -103,7 +178,12 @@ func runRMQ(ctx context.Context, cfg ConfigRabbitMQ) (*rabbitmq.Connection, erro
 		return nil, fmt.Errorf("rabbitmq.New: %w", err)
 	}
 
-	err = rmqConn.Ch.ExchangeDeclare("query", "fanout", true, false, false, false, nil)
+	err = rmqConn.Ch.ExchangeDeclare(message.ParseExchange, "topic", true, false, false, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("rmqConn.Ch.ExchangeDeclare: %w", err)
+	}
+
+	err = rmqConn.Ch.ExchangeDeclare(message.LegacyParseExchange, "fanout", true, false, false, false, nil)
 	if err != nil {
 		return nil, fmt.Errorf("rmqConn.Ch.ExchangeDeclare: %w", err)
 	}
@@ -113,6 +193,17 @@ func runRMQ(ctx context.Context, cfg ConfigRabbitMQ) (*rabbitmq.Connection, erro
 		return nil, fmt.Errorf("rmqConn.Ch.QueueDeclare: %w", err)
 	}
 
+	_, err = rmqConn.Ch.QueueDeclare(message.ProgressQueue, true, false, false, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("rmqConn.Ch.QueueDeclare: %w", err)
+	}
+
+	// messages failed validation
+	_, err = rmqConn.Ch.QueueDeclare("news.invalid", true, false, false, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("rmqConn.Ch.QueueDeclare: %w", err)
+	}
+
 	wg.Add(1)
 	go func(ctx context.Context) {
 		defer wg.Done()
@@ -131,27 +222,93 @@ func runRMQ(ctx context.Context, cfg ConfigRabbitMQ) (*rabbitmq.Connection, erro
 	return rmqConn, nil
 }
 
-func runConsumer(ctx context.Context, news service.News, conn *rabbitmq.Connection) {
+func runConsumer(ctx context.Context, cfg amqp.Config, conn *rabbitmq.Connection) {
 	log := zerolog.Ctx(ctx).With().Str("module", "consumer").Logger()
 
-	amqpRouter := amqp.NewRouter(&log, news)
+	cfg.Logger = &log
+	amqpRouter := amqp.NewRouter(cfg)
 	rmqConsumer := consumer.New(conn, amqpRouter, consumer.Ack(false))
 
+	for _, queue := range []string{"news", message.ProgressQueue} {
+		wg.Add(1)
+		go func(ctx context.Context) {
+			defer wg.Done()
+			for timer := time.NewTimer(0); ; timer.Reset(5 * time.Second) {
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+					err := rmqConsumer.Consume(ctx, queue)
+					if err != nil {
+						log.Error().Err(err).Str("queue", queue).Send()
+						return
+					}
+					log.Info().Str("queue", queue).Msg("graceful shutdown")
+				}
+			}
+		}(ctx)
+	}
+
+	log.Info().Msg("started")
+}
+
+func backfillUID(ctx context.Context, news service.News) error {
+	log := zerolog.Ctx(ctx).With().Str("module", "migration").Logger()
+
+	count, err := news.BackfillUID(ctx)
+	if err != nil {
+		log.Error().Err(err).Int("count", count).Send()
+		return err
+	}
+
+	log.Info().Int("count", count).Msg("uid backfilled")
+	return nil
+}
+
+func backfillTimestamps(ctx context.Context, news service.News) error {
+	log := zerolog.Ctx(ctx).With().Str("module", "migration").Logger()
+
+	count, err := news.BackfillTimestamps(ctx)
+	if err != nil {
+		log.Error().Err(err).Int("count", count).Send()
+		return err
+	}
+
+	log.Info().Int("count", count).Msg("timestamps backfilled")
+	return nil
+}
+
+func runReindexer(ctx context.Context, news service.News) {
+	log := zerolog.Ctx(ctx).With().Str("module", "reindexer").Logger()
+
 	wg.Add(1)
 	go func(ctx context.Context) {
 		defer wg.Done()
-		for timer := time.NewTimer(0); ; timer.Reset(5 * time.Second) {
+		reindexed := false
+		for timer := time.NewTimer(0); ; timer.Reset(time.Minute) {
 			select {
 			case <-ctx.Done():
 				timer.Stop()
 				return
 			case <-timer.C:
-				err := rmqConsumer.Consume(ctx, "news")
+				// failed reindex is continued from checkpoint on next tick
+				if !reindexed {
+					count, err := news.Reindex(ctx)
+					if err != nil {
+						log.Error().Err(err).Int("count", count).Send()
+						continue
+					}
+					log.Info().Int("count", count).Msg("reindexed")
+					reindexed = true
+				}
+
+				count, err := news.RetryIndex(ctx)
 				if err != nil {
-					log.Error().Err(err).Send()
-					return
+					log.Error().Err(err).Int("count", count).Send()
+				} else if count > 0 {
+					log.Info().Int("count", count).Msg("queued news indexed")
 				}
-				log.Info().Msg("graceful shutdown")
 			}
 		}
 	}(ctx)
@@ -159,10 +316,10 @@ func runConsumer(ctx context.Context, news service.News, conn *rabbitmq.Connecti
 	log.Info().Msg("started")
 }
 
-func runServer(ctx context.Context, news service.News, cfg ConfigHTTP) {
+func runServer(ctx context.Context, news service.News, jobs service.ParseJob, cfg ConfigHTTP) {
 	log := zerolog.Ctx(ctx).With().Str("module", "server").Logger()
 
-	httpRouter := http.NewRouter(news)
+	httpRouter := http.NewRouter(news, jobs)
 	httpServer := httpserver.New(httpRouter, httpserver.Addr(cfg.Host, cfg.Port))
 
 	wg.Add(1)
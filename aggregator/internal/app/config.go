@@ -2,6 +2,7 @@ package app
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -11,6 +12,8 @@ type (
 		HTTP     ConfigHTTP     `yaml:"http"`
 		RabbitMQ ConfigRabbitMQ `yaml:"rabbitmq"`
 		MongoDB  ConfigMongoDB  `yaml:"mongodb"`
+		Search   ConfigSearch   `yaml:"search"`
+		Parse    ConfigParse    `yaml:"parse"`
 	}
 
 	ConfigHTTP struct {
@@ -26,6 +29,23 @@ type (
 	ConfigMongoDB struct {
 		URI string `yaml:"uri"`
 	}
+
+	ConfigParse struct {
+		Sources  []string      `yaml:"sources"`
+		Timeout  time.Duration `yaml:"timeout" env-default:"10m"`
+		Cooldown time.Duration `yaml:"cooldown" env-default:"15m"`
+		Budget   int           `yaml:"budget" env-default:"30"`
+	}
+
+	ConfigSearch struct {
+		Path      string `yaml:"path"`
+		Highlight struct {
+			FragmentSize int    `yaml:"fragment_size"`
+			MaxFragments int    `yaml:"max_fragments"`
+			PreTag       string `yaml:"pre_tag"`
+			PostTag      string `yaml:"post_tag"`
+		} `yaml:"highlight"`
+	}
 )
 
 func NewConfig(path string) (*Config, error) {
@@ -3,6 +3,6 @@ package repo
 import "errors"
 
 var (
-	ErrInvalidID = errors.New("invalid ObjectID")
+	ErrInvalidID = errors.New("invalid ID")
 	ErrNotFound  = errors.New("entity not found")
 )
@@ -0,0 +1,467 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/lang/ru"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/single"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/aggregator/pkg/highlight"
+	"github.com/qsoulior/news/aggregator/pkg/querylang"
+)
+
+const keywordAnalyzer = "keyword_lower"
+
+// NewsBleveVersion is version of index mapping, index is rebuilt once it is changed.
+const NewsBleveVersion = "3"
+
+// checkpointKey is internal key of last news indexed by reindex.
+var checkpointKey = []byte("reindex")
+
+// newsDocument is indexed by UID of news. Dates truncated to intervals
+// are indexed as keywords for facets.
+type newsDocument struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Content     string    `json:"content"`
+	Source      string    `json:"source"`
+	Authors     []string  `json:"authors"`
+	Tags        []string  `json:"tags"`
+	Categories  []string  `json:"categories"`
+	PublishedAt time.Time `json:"published_at"`
+	ModifiedAt  time.Time `json:"modified_at"`
+	FetchedAt   time.Time `json:"fetched_at"`
+	IngestedAt  time.Time `json:"ingested_at"`
+
+	// Dates are keyed by dateFacet.
+	Dates map[string]string `json:"dates"`
+}
+
+// dateFacet returns key of date field truncated to interval.
+func dateFacet(field DateField, interval DateInterval) string {
+	return string(field) + "_" + string(interval)
+}
+
+// newsDate returns timestamp of news by its field.
+func newsDate(news *entity.News, field DateField) time.Time {
+	switch field {
+	case DateModifiedAt:
+		return news.ModifiedAt
+	case DateFetchedAt:
+		return news.FetchedAt
+	case DateIngestedAt:
+		return news.IngestedAt
+	}
+
+	return news.PublishedAt
+}
+
+type newsBleve struct {
+	index     bleve.Index
+	highlight highlight.Options
+}
+
+func NewNewsBleve(index bleve.Index, highlight highlight.Options) Search {
+	highlight.SetDefault()
+	return &newsBleve{index, highlight}
+}
+
+// NewsBleveMapping returns index mapping with russian morphology for text fields
+// and case-insensitive keyword fields. Keyword fields are indexed as is
+// under "_facet" suffix for facets.
+func NewsBleveMapping() mapping.IndexMapping {
+	textField := bleve.NewTextFieldMapping()
+	textField.Analyzer = ru.AnalyzerName
+
+	keywordField := bleve.NewTextFieldMapping()
+	keywordField.Analyzer = keywordAnalyzer
+	keywordField.IncludeTermVectors = false
+
+	facetField := func(name string) *mapping.FieldMapping {
+		field := bleve.NewKeywordFieldMapping()
+		field.Name = name
+		field.IncludeTermVectors = false
+		field.IncludeInAll = false
+		return field
+	}
+
+	dateField := bleve.NewDateTimeFieldMapping()
+
+	document := bleve.NewDocumentMapping()
+	document.AddFieldMappingsAt("title", textField)
+	document.AddFieldMappingsAt("description", textField)
+	document.AddFieldMappingsAt("content", textField)
+	for _, field := range facetFields {
+		document.AddFieldMappingsAt(field, keywordField, facetField(field+"_facet"))
+	}
+
+	dates := bleve.NewDocumentMapping()
+	for _, field := range dateFields {
+		for _, interval := range facetIntervals {
+			dates.AddFieldMappingsAt(dateFacet(field, interval), facetField(dateFacet(field, interval)))
+		}
+	}
+	document.AddSubDocumentMapping("dates", dates)
+
+	document.AddFieldMappingsAt("published_at", dateField)
+	document.AddFieldMappingsAt("modified_at", dateField)
+	document.AddFieldMappingsAt("fetched_at", dateField)
+	document.AddFieldMappingsAt("ingested_at", dateField)
+
+	indexMapping := bleve.NewIndexMapping()
+	indexMapping.AddCustomAnalyzer(keywordAnalyzer, map[string]any{
+		"type":          custom.Name,
+		"tokenizer":     single.Name,
+		"token_filters": []string{lowercase.Name},
+	})
+	indexMapping.DefaultAnalyzer = ru.AnalyzerName
+	indexMapping.DefaultMapping = document
+
+	return indexMapping
+}
+
+func (n *newsBleve) Index(ctx context.Context, news ...entity.News) error {
+	batch := n.index.NewBatch()
+	for _, item := range news {
+		dates := make(map[string]string, len(dateFields)*len(facetIntervals))
+		for _, field := range dateFields {
+			date := newsDate(&item, field).UTC()
+			for _, interval := range facetIntervals {
+				dates[dateFacet(field, interval)] = truncateDate(date, interval).Format(time.DateOnly)
+			}
+		}
+
+		err := batch.Index(item.UID, newsDocument{
+			Title:       item.Title,
+			Description: item.Description,
+			Content:     item.Content,
+			Source:      item.Source,
+			Authors:     item.Authors,
+			Tags:        item.Tags,
+			Categories:  item.Categories,
+			PublishedAt: item.PublishedAt,
+			ModifiedAt:  item.ModifiedAt,
+			FetchedAt:   item.FetchedAt,
+			IngestedAt:  item.IngestedAt,
+			Dates:       dates,
+		})
+		if err != nil {
+			return fmt.Errorf("batch.Index: %w", err)
+		}
+	}
+
+	err := n.index.Batch(batch)
+	if err != nil {
+		return fmt.Errorf("n.index.Batch: %w", err)
+	}
+
+	return nil
+}
+
+var bleveSortVariants = map[SortOption][]string{
+	SortPublishedAtDesc: {"-published_at"},
+	SortPublishedAtAsc:  {"published_at"},
+	SortRelevanceDesc:   {"-_score", "-published_at"},
+	SortRelevanceAsc:    {"_score", "-published_at"},
+	SortModifiedAtDesc:  {"-modified_at"},
+	SortModifiedAtAsc:   {"modified_at"},
+	SortFetchedAtDesc:   {"-fetched_at"},
+	SortFetchedAtAsc:    {"fetched_at"},
+	SortIngestedAtDesc:  {"-ingested_at"},
+	SortIngestedAtAsc:   {"ingested_at"},
+}
+
+func (n *newsBleve) Search(ctx context.Context, query Query, opts Options) ([]SearchHit, int, error) {
+	req := bleve.NewSearchRequestOptions(n.parseQuery(query), int(opts.Limit), int(opts.Skip), false)
+
+	sort, ok := bleveSortVariants[opts.Sort]
+	if !ok {
+		sort = bleveSortVariants[SortDefault]
+	}
+	req.SortBy(sort)
+	req.Fields = textFields
+	req.IncludeLocations = true
+
+	result, err := n.index.SearchInContext(ctx, req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("n.index.SearchInContext: %w", err)
+	}
+
+	hits := make([]SearchHit, len(result.Hits))
+	for i, hit := range result.Hits {
+		hits[i] = SearchHit{
+			UID:        hit.ID,
+			Score:      hit.Score,
+			Highlights: n.highlights(hit),
+		}
+	}
+
+	return hits, int(result.Total), nil
+}
+
+// facetFields are keyword fields of news counted by facets.
+var facetFields = []string{"source", "tags", "authors", "categories"}
+
+// facetIntervals are intervals of date facets.
+var facetIntervals = []DateInterval{IntervalDay, IntervalWeek, IntervalMonth, IntervalYear}
+
+// FACET_MAX_DATES limits number of intervals counted by date facet.
+const FACET_MAX_DATES = 10000
+
+// Facets counts values of news matched by query in the same way as facets of repo.
+func (n *newsBleve) Facets(ctx context.Context, query Query, opts FacetOptions) (*entity.Facets, error) {
+	if !opts.Interval.IsValid() {
+		opts.Interval = IntervalDefault
+	}
+
+	req := bleve.NewSearchRequestOptions(n.parseQuery(query), 0, 0, false)
+	for _, field := range facetFields {
+		req.AddFacet(field, bleve.NewFacetRequest(field+"_facet", int(opts.Limit)))
+	}
+	// dates are counted by the same field as date range filter
+	req.AddFacet("dates", bleve.NewFacetRequest("dates."+dateFacet(query.dateField(), opts.Interval), FACET_MAX_DATES))
+
+	result, err := n.index.SearchInContext(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("n.index.SearchInContext: %w", err)
+	}
+
+	values := func(field string) []entity.FacetValue {
+		values := make([]entity.FacetValue, 0)
+		if facet, ok := result.Facets[field]; ok {
+			for _, term := range facet.Terms.Terms() {
+				values = append(values, entity.FacetValue{Value: term.Term, Count: term.Count})
+			}
+		}
+		return values
+	}
+
+	facets := &entity.Facets{
+		Sources:    values("source"),
+		Tags:       values("tags"),
+		Authors:    values("authors"),
+		Categories: values("categories"),
+		Dates:      make([]entity.FacetDate, 0),
+	}
+
+	for _, value := range values("dates") {
+		date, err := time.Parse(time.DateOnly, value.Value)
+		if err != nil {
+			continue
+		}
+		facets.Dates = append(facets.Dates, entity.FacetDate{Date: date, Count: value.Count})
+	}
+
+	slices.SortFunc(facets.Dates, func(a, b entity.FacetDate) int {
+		return a.Date.Compare(b.Date)
+	})
+
+	return facets, nil
+}
+
+// truncateDate truncates UTC date to start of interval like $dateTrunc,
+// weeks start on Sunday.
+func truncateDate(date time.Time, interval DateInterval) time.Time {
+	year, month, day := date.Date()
+	switch interval {
+	case IntervalWeek:
+		return time.Date(year, month, day-int(date.Weekday()), 0, 0, 0, 0, time.UTC)
+	case IntervalMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	case IntervalYear:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Checkpoint returns last checkpoint of reindex or empty string if reindex is not started.
+func (n *newsBleve) Checkpoint(ctx context.Context) (string, error) {
+	checkpoint, err := n.index.GetInternal(checkpointKey)
+	if err != nil {
+		return "", fmt.Errorf("n.index.GetInternal: %w", err)
+	}
+
+	return string(checkpoint), nil
+}
+
+func (n *newsBleve) SetCheckpoint(ctx context.Context, checkpoint string) error {
+	if err := n.index.SetInternal(checkpointKey, []byte(checkpoint)); err != nil {
+		return fmt.Errorf("n.index.SetInternal: %w", err)
+	}
+
+	return nil
+}
+
+func (n *newsBleve) highlights(hit *search.DocumentMatch) *entity.Highlights {
+	if len(hit.Locations) == 0 {
+		return nil
+	}
+
+	fragments := func(field string) []string {
+		text, _ := hit.Fields[field].(string)
+		spans := make([]highlight.Span, 0)
+		for _, locations := range hit.Locations[field] {
+			for _, location := range locations {
+				spans = append(spans, highlight.Span{Start: int(location.Start), End: int(location.End)})
+			}
+		}
+
+		return highlight.Fragments(text, spans, n.highlight)
+	}
+
+	return &entity.Highlights{
+		Title:       fragments("title"),
+		Description: fragments("description"),
+		Content:     fragments("content"),
+	}
+}
+
+func (n *newsBleve) parseQuery(q Query) query.Query {
+	conjuncts := make([]query.Query, 0, 6)
+
+	if q.Expr != nil {
+		conjuncts = append(conjuncts, n.compileExpr(q.Expr))
+	} else if q.Text != "" {
+		field := querylang.FieldAny
+		if q.Title {
+			field = querylang.FieldTitle
+		}
+		conjuncts = append(conjuncts, n.compileTerm(&querylang.Term{Field: field, Value: q.Text}))
+	}
+
+	if len(q.Sources) > 0 {
+		sources := make([]query.Query, len(q.Sources))
+		for i, source := range q.Sources {
+			sources[i] = n.keywordQuery("source", source)
+		}
+		conjuncts = append(conjuncts, bleve.NewDisjunctionQuery(sources...))
+	}
+
+	for _, author := range q.Authors {
+		conjuncts = append(conjuncts, n.keywordQuery("authors", author))
+	}
+
+	for _, tag := range q.Tags {
+		conjuncts = append(conjuncts, n.keywordQuery("tags", tag))
+	}
+
+	if q.DateFrom != nil || q.DateTo != nil {
+		conjuncts = append(conjuncts, n.compileDateRange(&querylang.DateRange{From: q.DateFrom, To: q.DateTo}, q.dateField()))
+	}
+
+	if len(conjuncts) == 0 {
+		return bleve.NewMatchAllQuery()
+	}
+
+	return bleve.NewConjunctionQuery(conjuncts...)
+}
+
+func (n *newsBleve) compileExpr(expr querylang.Expr) query.Query {
+	switch e := expr.(type) {
+	case *querylang.And:
+		return bleve.NewConjunctionQuery(n.compileExprs(e.Exprs)...)
+	case *querylang.Or:
+		return bleve.NewDisjunctionQuery(n.compileExprs(e.Exprs)...)
+	case *querylang.Not:
+		return query.NewBooleanQuery(
+			[]query.Query{bleve.NewMatchAllQuery()},
+			nil,
+			[]query.Query{n.compileExpr(e.Expr)},
+		)
+	case *querylang.Term:
+		return n.compileTerm(e)
+	case *querylang.DateRange:
+		return n.compileDateRange(e, DatePublishedAt)
+	}
+
+	return bleve.NewMatchNoneQuery()
+}
+
+func (n *newsBleve) compileExprs(exprs []querylang.Expr) []query.Query {
+	result := make([]query.Query, len(exprs))
+	for i, expr := range exprs {
+		result[i] = n.compileExpr(expr)
+	}
+
+	return result
+}
+
+func (n *newsBleve) compileTerm(term *querylang.Term) query.Query {
+	if !term.Field.IsText() {
+		field := termFields[term.Field]
+		if term.Wildcard {
+			q := bleve.NewWildcardQuery(strings.ToLower(term.Value))
+			q.SetField(field)
+			return q
+		}
+
+		return n.keywordQuery(field, term.Value)
+	}
+
+	fields := textFields
+	if term.Field != querylang.FieldAny {
+		fields = []string{termFields[term.Field]}
+	}
+
+	queries := make([]query.Query, len(fields))
+	for i, field := range fields {
+		var q query.FieldableQuery
+		switch {
+		case term.Wildcard:
+			q = bleve.NewWildcardQuery(strings.ToLower(term.Value))
+		case term.Phrase:
+			q = bleve.NewMatchPhraseQuery(term.Value)
+		default:
+			q = bleve.NewMatchQuery(term.Value)
+		}
+		q.SetField(field)
+
+		// matches in title are more relevant
+		if field == "title" && len(fields) > 1 {
+			if b, ok := q.(query.BoostableQuery); ok {
+				b.SetBoost(2)
+			}
+		}
+
+		queries[i] = q
+	}
+
+	if len(queries) == 1 {
+		return queries[0]
+	}
+
+	return bleve.NewDisjunctionQuery(queries...)
+}
+
+func (n *newsBleve) keywordQuery(field string, value string) query.Query {
+	q := bleve.NewTermQuery(strings.ToLower(value))
+	q.SetField(field)
+	return q
+}
+
+func (n *newsBleve) compileDateRange(dateRange *querylang.DateRange, field DateField) query.Query {
+	var from, to time.Time
+	if dateRange.From != nil {
+		from = *dateRange.From
+	}
+
+	if dateRange.To != nil {
+		to = *dateRange.To
+	}
+
+	inclusiveFrom, inclusiveTo := true, false
+	q := bleve.NewDateRangeInclusiveQuery(from, to, &inclusiveFrom, &inclusiveTo)
+	q.SetField(string(field))
+	return q
+}
@@ -0,0 +1,153 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/aggregator/pkg/highlight"
+	"github.com/qsoulior/news/aggregator/pkg/querylang"
+)
+
+func newTestBleve(t *testing.T, news ...entity.News) Search {
+	t.Helper()
+	index, err := bleve.NewMemOnly(NewsBleveMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { index.Close() })
+
+	search := NewNewsBleve(index, highlight.Options{})
+	if err := search.Index(context.Background(), news...); err != nil {
+		t.Fatal(err)
+	}
+	return search
+}
+
+func testNews(uid string, link string, source string, tags []string, published time.Time) entity.News {
+	return entity.News{
+		NewsHead: entity.NewsHead{
+			UID:         uid,
+			Title:       "Вакцина от гриппа",
+			Source:      source,
+			PublishedAt: published,
+		},
+		Link: link,
+		Tags: tags,
+	}
+}
+
+func TestNewsBleveIndexByUID(t *testing.T) {
+	date := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	search := newTestBleve(t,
+		testNews("a", "https://ria.ru/a?utm_source=x", "ria", nil, date),
+		// the same news with another link replaces indexed one
+		testNews("a", "https://ria.ru/a", "ria", nil, date),
+	)
+
+	expr, _ := querylang.Parse("вакцина")
+	hits, count, err := search.Search(context.Background(), Query{Expr: expr}, Options{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 1 || len(hits) != 1 || hits[0].UID != "a" {
+		t.Errorf("Search = %d hits %+v, want one hit of uid a", count, hits)
+	}
+}
+
+func TestNewsBleveFacets(t *testing.T) {
+	search := newTestBleve(t,
+		testNews("a", "https://ria.ru/a", "ria", []string{"Спорт", "Футбол"}, time.Date(2024, 1, 6, 23, 0, 0, 0, time.UTC)),
+		testNews("b", "https://ria.ru/b", "ria", []string{"Спорт"}, time.Date(2024, 1, 7, 1, 0, 0, 0, time.UTC)),
+		testNews("c", "https://lenta.ru/c", "lenta", []string{"Спорт"}, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)),
+	)
+
+	expr, _ := querylang.Parse("вакцина")
+	facets, err := search.Facets(context.Background(), Query{Expr: expr}, FacetOptions{Limit: 10, Interval: IntervalWeek})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantValues := func(name string, got []entity.FacetValue, want ...entity.FacetValue) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("%s = %+v, want %+v", name, got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s = %+v, want %+v", name, got, want)
+				return
+			}
+		}
+	}
+
+	// values keep case like facets of repo
+	wantValues("sources", facets.Sources, entity.FacetValue{Value: "ria", Count: 2}, entity.FacetValue{Value: "lenta", Count: 1})
+	wantValues("tags", facets.Tags, entity.FacetValue{Value: "Спорт", Count: 3}, entity.FacetValue{Value: "Футбол", Count: 1})
+
+	// weeks start on Sunday
+	want := []entity.FacetDate{
+		{Date: time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), Count: 1},
+		{Date: time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC), Count: 1},
+		{Date: time.Date(2024, 1, 28, 0, 0, 0, 0, time.UTC), Count: 1},
+	}
+	if len(facets.Dates) != len(want) {
+		t.Fatalf("dates = %+v, want %+v", facets.Dates, want)
+	}
+	for i := range want {
+		if !facets.Dates[i].Date.Equal(want[i].Date) || facets.Dates[i].Count != want[i].Count {
+			t.Errorf("dates = %+v, want %+v", facets.Dates, want)
+			break
+		}
+	}
+}
+
+func TestNewsBleveCheckpoint(t *testing.T) {
+	search := newTestBleve(t)
+	ctx := context.Background()
+
+	checkpoint, err := search.Checkpoint(ctx)
+	if err != nil || checkpoint != "" {
+		t.Fatalf("Checkpoint = %q, %v, want empty", checkpoint, err)
+	}
+
+	if err := search.SetCheckpoint(ctx, "65a0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if checkpoint, _ := search.Checkpoint(ctx); checkpoint != "65a0" {
+		t.Errorf("Checkpoint = %q, want 65a0", checkpoint)
+	}
+}
+
+func TestNewsBleveFacetsDateField(t *testing.T) {
+	news := testNews("a", "https://ria.ru/a", "ria", nil, time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC))
+	news.ModifiedAt = time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	search := newTestBleve(t, news)
+
+	tests := []struct {
+		field DateField
+		want  time.Time
+	}{
+		{"", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{DatePublishedAt, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// dates are counted by the same field as date range filter
+		{DateModifiedAt, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	expr, _ := querylang.Parse("вакцина")
+	for _, tt := range tests {
+		query := Query{Expr: expr, DateField: tt.field}
+		facets, err := search.Facets(context.Background(), query, FacetOptions{Limit: 10, Interval: IntervalMonth})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(facets.Dates) != 1 || !facets.Dates[0].Date.Equal(tt.want) {
+			t.Errorf("dates by %q = %+v, want one date %s", tt.field, facets.Dates, tt.want)
+		}
+	}
+}
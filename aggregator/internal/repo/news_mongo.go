@@ -2,9 +2,12 @@ package repo
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/qsoulior/news/aggregator/entity"
 	"go.mongodb.org/mongo-driver/bson"
@@ -16,11 +19,15 @@ import (
 
 type newsMongo struct {
 	collection *mongo.Collection
+	revisions  *mongo.Collection
+	indexQueue *mongo.Collection
 }
 
 func NewNewsMongo(database *mongo.Database) News {
 	return &newsMongo{
 		collection: database.Collection("news"),
+		revisions:  database.Collection("news_revisions"),
+		indexQueue: database.Collection("news_index_queue"),
 	}
 }
 
@@ -32,10 +39,88 @@ func (n *newsMongo) Create(ctx context.Context, news entity.News) error {
 	return nil
 }
 
-func (n *newsMongo) ReplaceOrCreate(ctx context.Context, news entity.News) error {
+// REPLACE_MAX_ATTEMPTS limits attempts to store news changed concurrently.
+const REPLACE_MAX_ATTEMPTS = 3
+
+// errStale is returned if stored news is changed after it was read.
+var errStale = errors.New("stored news is changed concurrently")
+
+// ReplaceOrCreate stores news if it is new, its content differs from stored news
+// or it is modified later. Each change of content is kept in revision history,
+// original version is kept only after first change.
+// It reports whether news was stored.
+func (n *newsMongo) ReplaceOrCreate(ctx context.Context, news entity.News) (bool, error) {
+	for attempt := 1; ; attempt++ {
+		stored, err := n.replaceOrCreate(ctx, news)
+		if (errors.Is(err, errStale) || mongo.IsDuplicateKeyError(err)) && attempt < REPLACE_MAX_ATTEMPTS {
+			continue
+		}
+
+		return stored, err
+	}
+}
+
+// replaceOrCreate compares news with stored news outside of transaction,
+// transaction fails with errStale if stored news is changed meanwhile.
+func (n *newsMongo) replaceOrCreate(ctx context.Context, news entity.News) (bool, error) {
+	// news stored before identifiers were introduced get them by SetMissingUID
+	filter := bson.D{{Key: "uid", Value: news.UID}}
+
+	prev := new(entity.News)
+	err := n.collection.FindOne(ctx, filter).Decode(prev)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		news.Changed = false
+		if _, err := n.collection.InsertOne(ctx, news); err != nil {
+			return false, fmt.Errorf("n.collection.InsertOne: %w", err)
+		}
+		return true, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("n.collection.FindOne.Decode: %w", err)
+	}
+
+	// stored news is replaced only if it is not changed after it was read
+	objectID, err := primitive.ObjectIDFromHex(prev.ID)
+	if err != nil {
+		return false, fmt.Errorf("primitive.ObjectIDFromHex: %w", err)
+	}
+
+	replaceFilter := bson.D{
+		{Key: "_id", Value: objectID},
+		{Key: "modified_at", Value: timeFilter(prev.ModifiedAt)},
+		{Key: "fetched_at", Value: timeFilter(prev.FetchedAt)},
+	}
+
+	// news stored before modification time was introduced
+	if prev.ModifiedAt.Before(prev.PublishedAt) {
+		prev.ModifiedAt = prev.PublishedAt
+	}
+
+	replaced, changed := newsReplaced(prev, &news)
+	if !replaced {
+		return false, nil
+	}
+
+	// keep first publication and ingestion time
+	if !prev.PublishedAt.IsZero() && prev.PublishedAt.Before(news.PublishedAt) {
+		news.PublishedAt = prev.PublishedAt
+	}
+
+	if !prev.IngestedAt.IsZero() {
+		news.IngestedAt = prev.IngestedAt
+	}
+
+	news.Changed = prev.Changed || changed
+
+	var diff []entity.FieldDiff
+	if changed {
+		diff = diffNews(prev, &news)
+	}
+
 	session, err := n.collection.Database().Client().StartSession()
 	if err != nil {
-		return fmt.Errorf("client.StartSession: %w", err)
+		return false, fmt.Errorf("client.StartSession: %w", err)
 	}
 	defer session.EndSession(ctx)
 
@@ -43,33 +128,86 @@ func (n *newsMongo) ReplaceOrCreate(ctx context.Context, news entity.News) error
 	txnOptions := options.Transaction().SetWriteConcern(wc)
 
 	_, err = session.WithTransaction(ctx, func(ctx mongo.SessionContext) (any, error) {
-		resultNews := new(entity.News)
-
-		filter := bson.D{{Key: "link", Value: news.Link}}
-		err := n.collection.FindOne(ctx, filter).Decode(resultNews)
-		if err == mongo.ErrNoDocuments {
-			return n.collection.InsertOne(ctx, news)
+		result, err := n.collection.ReplaceOne(ctx, replaceFilter, news)
+		if err != nil {
+			return nil, fmt.Errorf("n.collection.ReplaceOne: %w", err)
 		}
 
-		if err != nil {
-			return nil, err
+		if result.MatchedCount == 0 {
+			return nil, errStale
 		}
 
-		if news.PublishedAt.After(resultNews.PublishedAt) {
-			filter := bson.D{{Key: "link", Value: resultNews.Link}}
-			return n.collection.ReplaceOne(ctx, filter, news)
+		if changed {
+			return nil, n.createRevision(ctx, prev, &news, diff)
 		}
 
 		return nil, nil
 	}, txnOptions)
 
 	if err != nil {
-		return fmt.Errorf("session.WithTransaction: %w", err)
+		return false, fmt.Errorf("session.WithTransaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// timeFilter matches time or missing field if time is zero.
+func timeFilter(t time.Time) any {
+	if t.IsZero() {
+		return bson.D{{Key: "$in", Value: bson.A{nil, t}}}
+	}
+
+	return t
+}
+
+// createRevision stores revisions of news changed from prev.
+func (n *newsMongo) createRevision(ctx context.Context, prev *entity.News, news *entity.News, diff []entity.FieldDiff) error {
+	last := new(entity.Revision)
+
+	filter := bson.D{{Key: "news_id", Value: prev.ID}}
+	opts := options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}})
+	err := n.revisions.FindOne(ctx, filter, opts).Decode(last)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		last = nil
+	} else if err != nil {
+		return fmt.Errorf("n.revisions.FindOne.Decode: %w", err)
+	}
+
+	revisions := nextRevisions(last, prev, news, diff)
+	documents := make([]any, len(revisions))
+	for i, revision := range revisions {
+		documents[i] = revision
+	}
+
+	if _, err := n.revisions.InsertMany(ctx, documents); err != nil {
+		return fmt.Errorf("n.revisions.InsertMany: %w", err)
 	}
 
 	return nil
 }
 
+func (n *newsMongo) GetRevisions(ctx context.Context, newsID string) ([]entity.Revision, error) {
+	filter := bson.D{{Key: "news_id", Value: newsID}}
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: 1}})
+
+	cursor, err := n.revisions.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("n.revisions.Find: %w", err)
+	}
+
+	revisions := make([]entity.Revision, 0)
+	if err = cursor.All(ctx, &revisions); err != nil {
+		return nil, fmt.Errorf("cursor.All: %w", err)
+	}
+
+	return revisions, nil
+}
+
+func isUID(id string) bool {
+	_, err := hex.DecodeString(id)
+	return err == nil && len(id) == 32
+}
+
 func (n *newsMongo) CreateMany(ctx context.Context, news []entity.News) error {
 	documents := make([]any, len(news))
 	for i, v := range news {
@@ -83,16 +221,18 @@ func (n *newsMongo) CreateMany(ctx context.Context, news []entity.News) error {
 	return nil
 }
 
+// GetByID finds news by ObjectID or by stable identifier derived from link.
 func (n *newsMongo) GetByID(ctx context.Context, id string) (*entity.News, error) {
-	objectID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
+	filter := bson.D{{Key: "uid", Value: id}}
+	if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+		filter = bson.D{{Key: "_id", Value: objectID}}
+	} else if !isUID(id) {
 		return nil, ErrInvalidID
 	}
 
 	news := new(entity.News)
 
-	filter := bson.D{{Key: "_id", Value: objectID}}
-	err = n.collection.FindOne(ctx, filter).Decode(news)
+	err := n.collection.FindOne(ctx, filter).Decode(news)
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, ErrNotFound
 	}
@@ -104,22 +244,316 @@ func (n *newsMongo) GetByID(ctx context.Context, id string) (*entity.News, error
 	return news, nil
 }
 
+func (n *newsMongo) GetByUIDs(ctx context.Context, uids []string) (map[string]entity.NewsHead, error) {
+	filter := bson.D{{Key: "uid", Value: bson.D{{Key: "$in", Value: uids}}}}
+	projection := bson.D{
+		{Key: "_id", Value: true},
+		{Key: "uid", Value: true},
+		{Key: "title", Value: true},
+		{Key: "description", Value: true},
+		{Key: "source", Value: true},
+		{Key: "published_at", Value: true},
+		{Key: "modified_at", Value: true},
+		{Key: "changed", Value: true},
+		{Key: "link", Value: true},
+	}
+
+	cursor, err := n.collection.Find(ctx, filter, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, fmt.Errorf("n.collection.Find: %w", err)
+	}
+
+	var results []entity.News
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("cursor.All: %w", err)
+	}
+
+	news := make(map[string]entity.NewsHead, len(results))
+	for _, result := range results {
+		news[result.UID] = result.NewsHead
+	}
+
+	return news, nil
+}
+
+// ForEach calls fn for news in order of ID starting after news with given ID.
+func (n *newsMongo) ForEach(ctx context.Context, after string, fn func(news entity.News) error) error {
+	filter := bson.D{}
+	if after != "" {
+		objectID, err := primitive.ObjectIDFromHex(after)
+		if err != nil {
+			return fmt.Errorf("primitive.ObjectIDFromHex: %w", err)
+		}
+		filter = bson.D{{Key: "_id", Value: bson.D{{Key: "$gt", Value: objectID}}}}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := n.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("n.collection.Find: %w", err)
+	}
+
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var news entity.News
+		if err := cursor.Decode(&news); err != nil {
+			return fmt.Errorf("cursor.Decode: %w", err)
+		}
+
+		if err := fn(news); err != nil {
+			return err
+		}
+	}
+
+	if err = cursor.Err(); err != nil {
+		return fmt.Errorf("cursor.Err: %w", err)
+	}
+
+	return nil
+}
+
+// SetMissingUID sets identifiers computed from links of news without them.
+// News with identifier of another stored news are duplicates and are skipped.
+// It returns number of updated news.
+func (n *newsMongo) SetMissingUID(ctx context.Context, uid func(link string) string) (int, error) {
+	filter := bson.D{{Key: "uid", Value: bson.D{{Key: "$not", Value: bson.D{{Key: "$type", Value: "string"}}}}}}
+	opts := options.Find().SetProjection(bson.D{{Key: "link", Value: 1}})
+
+	cursor, err := n.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, fmt.Errorf("n.collection.Find: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	count := 0
+	for cursor.Next(ctx) {
+		var news struct {
+			ID   primitive.ObjectID `bson:"_id"`
+			Link string             `bson:"link"`
+		}
+		if err := cursor.Decode(&news); err != nil {
+			return count, fmt.Errorf("cursor.Decode: %w", err)
+		}
+
+		update := bson.D{{Key: "$set", Value: bson.D{{Key: "uid", Value: uid(news.Link)}}}}
+		_, err := n.collection.UpdateByID(ctx, news.ID, update)
+		if mongo.IsDuplicateKeyError(err) {
+			continue
+		}
+
+		if err != nil {
+			return count, fmt.Errorf("n.collection.UpdateByID: %w", err)
+		}
+		count++
+	}
+
+	if err = cursor.Err(); err != nil {
+		return count, fmt.Errorf("cursor.Err: %w", err)
+	}
+
+	return count, nil
+}
+
+// SetMissingTimestamps sets timestamps of news stored before they were introduced:
+// modification time by publication time, ingestion time by time of identifier
+// and fetch time by ingestion time. It returns number of updated news.
+func (n *newsMongo) SetMissingTimestamps(ctx context.Context) (int, error) {
+	missing := func(field string) bson.D {
+		return bson.D{{Key: field, Value: bson.D{{Key: "$exists", Value: false}}}}
+	}
+
+	set := func(field string, value any) mongo.Pipeline {
+		return mongo.Pipeline{bson.D{{Key: "$set", Value: bson.D{{Key: field, Value: value}}}}}
+	}
+
+	// fetch time is set after ingestion time it is copied from
+	updates := []struct {
+		field string
+		value any
+	}{
+		{"modified_at", "$published_at"},
+		{"ingested_at", bson.D{{Key: "$toDate", Value: "$_id"}}},
+		{"fetched_at", "$ingested_at"},
+	}
+
+	count := 0
+	for _, update := range updates {
+		result, err := n.collection.UpdateMany(ctx, missing(update.field), set(update.field, update.value))
+		if err != nil {
+			return count, fmt.Errorf("n.collection.UpdateMany: %w", err)
+		}
+		count += int(result.ModifiedCount)
+	}
+
+	return count, nil
+}
+
+// QueueIndex adds news with given identifiers to queue of news to be indexed
+// again. Time of queuing is updated for news already in queue.
+func (n *newsMongo) QueueIndex(ctx context.Context, uids ...string) error {
+	now := time.Now().UTC()
+	for _, uid := range uids {
+		filter := bson.D{{Key: "_id", Value: uid}}
+		update := bson.D{{Key: "$set", Value: bson.D{{Key: "queued_at", Value: now}}}}
+		_, err := n.indexQueue.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+		if err != nil {
+			return fmt.Errorf("n.indexQueue.UpdateOne: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetIndexQueue returns identifiers of first queued news and stored news
+// with them. Identifiers of deleted news have no stored news.
+func (n *newsMongo) GetIndexQueue(ctx context.Context, limit int) ([]string, []entity.News, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "queued_at", Value: 1}}).SetLimit(int64(limit))
+	cursor, err := n.indexQueue.Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("n.indexQueue.Find: %w", err)
+	}
+
+	var queued []struct {
+		UID string `bson:"_id"`
+	}
+	if err = cursor.All(ctx, &queued); err != nil {
+		return nil, nil, fmt.Errorf("cursor.All: %w", err)
+	}
+
+	if len(queued) == 0 {
+		return nil, nil, nil
+	}
+
+	uids := make([]string, len(queued))
+	for i, item := range queued {
+		uids[i] = item.UID
+	}
+
+	filter := bson.D{{Key: "uid", Value: bson.D{{Key: "$in", Value: uids}}}}
+	cursor, err = n.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("n.collection.Find: %w", err)
+	}
+
+	var news []entity.News
+	if err = cursor.All(ctx, &news); err != nil {
+		return nil, nil, fmt.Errorf("cursor.All: %w", err)
+	}
+
+	return uids, news, nil
+}
+
+// DeleteIndexQueue removes news with given identifiers queued before given time,
+// news queued again after it stay in queue.
+func (n *newsMongo) DeleteIndexQueue(ctx context.Context, uids []string, before time.Time) error {
+	filter := bson.D{
+		{Key: "_id", Value: bson.D{{Key: "$in", Value: uids}}},
+		{Key: "queued_at", Value: bson.D{{Key: "$lt", Value: before}}},
+	}
+
+	if _, err := n.indexQueue.DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("n.indexQueue.DeleteMany: %w", err)
+	}
+
+	return nil
+}
+
+func (n *newsMongo) GetRelated(ctx context.Context, query RelatedQuery) ([]entity.News, error) {
+	objectID, err := primitive.ObjectIDFromHex(query.ExcludeID)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	conds := make(bson.A, 0, 3)
+	if len(query.Tags) > 0 {
+		conds = append(conds, bson.D{{Key: "tags", Value: bson.D{{Key: "$in", Value: query.Tags}}}})
+	}
+
+	if len(query.Keywords) > 0 {
+		keywords := make([]string, len(query.Keywords))
+		for i, keyword := range query.Keywords {
+			keywords[i] = regexp.QuoteMeta(keyword)
+		}
+
+		regex := primitive.Regex{Pattern: strings.Join(keywords, "|"), Options: "i"}
+		conds = append(conds,
+			bson.D{{Key: "title", Value: regex}},
+			bson.D{{Key: "description", Value: regex}},
+		)
+	}
+
+	if len(conds) == 0 {
+		return make([]entity.News, 0), nil
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: bson.D{{Key: "$ne", Value: objectID}}},
+		{Key: "published_at", Value: bson.D{
+			{Key: "$gte", Value: query.DateFrom},
+			{Key: "$lt", Value: query.DateTo},
+		}},
+		{Key: "$or", Value: conds},
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "published_at", Value: -1}}).
+		SetLimit(int64(query.Limit))
+
+	cursor, err := n.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("n.collection.Find: %w", err)
+	}
+
+	news := make([]entity.News, 0)
+	if err = cursor.All(ctx, &news); err != nil {
+		return nil, fmt.Errorf("cursor.All: %w", err)
+	}
+
+	return news, nil
+}
+
 var sortVariants = map[SortOption]bson.D{
 	SortPublishedAtDesc: {{Key: "published_at", Value: -1}},
 	SortPublishedAtAsc:  {{Key: "published_at", Value: 1}},
 	SortRelevanceDesc:   {{Key: "score", Value: -1}},
 	SortRelevanceAsc:    {{Key: "score", Value: 1}},
+	SortModifiedAtDesc:  {{Key: "modified_at", Value: -1}},
+	SortModifiedAtAsc:   {{Key: "modified_at", Value: 1}},
+	SortFetchedAtDesc:   {{Key: "fetched_at", Value: -1}},
+	SortFetchedAtAsc:    {{Key: "fetched_at", Value: 1}},
+	SortIngestedAtDesc:  {{Key: "ingested_at", Value: -1}},
+	SortIngestedAtAsc:   {{Key: "ingested_at", Value: 1}},
 }
 
-func (n *newsMongo) parseQuery(query Query) bson.D {
-	doc := make(bson.D, 0, 6)
-	if query.Text != "" {
+// parseQuery builds match document and reports whether it uses $text search.
+func (n *newsMongo) parseQuery(query Query) (bson.D, bool) {
+	doc := make(bson.D, 0, 7)
+	textSearch := false
+
+	if query.Expr != nil {
+		search, rest := n.splitText(query.Expr)
+		if search != "" {
+			textSearch = true
+			doc = append(doc, bson.E{
+				Key:   "$text",
+				Value: bson.D{{Key: "$search", Value: search}},
+			})
+		}
+
+		if len(rest) > 0 {
+			doc = append(doc, bson.E{
+				Key:   "$and",
+				Value: n.compileExprs(rest),
+			})
+		}
+	} else if query.Text != "" {
 		if query.Title {
 			doc = append(doc, bson.E{
 				Key:   "title",
 				Value: primitive.Regex{Pattern: regexp.QuoteMeta(query.Text), Options: "i"},
 			})
 		} else {
+			textSearch = true
 			doc = append(doc, bson.E{
 				Key:   "$text",
 				Value: bson.D{{Key: "$search", Value: query.Text}},
@@ -169,12 +603,12 @@ func (n *newsMongo) parseQuery(query Query) bson.D {
 
 	if len(dateCond) > 0 {
 		doc = append(doc, bson.E{
-			Key:   "published_at",
+			Key:   string(query.dateField()),
 			Value: dateCond,
 		})
 	}
 
-	return doc
+	return doc, textSearch
 }
 
 func (n *newsMongo) parseOptions(opts Options) mongo.Pipeline {
@@ -232,11 +666,17 @@ func (n *newsMongo) GetByQuery(ctx context.Context, query Query, opts Options) (
 	pipeline := make(mongo.Pipeline, 0, 6)
 
 	// match stage
+	match, textSearch := n.parseQuery(query)
 	pipeline = append(pipeline, bson.D{{
 		Key:   "$match",
-		Value: n.parseQuery(query),
+		Value: match,
 	}})
 
+	// relevance requires text score metadata
+	if opts.Sort.IsRelevance() && !textSearch {
+		opts.Sort = SortDefault
+	}
+
 	pipeline = append(pipeline, n.parseOptions(opts)...)
 
 	// project stage
@@ -245,10 +685,13 @@ func (n *newsMongo) GetByQuery(ctx context.Context, query Query, opts Options) (
 		Value: bson.D{
 			{Key: "results", Value: bson.D{
 				{Key: "_id", Value: true},
+				{Key: "uid", Value: true},
 				{Key: "title", Value: true},
 				{Key: "description", Value: true},
 				{Key: "source", Value: true},
 				{Key: "published_at", Value: true},
+				{Key: "modified_at", Value: true},
+				{Key: "changed", Value: true},
 			}},
 			{Key: "total_count", Value: "$total_results.count"},
 		},
@@ -282,3 +725,83 @@ func (n *newsMongo) GetByQuery(ctx context.Context, query Query, opts Options) (
 
 	return val.Results, val.TotalCount, nil
 }
+
+func (n *newsMongo) facetValues(field string, unwind bool, limit uint) bson.A {
+	stages := make(bson.A, 0, 4)
+	if unwind {
+		stages = append(stages, bson.D{{Key: "$unwind", Value: "$" + field}})
+	}
+
+	return append(stages,
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$" + field},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.D{
+			{Key: "count", Value: -1},
+			{Key: "_id", Value: 1},
+		}}},
+		bson.D{{Key: "$limit", Value: limit}},
+	)
+}
+
+// facetDates counts news by intervals of the same date field as date range filter.
+func (n *newsMongo) facetDates(field DateField, interval DateInterval) bson.A {
+	return bson.A{
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$dateTrunc", Value: bson.D{
+				{Key: "date", Value: "$" + string(field)},
+				{Key: "unit", Value: string(interval)},
+			}}}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+}
+
+func (n *newsMongo) GetFacets(ctx context.Context, query Query, opts FacetOptions) (*entity.Facets, error) {
+	if !opts.Interval.IsValid() {
+		opts.Interval = IntervalDefault
+	}
+
+	match, _ := n.parseQuery(query)
+	pipeline := mongo.Pipeline{
+		// match stage
+		bson.D{{
+			Key:   "$match",
+			Value: match,
+		}},
+		// facet stage
+		bson.D{{
+			Key: "$facet",
+			Value: bson.D{
+				{Key: "sources", Value: n.facetValues("source", false, opts.Limit)},
+				{Key: "tags", Value: n.facetValues("tags", true, opts.Limit)},
+				{Key: "authors", Value: n.facetValues("authors", true, opts.Limit)},
+				{Key: "categories", Value: n.facetValues("categories", true, opts.Limit)},
+				{Key: "dates", Value: n.facetDates(query.dateField(), opts.Interval)},
+			},
+		}},
+	}
+
+	cursor, err := n.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("n.collection.Aggregate: %w", err)
+	}
+
+	facets := new(entity.Facets)
+
+	defer cursor.Close(ctx)
+	if cursor.Next(ctx) {
+		err = cursor.Decode(facets)
+		if err != nil {
+			return nil, fmt.Errorf("cursor.Decode: %w", err)
+		}
+	}
+
+	if err = cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor.Err: %w", err)
+	}
+
+	return facets, nil
+}
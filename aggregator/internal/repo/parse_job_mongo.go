@@ -0,0 +1,167 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qsoulior/news/aggregator/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type parseJobMongo struct {
+	collection *mongo.Collection
+}
+
+func NewParseJobMongo(database *mongo.Database) ParseJob {
+	return &parseJobMongo{
+		collection: database.Collection("parse_jobs"),
+	}
+}
+
+func (p *parseJobMongo) Create(ctx context.Context, job entity.ParseJob) error {
+	_, err := p.collection.InsertOne(ctx, job)
+	if err != nil {
+		return fmt.Errorf("p.collection.InsertOne: %w", err)
+	}
+	return nil
+}
+
+func (p *parseJobMongo) GetByID(ctx context.Context, id string) (*entity.ParseJob, error) {
+	job := new(entity.ParseJob)
+
+	filter := bson.D{{Key: "_id", Value: id}}
+	err := p.collection.FindOne(ctx, filter).Decode(job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("p.collection.FindOne.Decode: %w", err)
+	}
+
+	return job, nil
+}
+
+func (p *parseJobMongo) Delete(ctx context.Context, id string) error {
+	filter := bson.D{{Key: "_id", Value: id}}
+	_, err := p.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("p.collection.DeleteOne: %w", err)
+	}
+	return nil
+}
+
+func (p *parseJobMongo) update(ctx context.Context, id string, update any) error {
+	filter := bson.D{{Key: "_id", Value: id}}
+	result, err := p.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("p.collection.UpdateOne: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (p *parseJobMongo) SetState(ctx context.Context, id string, state entity.ParseJobState, reason string) error {
+	return p.update(ctx, id, bson.D{{Key: "$set", Value: bson.D{
+		{Key: "state", Value: state},
+		{Key: "error", Value: reason},
+		{Key: "updated_at", Value: time.Now().UTC()},
+	}}})
+}
+
+// UpdateSource sets progress of source keeping its start time and number of new news.
+// State of job is derived from states of its sources in the same update,
+// so concurrent progress of sources does not overwrite it. Updated job is returned.
+func (p *parseJobMongo) UpdateSource(ctx context.Context, id string, source string, progress entity.ParseJobSource) (*entity.ParseJob, error) {
+	prefix := "sources." + source + "."
+	set := bson.D{
+		{Key: prefix + "state", Value: progress.State},
+		{Key: prefix + "count", Value: progress.Count},
+		{Key: prefix + "error", Value: bson.D{{Key: "$literal", Value: progress.Error}}},
+		{Key: prefix + "new", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$" + prefix + "new", 0}}}},
+		{Key: "updated_at", Value: time.Now().UTC()},
+	}
+
+	if progress.StartedAt != nil {
+		set = append(set, bson.E{
+			Key:   prefix + "started_at",
+			Value: bson.D{{Key: "$ifNull", Value: bson.A{"$" + prefix + "started_at", *progress.StartedAt}}},
+		})
+	}
+
+	if progress.FinishedAt != nil {
+		set = append(set, bson.E{Key: prefix + "finished_at", Value: *progress.FinishedAt})
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: set}},
+		{{Key: "$set", Value: bson.D{{Key: "_state", Value: jobState()}}}},
+		// error of job is kept while its state is not changed
+		{{Key: "$set", Value: bson.D{
+			{Key: "error", Value: bson.D{{Key: "$cond", Value: bson.A{
+				bson.D{{Key: "$eq", Value: bson.A{"$_state", "$state"}}}, "$error", "",
+			}}}},
+			{Key: "state", Value: "$_state"},
+		}}},
+		{{Key: "$unset", Value: "_state"}},
+	}
+
+	job := new(entity.ParseJob)
+	filter := bson.D{{Key: "_id", Value: id}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := p.collection.FindOneAndUpdate(ctx, filter, pipeline, opts).Decode(job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("p.collection.FindOneAndUpdate.Decode: %w", err)
+	}
+
+	return job, nil
+}
+
+// jobState returns expression of state of job derived from states of its sources.
+// Job is queued while all sources are queued, it is done if all sources are
+// finished and one of them is done, it is failed if all sources failed.
+func jobState() bson.D {
+	states := bson.D{{Key: "$map", Value: bson.D{
+		{Key: "input", Value: bson.D{{Key: "$objectToArray", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$sources", bson.D{}}}}}}},
+		{Key: "in", Value: "$$this.v.state"},
+	}}}
+
+	// all returns expression reporting whether all states are in given ones
+	all := func(values ...entity.ParseJobState) bson.D {
+		return bson.D{{Key: "$allElementsTrue", Value: bson.A{bson.D{{Key: "$map", Value: bson.D{
+			{Key: "input", Value: "$$states"},
+			{Key: "in", Value: bson.D{{Key: "$in", Value: bson.A{"$$this", values}}}},
+		}}}}}}
+	}
+
+	return bson.D{{Key: "$let", Value: bson.D{
+		{Key: "vars", Value: bson.D{{Key: "states", Value: states}}},
+		{Key: "in", Value: bson.D{{Key: "$switch", Value: bson.D{
+			{Key: "branches", Value: bson.A{
+				bson.D{{Key: "case", Value: all(entity.ParseJobQueued)}, {Key: "then", Value: entity.ParseJobQueued}},
+				bson.D{{Key: "case", Value: all(entity.ParseJobFailed)}, {Key: "then", Value: entity.ParseJobFailed}},
+				bson.D{{Key: "case", Value: all(entity.ParseJobDone, entity.ParseJobFailed)}, {Key: "then", Value: entity.ParseJobDone}},
+			}},
+			{Key: "default", Value: entity.ParseJobRunning},
+		}}}},
+	}}}
+}
+
+func (p *parseJobMongo) IncrementNew(ctx context.Context, id string, source string) error {
+	return p.update(ctx, id, bson.D{
+		{Key: "$inc", Value: bson.D{{Key: "sources." + source + ".new", Value: 1}}},
+		{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now().UTC()}}},
+	})
+}
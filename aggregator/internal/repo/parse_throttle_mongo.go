@@ -0,0 +1,132 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type parseThrottleMongo struct {
+	cooldowns *mongo.Collection
+	budgets   *mongo.Collection
+}
+
+func NewParseThrottleMongo(database *mongo.Database) ParseThrottle {
+	return &parseThrottleMongo{
+		cooldowns: database.Collection("parse_cooldowns"),
+		budgets:   database.Collection("parse_budgets"),
+	}
+}
+
+// PARSE_ACQUIRE_MAX_ATTEMPTS limits attempts to reserve key released concurrently.
+const PARSE_ACQUIRE_MAX_ATTEMPTS = 3
+
+// Acquire reserves key for job until given time. If key is reserved by another
+// job and reservation is not expired, ID of that job is returned.
+func (p *parseThrottleMongo) Acquire(ctx context.Context, key string, jobID string, until time.Time) (string, bool, error) {
+	for range PARSE_ACQUIRE_MAX_ATTEMPTS {
+		holder, ok, err := p.acquire(ctx, key, jobID, until)
+		if err != nil || ok || holder != "" {
+			return holder, ok, err
+		}
+		// reservation is released concurrently, key is reserved again
+	}
+
+	return "", false, nil
+}
+
+func (p *parseThrottleMongo) acquire(ctx context.Context, key string, jobID string, until time.Time) (string, bool, error) {
+	// expired reservation is replaced, active one causes duplicate key error on upsert
+	filter := bson.D{
+		{Key: "_id", Value: key},
+		{Key: "expires_at", Value: bson.D{{Key: "$lte", Value: time.Now().UTC()}}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "job_id", Value: jobID},
+		{Key: "expires_at", Value: until},
+	}}}
+
+	_, err := p.cooldowns.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err == nil {
+		return jobID, true, nil
+	}
+
+	if !mongo.IsDuplicateKeyError(err) {
+		return "", false, fmt.Errorf("p.cooldowns.UpdateOne: %w", err)
+	}
+
+	var reservation struct {
+		JobID string `bson:"job_id"`
+	}
+
+	err = p.cooldowns.FindOne(ctx, bson.D{{Key: "_id", Value: key}}).Decode(&reservation)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", false, fmt.Errorf("p.cooldowns.FindOne.Decode: %w", err)
+	}
+
+	return reservation.JobID, false, nil
+}
+
+func (p *parseThrottleMongo) Release(ctx context.Context, key string, jobID string) error {
+	filter := bson.D{{Key: "_id", Value: key}, {Key: "job_id", Value: jobID}}
+	_, err := p.cooldowns.DeleteOne(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("p.cooldowns.DeleteOne: %w", err)
+	}
+	return nil
+}
+
+// Spend increments number of requests sent to source within window
+// and returns incremented number.
+func (p *parseThrottleMongo) Spend(ctx context.Context, source string, window time.Time, period time.Duration) (int, error) {
+	filter := bson.D{{Key: "_id", Value: budgetID(source, window)}}
+	update := bson.D{
+		{Key: "$inc", Value: bson.D{{Key: "count", Value: 1}}},
+		{Key: "$setOnInsert", Value: bson.D{
+			{Key: "source", Value: source},
+			{Key: "expires_at", Value: window.Add(2 * period)},
+		}},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var budget struct {
+		Count int `bson:"count"`
+	}
+
+	err := p.budgets.FindOneAndUpdate(ctx, filter, update, opts).Decode(&budget)
+	if err != nil {
+		return 0, fmt.Errorf("p.budgets.FindOneAndUpdate.Decode: %w", err)
+	}
+
+	return budget.Count, nil
+}
+
+// Refund returns request to budget of source within window if it is not sent.
+func (p *parseThrottleMongo) Refund(ctx context.Context, source string, window time.Time) error {
+	filter := bson.D{
+		{Key: "_id", Value: budgetID(source, window)},
+		{Key: "count", Value: bson.D{{Key: "$gt", Value: 0}}},
+	}
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "count", Value: -1}}}}
+
+	_, err := p.budgets.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("p.budgets.UpdateOne: %w", err)
+	}
+
+	return nil
+}
+
+func budgetID(source string, window time.Time) string {
+	return fmt.Sprintf("%s:%d", source, window.Unix())
+}
@@ -0,0 +1,148 @@
+package repo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/qsoulior/news/aggregator/pkg/querylang"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var textFields = []string{"title", "description", "content"}
+
+var termFields = map[querylang.Field]string{
+	querylang.FieldTitle:       "title",
+	querylang.FieldDescription: "description",
+	querylang.FieldContent:     "content",
+	querylang.FieldSource:      "source",
+	querylang.FieldAuthor:      "authors",
+	querylang.FieldTag:         "tags",
+	querylang.FieldCategory:    "categories",
+}
+
+// splitText extracts top-level text terms that can be served by $text index.
+// It returns $text search string and the remaining expressions.
+// $text joins terms by OR and phrases by AND, so several positive terms
+// are passed as phrases to keep AND of query.
+func (n *newsMongo) splitText(expr querylang.Expr) (string, []querylang.Expr) {
+	exprs := []querylang.Expr{expr}
+	if and, ok := expr.(*querylang.And); ok {
+		exprs = and.Exprs
+	}
+
+	type textTerm struct {
+		*querylang.Term
+		negated bool
+	}
+
+	terms := make([]textTerm, 0, len(exprs))
+	rest := make([]querylang.Expr, 0, len(exprs))
+	positive := 0
+
+	for _, item := range exprs {
+		negated := false
+		term, ok := item.(*querylang.Term)
+		if not, isNot := item.(*querylang.Not); isNot {
+			negated = true
+			term, ok = not.Expr.(*querylang.Term)
+		}
+
+		if !ok || term.Field != querylang.FieldAny || term.Wildcard {
+			rest = append(rest, item)
+			continue
+		}
+
+		terms = append(terms, textTerm{term, negated})
+		if !negated {
+			positive++
+		}
+	}
+
+	// $text search with negations only matches nothing
+	if positive == 0 {
+		return "", exprs
+	}
+
+	search := make([]string, len(terms))
+	for i, term := range terms {
+		value := term.Value
+		if term.Phrase || (positive > 1 && !term.negated) {
+			value = `"` + strings.ReplaceAll(value, `"`, ``) + `"`
+		}
+
+		if term.negated {
+			value = "-" + value
+		}
+		search[i] = value
+	}
+
+	return strings.Join(search, " "), rest
+}
+
+func (n *newsMongo) compileExpr(expr querylang.Expr) bson.D {
+	switch e := expr.(type) {
+	case *querylang.And:
+		return bson.D{{Key: "$and", Value: n.compileExprs(e.Exprs)}}
+	case *querylang.Or:
+		return bson.D{{Key: "$or", Value: n.compileExprs(e.Exprs)}}
+	case *querylang.Not:
+		return bson.D{{Key: "$nor", Value: bson.A{n.compileExpr(e.Expr)}}}
+	case *querylang.Term:
+		return n.compileTerm(e)
+	case *querylang.DateRange:
+		return n.compileDateRange(e)
+	}
+
+	return bson.D{}
+}
+
+func (n *newsMongo) compileExprs(exprs []querylang.Expr) bson.A {
+	result := make(bson.A, len(exprs))
+	for i, expr := range exprs {
+		result[i] = n.compileExpr(expr)
+	}
+
+	return result
+}
+
+func (n *newsMongo) compileTerm(term *querylang.Term) bson.D {
+	pattern := regexp.QuoteMeta(term.Value)
+	if term.Wildcard {
+		pattern = strings.NewReplacer(`\*`, `\S*`, `\?`, `\S`).Replace(pattern)
+	}
+
+	if term.Field == querylang.FieldAny {
+		regex := primitive.Regex{Pattern: pattern, Options: "i"}
+		conds := make(bson.A, len(textFields))
+		for i, field := range textFields {
+			conds[i] = bson.D{{Key: field, Value: regex}}
+		}
+
+		return bson.D{{Key: "$or", Value: conds}}
+	}
+
+	// exact match for keyword fields
+	if !term.Field.IsText() {
+		pattern = fmt.Sprintf("^%s$", pattern)
+	}
+
+	return bson.D{{
+		Key:   termFields[term.Field],
+		Value: primitive.Regex{Pattern: pattern, Options: "i"},
+	}}
+}
+
+func (n *newsMongo) compileDateRange(dateRange *querylang.DateRange) bson.D {
+	cond := make(bson.D, 0, 2)
+	if dateRange.From != nil {
+		cond = append(cond, bson.E{Key: "$gte", Value: *dateRange.From})
+	}
+
+	if dateRange.To != nil {
+		cond = append(cond, bson.E{Key: "$lt", Value: *dateRange.To})
+	}
+
+	return bson.D{{Key: "published_at", Value: cond}}
+}
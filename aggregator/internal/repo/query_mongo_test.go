@@ -0,0 +1,42 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/qsoulior/news/aggregator/pkg/querylang"
+)
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		input  string
+		search string
+		rest   int
+	}{
+		{"a", "a", 0},
+		{"a b", `"a" "b"`, 0},
+		{"a AND b", `"a" "b"`, 0},
+		{`a "b c"`, `"a" "b c"`, 0},
+		{"a -b", "a -b", 0},
+		{`a -"b c"`, `a -"b c"`, 0},
+		{"a b -c", `"a" "b" -c`, 0},
+		{"a title:b", "a", 1},
+		{"a b*", "a", 1},
+		{"-a", "", 1},
+		{"a OR b", "", 1},
+	}
+
+	n := &newsMongo{}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			expr, err := querylang.Parse(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			search, rest := n.splitText(expr)
+			if search != tt.search || len(rest) != tt.rest {
+				t.Errorf("splitText(%q) = %q, %d rest, want %q, %d rest", tt.input, search, len(rest), tt.search, tt.rest)
+			}
+		})
+	}
+}
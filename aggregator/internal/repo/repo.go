@@ -5,24 +5,103 @@ import (
 	"time"
 
 	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/aggregator/pkg/querylang"
 )
 
 type News interface {
 	Create(ctx context.Context, news entity.News) error
-	ReplaceOrCreate(ctx context.Context, news entity.News) error
+	ReplaceOrCreate(ctx context.Context, news entity.News) (bool, error)
 	CreateMany(ctx context.Context, news []entity.News) error
 	GetByID(ctx context.Context, id string) (*entity.News, error)
+	GetByUIDs(ctx context.Context, uids []string) (map[string]entity.NewsHead, error)
+	ForEach(ctx context.Context, after string, fn func(news entity.News) error) error
+	SetMissingUID(ctx context.Context, uid func(link string) string) (int, error)
+	SetMissingTimestamps(ctx context.Context) (int, error)
+	QueueIndex(ctx context.Context, uids ...string) error
+	GetIndexQueue(ctx context.Context, limit int) ([]string, []entity.News, error)
+	DeleteIndexQueue(ctx context.Context, uids []string, before time.Time) error
+	GetRelated(ctx context.Context, query RelatedQuery) ([]entity.News, error)
 	GetByQuery(ctx context.Context, query Query, opts Options) ([]entity.NewsHead, int, error)
+	GetFacets(ctx context.Context, query Query, opts FacetOptions) (*entity.Facets, error)
+	GetRevisions(ctx context.Context, newsID string) ([]entity.Revision, error)
+}
+
+type ParseJob interface {
+	Create(ctx context.Context, job entity.ParseJob) error
+	GetByID(ctx context.Context, id string) (*entity.ParseJob, error)
+	Delete(ctx context.Context, id string) error
+	SetState(ctx context.Context, id string, state entity.ParseJobState, reason string) error
+	UpdateSource(ctx context.Context, id string, source string, progress entity.ParseJobSource) (*entity.ParseJob, error)
+	IncrementNew(ctx context.Context, id string, source string) error
+}
+
+type ParseThrottle interface {
+	Acquire(ctx context.Context, key string, jobID string, until time.Time) (string, bool, error)
+	Release(ctx context.Context, key string, jobID string) error
+	Spend(ctx context.Context, source string, window time.Time, period time.Duration) (int, error)
+	Refund(ctx context.Context, source string, window time.Time) error
+}
+
+type Search interface {
+	Index(ctx context.Context, news ...entity.News) error
+	Search(ctx context.Context, query Query, opts Options) ([]SearchHit, int, error)
+	Facets(ctx context.Context, query Query, opts FacetOptions) (*entity.Facets, error)
+	Checkpoint(ctx context.Context) (string, error)
+	SetCheckpoint(ctx context.Context, checkpoint string) error
+}
+
+type SearchHit struct {
+	UID        string
+	Score      float64
+	Highlights *entity.Highlights
 }
 
 type Query struct {
-	Text     string
-	Title    bool
-	Sources  []string
-	Authors  []string
-	Tags     []string
-	DateFrom *time.Time
-	DateTo   *time.Time
+	Text      string
+	Expr      querylang.Expr
+	Title     bool
+	Sources   []string
+	Authors   []string
+	Tags      []string
+	DateField DateField
+	DateFrom  *time.Time
+	DateTo    *time.Time
+}
+
+// DateField is a news timestamp used by date range filter.
+type DateField string
+
+const (
+	DatePublishedAt  DateField = "published_at"
+	DateModifiedAt   DateField = "modified_at"
+	DateFetchedAt    DateField = "fetched_at"
+	DateIngestedAt   DateField = "ingested_at"
+	DateFieldDefault           = DatePublishedAt
+)
+
+func (d DateField) IsValid() bool {
+	return d == DatePublishedAt || d == DateModifiedAt || d == DateFetchedAt || d == DateIngestedAt
+}
+
+// dateFields are timestamps of news counted by date facets.
+var dateFields = []DateField{DatePublishedAt, DateModifiedAt, DateFetchedAt, DateIngestedAt}
+
+// dateField returns timestamp filtered by date range and counted by date facet.
+func (q *Query) dateField() DateField {
+	if !q.DateField.IsValid() {
+		return DateFieldDefault
+	}
+	return q.DateField
+}
+
+// RelatedQuery selects candidates sharing tags or keywords within date range.
+type RelatedQuery struct {
+	ExcludeID string
+	Tags      []string
+	Keywords  []string
+	DateFrom  time.Time
+	DateTo    time.Time
+	Limit     uint
 }
 
 type Options struct {
@@ -38,11 +117,17 @@ const (
 	SortPublishedAtAsc
 	SortRelevanceDesc
 	SortRelevanceAsc
+	SortModifiedAtDesc
+	SortModifiedAtAsc
+	SortFetchedAtDesc
+	SortFetchedAtAsc
+	SortIngestedAtDesc
+	SortIngestedAtAsc
 	SortDefault = SortPublishedAtDesc
 )
 
 func (s SortOption) IsValid() bool {
-	return s >= SortPublishedAtDesc && s <= SortRelevanceAsc
+	return s >= SortPublishedAtDesc && s <= SortIngestedAtAsc
 }
 
 func (s SortOption) IsPublishedAt() bool {
@@ -52,3 +137,22 @@ func (s SortOption) IsPublishedAt() bool {
 func (s SortOption) IsRelevance() bool {
 	return s == SortRelevanceDesc || s == SortRelevanceAsc
 }
+
+type FacetOptions struct {
+	Limit    uint
+	Interval DateInterval
+}
+
+type DateInterval string
+
+const (
+	IntervalDay     DateInterval = "day"
+	IntervalWeek    DateInterval = "week"
+	IntervalMonth   DateInterval = "month"
+	IntervalYear    DateInterval = "year"
+	IntervalDefault              = IntervalDay
+)
+
+func (i DateInterval) IsValid() bool {
+	return i == IntervalDay || i == IntervalWeek || i == IntervalMonth || i == IntervalYear
+}
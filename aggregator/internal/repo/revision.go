@@ -0,0 +1,89 @@
+package repo
+
+import (
+	"slices"
+	"time"
+
+	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/aggregator/pkg/textdiff"
+)
+
+// newsChanged reports whether stored content of news differs.
+func newsChanged(a, b *entity.News) bool {
+	return a.Title != b.Title ||
+		a.Description != b.Description ||
+		a.Content != b.Content ||
+		!slices.Equal(a.Authors, b.Authors) ||
+		!slices.Equal(a.Tags, b.Tags) ||
+		!slices.Equal(a.Categories, b.Categories)
+}
+
+// newsReplaced reports whether news replaces stored news and whether its content
+// differs. Versions modified before stored news never replace it, so news
+// received out of order do not revert it.
+func newsReplaced(prev, news *entity.News) (replaced bool, changed bool) {
+	if news.ModifiedAt.Before(prev.ModifiedAt) {
+		return false, false
+	}
+
+	changed = newsChanged(prev, news)
+	return changed || news.ModifiedAt.After(prev.ModifiedAt), changed
+}
+
+func newRevision(newsID string, version int, news *entity.News) entity.Revision {
+	return entity.Revision{
+		NewsID:      newsID,
+		Version:     version,
+		Title:       news.Title,
+		Description: news.Description,
+		Authors:     news.Authors,
+		Tags:        news.Tags,
+		Categories:  news.Categories,
+		Content:     news.Content,
+		PublishedAt: news.PublishedAt,
+		ModifiedAt:  news.ModifiedAt,
+		FetchedAt:   news.FetchedAt,
+		CreatedAt:   time.Now().UTC(),
+	}
+}
+
+// nextRevisions returns revisions following last revision of prev, news is stored
+// with diff from prev. Prev is returned as first revision if news has no revisions,
+// so original version is kept only for changed news.
+func nextRevisions(last *entity.Revision, prev *entity.News, news *entity.News, diff []entity.FieldDiff) []entity.Revision {
+	revisions := make([]entity.Revision, 0, 2)
+	if last == nil {
+		first := newRevision(prev.ID, 1, prev)
+		last = &first
+		revisions = append(revisions, first)
+	}
+
+	revision := newRevision(prev.ID, last.Version+1, news)
+	revision.Diff = diff
+	return append(revisions, revision)
+}
+
+// diffNews returns changes of each modified field from prev to news.
+func diffNews(prev, news *entity.News) []entity.FieldDiff {
+	diffs := make([]entity.FieldDiff, 0, 6)
+	appendDiff := func(field string, changes []textdiff.Change) {
+		if !textdiff.Changed(changes) {
+			return
+		}
+
+		diff := entity.FieldDiff{Field: field, Changes: make([]entity.DiffChange, len(changes))}
+		for i, change := range changes {
+			diff.Changes[i] = entity.DiffChange{Op: string(change.Op), Text: change.Text}
+		}
+		diffs = append(diffs, diff)
+	}
+
+	appendDiff("title", textdiff.Words(prev.Title, news.Title))
+	appendDiff("description", textdiff.Words(prev.Description, news.Description))
+	appendDiff("content", textdiff.Lines(prev.Content, news.Content))
+	appendDiff("authors", textdiff.Diff(prev.Authors, news.Authors, ", "))
+	appendDiff("tags", textdiff.Diff(prev.Tags, news.Tags, ", "))
+	appendDiff("categories", textdiff.Diff(prev.Categories, news.Categories, ", "))
+
+	return diffs
+}
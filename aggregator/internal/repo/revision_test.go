@@ -0,0 +1,108 @@
+package repo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qsoulior/news/aggregator/entity"
+)
+
+func TestNewsChanged(t *testing.T) {
+	base := entity.News{
+		NewsHead: entity.NewsHead{Title: "title", Description: "description"},
+		Content:  "content",
+		Tags:     []string{"a", "b"},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(news *entity.News)
+		want   bool
+	}{
+		{"same", func(news *entity.News) {}, false},
+		{"timestamps", func(news *entity.News) { news.FetchedAt = news.FetchedAt.AddDate(0, 0, 1) }, false},
+		{"title", func(news *entity.News) { news.Title = "other" }, true},
+		{"content", func(news *entity.News) { news.Content += "." }, true},
+		{"tags", func(news *entity.News) { news.Tags = []string{"b", "a"} }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			news := base
+			news.Tags = append([]string(nil), base.Tags...)
+			tt.modify(&news)
+
+			if got := newsChanged(&base, &news); got != tt.want {
+				t.Errorf("newsChanged = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewsReplaced(t *testing.T) {
+	modified := time.Date(2024, 1, 2, 11, 0, 0, 0, time.UTC)
+	prev := entity.News{
+		NewsHead: entity.NewsHead{Title: "title", ModifiedAt: modified},
+		Content:  "content",
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(news *entity.News)
+		replaced bool
+		changed  bool
+	}{
+		{"same", func(news *entity.News) {}, false, false},
+		{"same content modified later", func(news *entity.News) { news.ModifiedAt = modified.Add(time.Hour) }, true, false},
+		{"changed content", func(news *entity.News) { news.Content += "." }, true, true},
+		{"changed content modified later", func(news *entity.News) {
+			news.Content += "."
+			news.ModifiedAt = modified.Add(time.Hour)
+		}, true, true},
+		// older version received out of order does not revert stored news
+		{"changed content modified earlier", func(news *entity.News) {
+			news.Content = "old content"
+			news.ModifiedAt = modified.Add(-time.Hour)
+		}, false, false},
+		{"same content modified earlier", func(news *entity.News) { news.ModifiedAt = modified.Add(-time.Hour) }, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			news := prev
+			tt.modify(&news)
+
+			replaced, changed := newsReplaced(&prev, &news)
+			if replaced != tt.replaced || changed != tt.changed {
+				t.Errorf("newsReplaced = %t, %t, want %t, %t", replaced, changed, tt.replaced, tt.changed)
+			}
+		})
+	}
+}
+
+func TestNextRevisions(t *testing.T) {
+	prev := &entity.News{NewsHead: entity.NewsHead{ID: "id", Title: "old title"}}
+	news := &entity.News{NewsHead: entity.NewsHead{ID: "id", Title: "new title"}}
+	diff := diffNews(prev, news)
+
+	// original version is stored on first change
+	revisions := nextRevisions(nil, prev, news, diff)
+	if len(revisions) != 2 {
+		t.Fatalf("first change returned %d revisions, want 2", len(revisions))
+	}
+
+	if r := revisions[0]; r.Version != 1 || r.Title != "old title" || r.Diff != nil || r.NewsID != "id" {
+		t.Errorf("first revision = %+v, want original version 1", r)
+	}
+
+	if r := revisions[1]; r.Version != 2 || r.Title != "new title" || len(r.Diff) != 1 || r.Diff[0].Field != "title" {
+		t.Errorf("second revision = %+v, want changed version 2 with title diff", r)
+	}
+
+	// next change follows last revision
+	last := revisions[1]
+	revisions = nextRevisions(&last, news, prev, diffNews(news, prev))
+	if len(revisions) != 1 || revisions[0].Version != 3 || revisions[0].Title != "old title" {
+		t.Errorf("next change returned %+v, want version 3", revisions)
+	}
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/qsoulior/news/aggregator/pkg/querylang"
+)
+
+type SyntaxError = querylang.SyntaxError
+
+var (
+	ErrEmptyQuery    = errors.New("query contains no keywords")
+	ErrInvalidSource = errors.New("invalid source")
+	ErrCooldown      = errors.New("query is parsed recently")
+	ErrRateLimited   = errors.New("parse budget is exhausted")
+	ErrNotIndexed    = errors.New("news is not indexed")
+)
@@ -1,21 +1,35 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/qsoulior/news/aggregator/entity"
 	"github.com/qsoulior/news/aggregator/internal/repo"
+	"github.com/qsoulior/news/aggregator/pkg/codec"
+	"github.com/qsoulior/news/aggregator/pkg/highlight"
+	"github.com/qsoulior/news/aggregator/pkg/message"
+	"github.com/qsoulior/news/aggregator/pkg/querylang"
 	"github.com/qsoulior/news/aggregator/pkg/rabbitmq"
+	"github.com/qsoulior/news/aggregator/pkg/similarity"
+	"github.com/qsoulior/news/aggregator/pkg/urlcanon"
 )
 
 type (
 	NewsConfig struct {
-		Repo       repo.News
-		Producer   rabbitmq.Producer
-		Exchange   string
-		RoutingKey string
+		Repo      repo.News
+		Search    repo.Search
+		Producer  rabbitmq.Producer
+		Exchange  string
+		Highlight highlight.Options
+		// LegacyExchange receives plain text queries for parsers of previous release.
+		LegacyExchange string
 	}
 )
 
@@ -24,25 +38,210 @@ type news struct {
 }
 
 func NewNews(cfg NewsConfig) News {
+	cfg.Highlight.SetDefault()
 	return &news{cfg}
 }
 
-func (n *news) Create(ctx context.Context, news entity.News) error {
-	if err := n.Repo.ReplaceOrCreate(ctx, news); err != nil {
-		return fmt.Errorf("n.repo.Create: %w", err)
+// normalize sets identifier of news received from parsers by canonical form
+// of link and fills missing timestamps. Link is kept as is.
+func (n *news) normalize(news *entity.News, now time.Time) {
+	news.UID = urlcanon.UID(news.Link)
+
+	if news.ModifiedAt.Before(news.PublishedAt) {
+		news.ModifiedAt = news.PublishedAt
 	}
 
-	return nil
+	if news.FetchedAt.IsZero() {
+		news.FetchedAt = now
+	}
+
+	news.IngestedAt = now
+}
+
+// Create stores news and reports whether it is new or updated.
+// Stored news that failed to be indexed are reported with ErrNotIndexed,
+// they are queued and indexed again by RetryIndex.
+func (n *news) Create(ctx context.Context, news entity.News) (bool, error) {
+	n.normalize(&news, time.Now().UTC())
+	stored, err := n.Repo.ReplaceOrCreate(ctx, news)
+	if err != nil {
+		return false, fmt.Errorf("n.repo.Create: %w", err)
+	}
+
+	if stored && n.Search != nil {
+		if err := n.index(ctx, news); err != nil {
+			return stored, err
+		}
+	}
+
+	return stored, nil
 }
 
 func (n *news) CreateMany(ctx context.Context, news []entity.News) error {
+	now := time.Now().UTC()
+	for i := range news {
+		n.normalize(&news[i], now)
+	}
+
 	if err := n.Repo.CreateMany(ctx, news); err != nil {
 		return fmt.Errorf("n.repo.CreateMany: %w", err)
 	}
 
+	if n.Search != nil {
+		if err := n.index(ctx, news...); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// index adds stored news to search index. News failed to be indexed are
+// queued, so they are not lost until next reindex.
+func (n *news) index(ctx context.Context, news ...entity.News) error {
+	err := n.Search.Index(ctx, news...)
+	if err == nil {
+		return nil
+	}
+	err = fmt.Errorf("%w: n.Search.Index: %w", ErrNotIndexed, err)
+
+	uids := make([]string, len(news))
+	for i := range news {
+		uids[i] = news[i].UID
+	}
+
+	// news are queued even if indexing failed because of canceled context
+	if qerr := n.Repo.QueueIndex(context.WithoutCancel(ctx), uids...); qerr != nil {
+		return errors.Join(err, fmt.Errorf("n.Repo.QueueIndex: %w", qerr))
+	}
+
+	return err
+}
+
+// INDEX_RETRY_LIMIT is max number of queued news indexed at once.
+const INDEX_RETRY_LIMIT = 500
+
+// RetryIndex indexes stored versions of news queued after failed indexing
+// and returns their number.
+func (n *news) RetryIndex(ctx context.Context) (int, error) {
+	if n.Search == nil {
+		return 0, nil
+	}
+
+	count := 0
+	for {
+		// news queued again while they are indexed stay in queue
+		start := time.Now().UTC()
+		uids, news, err := n.Repo.GetIndexQueue(ctx, INDEX_RETRY_LIMIT)
+		if err != nil {
+			return count, fmt.Errorf("n.Repo.GetIndexQueue: %w", err)
+		}
+
+		if len(uids) == 0 {
+			return count, nil
+		}
+
+		if len(news) > 0 {
+			if err := n.Search.Index(ctx, news...); err != nil {
+				return count, fmt.Errorf("n.Search.Index: %w", err)
+			}
+		}
+
+		if err := n.Repo.DeleteIndexQueue(ctx, uids, start); err != nil {
+			return count, fmt.Errorf("n.Repo.DeleteIndexQueue: %w", err)
+		}
+		count += len(news)
+
+		if len(uids) < INDEX_RETRY_LIMIT {
+			return count, nil
+		}
+	}
+}
+
+// BackfillUID sets identifiers of news stored before they were introduced.
+// It returns number of updated news.
+func (n *news) BackfillUID(ctx context.Context) (int, error) {
+	count, err := n.Repo.SetMissingUID(ctx, urlcanon.UID)
+	if err != nil {
+		return count, fmt.Errorf("n.Repo.SetMissingUID: %w", err)
+	}
+
+	return count, nil
+}
+
+// BackfillTimestamps sets timestamps of news stored before they were introduced.
+// It returns number of updated news.
+func (n *news) BackfillTimestamps(ctx context.Context) (int, error) {
+	count, err := n.Repo.SetMissingTimestamps(ctx)
+	if err != nil {
+		return count, fmt.Errorf("n.Repo.SetMissingTimestamps: %w", err)
+	}
+
+	return count, nil
+}
+
+// REINDEX_DONE is checkpoint of completed reindex.
+const REINDEX_DONE = "done"
+
+// Reindex fills search index with all stored news. Checkpoint is saved
+// after each batch, so interrupted reindex is continued from it.
+func (n *news) Reindex(ctx context.Context) (int, error) {
+	if n.Search == nil {
+		return 0, nil
+	}
+
+	checkpoint, err := n.Search.Checkpoint(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("n.Search.Checkpoint: %w", err)
+	}
+
+	if checkpoint == REINDEX_DONE {
+		return 0, nil
+	}
+
+	const batchSize = 500
+	count := 0
+	batch := make([]entity.News, 0, batchSize)
+
+	flush := func() error {
+		if err := n.Search.Index(ctx, batch...); err != nil {
+			return fmt.Errorf("n.Search.Index: %w", err)
+		}
+
+		if err := n.Search.SetCheckpoint(ctx, batch[len(batch)-1].ID); err != nil {
+			return fmt.Errorf("n.Search.SetCheckpoint: %w", err)
+		}
+
+		count += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	err = n.Repo.ForEach(ctx, checkpoint, func(news entity.News) error {
+		batch = append(batch, news)
+		if len(batch) < batchSize {
+			return nil
+		}
+
+		return flush()
+	})
+	if err != nil {
+		return count, fmt.Errorf("n.Repo.ForEach: %w", err)
+	}
+
+	if len(batch) > 0 {
+		if err := flush(); err != nil {
+			return count, err
+		}
+	}
+
+	if err := n.Search.SetCheckpoint(ctx, REINDEX_DONE); err != nil {
+		return count, fmt.Errorf("n.Search.SetCheckpoint: %w", err)
+	}
+
+	return count, nil
+}
+
 func (n *news) Get(ctx context.Context, id string) (*entity.News, error) {
 	news, err := n.Repo.GetByID(ctx, id)
 	if errors.Is(err, repo.ErrNotFound) || errors.Is(err, repo.ErrInvalidID) {
@@ -56,14 +255,102 @@ func (n *news) Get(ctx context.Context, id string) (*entity.News, error) {
 	return news, nil
 }
 
+// GetRevisions returns history of news versions or nil if news is not found.
+func (n *news) GetRevisions(ctx context.Context, id string) ([]entity.Revision, error) {
+	news, err := n.Get(ctx, id)
+	if err != nil || news == nil {
+		return nil, err
+	}
+
+	revisions, err := n.Repo.GetRevisions(ctx, news.ID)
+	if err != nil {
+		return nil, fmt.Errorf("n.Repo.GetRevisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// GetRelated returns news similar to news with given ID ranked by similarity
+// of content, tags and named entities.
+func (n *news) GetRelated(ctx context.Context, id string, opts RelatedOptions) ([]entity.NewsHead, error) {
+	target, err := n.Get(ctx, id)
+	if err != nil || target == nil {
+		return nil, err
+	}
+
+	const (
+		candidateLimit = 200
+		keywordLimit   = 8
+		minScore       = 0.1
+	)
+
+	window := opts.GetWindow()
+	candidates, err := n.Repo.GetRelated(ctx, repo.RelatedQuery{
+		ExcludeID: target.ID,
+		Tags:      target.Tags,
+		Keywords:  similarity.Keywords(target.Title+" "+target.Description, keywordLimit),
+		DateFrom:  target.PublishedAt.Add(-window),
+		DateTo:    target.PublishedAt.Add(window),
+		Limit:     candidateLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("n.Repo.GetRelated: %w", err)
+	}
+
+	text := func(news *entity.News) string {
+		return news.Title + "\n" + news.Description + "\n" + news.Content
+	}
+
+	docs := make([][]string, len(candidates)+1)
+	docs[0] = similarity.Tokenize(text(target))
+	for i := range candidates {
+		docs[i+1] = similarity.Tokenize(text(&candidates[i]))
+	}
+
+	tfidf := similarity.NewTFIDF(docs)
+	targetVector := tfidf.Vector(docs[0])
+	targetEntities := similarity.Entities(target.Title + ". " + target.Description)
+
+	type scored struct {
+		head  entity.NewsHead
+		score float64
+	}
+
+	results := make([]scored, 0, len(candidates))
+	for i, candidate := range candidates {
+		score := 0.6*similarity.Cosine(targetVector, tfidf.Vector(docs[i+1])) +
+			0.25*similarity.Jaccard(target.Tags, candidate.Tags) +
+			0.15*similarity.Jaccard(targetEntities, similarity.Entities(candidate.Title+". "+candidate.Description))
+
+		if score >= minScore {
+			results = append(results, scored{candidate.NewsHead, score})
+		}
+	}
+
+	slices.SortStableFunc(results, func(a, b scored) int {
+		return cmp.Compare(b.score, a.score)
+	})
+
+	news := make([]entity.NewsHead, 0, opts.GetLimit())
+	for _, result := range results[:min(len(results), int(opts.GetLimit()))] {
+		news = append(news, result.head)
+	}
+
+	return news, nil
+}
+
 func (n *news) GetHead(ctx context.Context, query Query, opts Options) ([]entity.NewsHead, int, error) {
 	if opts.raw.Sort.IsRelevance() && (query.Text == "" || (query.Text != "" && query.Title)) {
 		opts.SetSort(0)
 	}
 
-	if query.DateTo != nil {
-		dateTo := query.DateTo.AddDate(0, 0, 1)
-		query.DateTo = &dateTo
+	query, err := n.prepareQuery(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if query.Expr != nil && n.searchReady(ctx) {
+		return n.search(ctx, query, opts)
 	}
 
 	news, count, err := n.Repo.GetByQuery(ctx, query, opts.raw)
@@ -71,19 +358,177 @@ func (n *news) GetHead(ctx context.Context, query Query, opts Options) ([]entity
 		return nil, 0, fmt.Errorf("n.repo.GetByQuery: %w", err)
 	}
 
+	n.highlight(news, query)
 	return news, count, nil
 }
 
-func (n *news) SendToParse(ctx context.Context, query string) error {
-	err := n.Producer.Produce(ctx, n.Exchange, n.RoutingKey, rabbitmq.Message{
-		ContentType:  "text/plain",
-		DeliveryMode: 2,
-		Body:         []byte(query),
-	})
+// highlight marks keywords of query in news found without search index,
+// repo doesn't report match positions so they are found in text.
+func (n *news) highlight(news []entity.NewsHead, query Query) {
+	var keywords []string
+	if query.Expr != nil {
+		keywords = querylang.Keywords(query.Expr)
+	} else if query.Text != "" {
+		keywords = []string{query.Text}
+	}
+
+	if len(keywords) == 0 {
+		return
+	}
+
+	for i := range news {
+		title := highlight.Fragments(news[i].Title, highlight.Match(news[i].Title, keywords), n.Highlight)
+		description := highlight.Fragments(news[i].Description, highlight.Match(news[i].Description, keywords), n.Highlight)
+		if title != nil || description != nil {
+			news[i].Highlights = &entity.Highlights{Title: title, Description: description}
+		}
+	}
+}
+
+// searchReady reports whether search index contains all stored news.
+// Until reindex is completed news are found by repo.
+func (n *news) searchReady(ctx context.Context) bool {
+	if n.Search == nil {
+		return false
+	}
 
+	checkpoint, err := n.Search.Checkpoint(ctx)
+	return err == nil && checkpoint == REINDEX_DONE
+}
+
+// search finds news in search index and loads them from repo.
+func (n *news) search(ctx context.Context, query Query, opts Options) ([]entity.NewsHead, int, error) {
+	hits, count, err := n.Search.Search(ctx, query, opts.raw)
 	if err != nil {
-		return fmt.Errorf("n.AMQP.Producer.Produce: %w", err)
+		return nil, 0, fmt.Errorf("n.Search.Search: %w", err)
 	}
 
-	return nil
+	uids := make([]string, len(hits))
+	for i, hit := range hits {
+		uids[i] = hit.UID
+	}
+
+	heads, err := n.Repo.GetByUIDs(ctx, uids)
+	if err != nil {
+		return nil, 0, fmt.Errorf("n.repo.GetByUIDs: %w", err)
+	}
+
+	// keep order of search hits
+	news := make([]entity.NewsHead, 0, len(hits))
+	for _, hit := range hits {
+		if head, ok := heads[hit.UID]; ok {
+			head.Highlights = hit.Highlights
+			news = append(news, head)
+		}
+	}
+
+	return news, count, nil
+}
+
+func (n *news) GetFacets(ctx context.Context, query Query, opts FacetOptions) (*entity.Facets, error) {
+	opts.raw.Limit = opts.GetLimit()
+	opts.raw.Interval = opts.GetInterval()
+
+	query, err := n.prepareQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// facets are counted by the same backend as list of news
+	if query.Expr != nil && n.searchReady(ctx) {
+		facets, err := n.Search.Facets(ctx, query, opts.raw)
+		if err != nil {
+			return nil, fmt.Errorf("n.Search.Facets: %w", err)
+		}
+		return facets, nil
+	}
+
+	facets, err := n.Repo.GetFacets(ctx, query, opts.raw)
+	if err != nil {
+		return nil, fmt.Errorf("n.repo.GetFacets: %w", err)
+	}
+
+	return facets, nil
+}
+
+func (n *news) prepareQuery(query Query) (Query, error) {
+	if query.DateTo != nil {
+		dateTo := query.DateTo.AddDate(0, 0, 1)
+		query.DateTo = &dateTo
+	}
+
+	if query.Text != "" && !query.Title {
+		expr, err := querylang.Parse(query.Text)
+		if err != nil {
+			return query, fmt.Errorf("querylang.Parse: %w", err)
+		}
+		query.Expr = expr
+	}
+
+	return query, nil
+}
+
+// SendToParse publishes parse request to target parsers and returns its ID.
+// Empty ID is returned if query contains no keywords.
+func (n *news) SendToParse(ctx context.Context, req ParseRequest) (string, error) {
+	// parsers search by keywords only
+	if expr, err := querylang.Parse(req.Query); err == nil {
+		req.Query = strings.Join(querylang.Keywords(expr), " ")
+	}
+
+	if req.Query == "" {
+		return "", nil
+	}
+
+	if req.ID == "" {
+		req.ID = uuid.NewString()
+	}
+	req.CreatedAt = time.Now().UTC()
+	req.Priority = min(req.Priority, message.MaxPriority)
+
+	body, err := message.EncodeParseRequest(&req)
+	if err != nil {
+		return "", fmt.Errorf("message.EncodeParseRequest: %w", err)
+	}
+
+	routingKeys := []string{message.ParseRoutingAll}
+	if len(req.Targets) > 0 {
+		routingKeys = make([]string, len(req.Targets))
+		for i, target := range req.Targets {
+			routingKeys[i] = message.ParseRoutingKey(target)
+		}
+	}
+
+	for _, routingKey := range routingKeys {
+		err := n.Producer.Produce(ctx, n.Exchange, routingKey, rabbitmq.Message{
+			ContentType:  codec.ContentTypeJSON,
+			DeliveryMode: 2,
+			MessageId:    req.ID,
+			Priority:     req.Priority,
+			Timestamp:    req.CreatedAt,
+			Body:         body,
+		})
+
+		if err != nil {
+			return "", fmt.Errorf("n.Producer.Produce: %w", err)
+		}
+	}
+
+	if n.LegacyExchange != "" {
+		// parsers of previous release can not filter targets, they parse all requests;
+		// current parsers skip these messages by their ID
+		err := n.Producer.Produce(ctx, n.LegacyExchange, "", rabbitmq.Message{
+			ContentType:  "text/plain",
+			DeliveryMode: 2,
+			MessageId:    req.ID,
+			Timestamp:    req.CreatedAt,
+			Body:         []byte(req.Query),
+		})
+
+		if err != nil {
+			return "", fmt.Errorf("n.Producer.Produce: %w", err)
+		}
+	}
+
+	return req.ID, nil
 }
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/aggregator/internal/repo"
+	"github.com/qsoulior/news/aggregator/pkg/message"
+	"github.com/qsoulior/news/aggregator/pkg/rabbitmq"
+)
+
+type publication struct {
+	exchange, routingKey string
+	msg                  rabbitmq.Message
+}
+
+// producer records published messages.
+type producer struct {
+	published []publication
+}
+
+func (p *producer) Produce(ctx context.Context, exchange string, routingKey string, msg rabbitmq.Message) error {
+	p.published = append(p.published, publication{exchange, routingKey, msg})
+	return nil
+}
+
+func TestNewsSendToParseLegacy(t *testing.T) {
+	p := &producer{}
+	n := NewNews(NewsConfig{
+		Producer:       p,
+		Exchange:       message.ParseExchange,
+		LegacyExchange: message.LegacyParseExchange,
+	})
+
+	id, err := n.SendToParse(context.Background(), ParseRequest{Query: "выборы", Targets: []string{"ria"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		exchange, routingKey, contentType, body string
+	}{
+		{message.ParseExchange, message.ParseRoutingKey("ria"), "application/json", ""},
+		// parsers of previous release receive plain query
+		{message.LegacyParseExchange, "", "text/plain", "выборы"},
+	}
+
+	if len(p.published) != len(want) {
+		t.Fatalf("published = %d, want %d", len(p.published), len(want))
+	}
+
+	for i, w := range want {
+		got := p.published[i]
+		if got.exchange != w.exchange || got.routingKey != w.routingKey || got.msg.ContentType != w.contentType {
+			t.Errorf("published[%d] = %s %s %s, want %s %s %s", i,
+				got.exchange, got.routingKey, got.msg.ContentType, w.exchange, w.routingKey, w.contentType)
+		}
+
+		if w.body != "" && string(got.msg.Body) != w.body {
+			t.Errorf("published[%d] body = %q, want %q", i, got.msg.Body, w.body)
+		}
+
+		// current parsers skip legacy messages having ID of request
+		if got.msg.MessageId != id {
+			t.Errorf("published[%d] message id = %q, want %q", i, got.msg.MessageId, id)
+		}
+	}
+}
+
+// newsRepo stores news by identifier and records which backend found them.
+type newsRepo struct {
+	repo.News
+	news    map[string]entity.News
+	queue   map[string]time.Time
+	queried int
+}
+
+func (r *newsRepo) ReplaceOrCreate(ctx context.Context, news entity.News) (bool, error) {
+	r.news[news.UID] = news
+	return true, nil
+}
+
+func (r *newsRepo) GetByQuery(ctx context.Context, query repo.Query, opts repo.Options) ([]entity.NewsHead, int, error) {
+	r.queried++
+	return nil, 0, nil
+}
+
+func (r *newsRepo) GetFacets(ctx context.Context, query repo.Query, opts repo.FacetOptions) (*entity.Facets, error) {
+	r.queried++
+	return &entity.Facets{}, nil
+}
+
+func (r *newsRepo) GetByUIDs(ctx context.Context, uids []string) (map[string]entity.NewsHead, error) {
+	return nil, nil
+}
+
+func (r *newsRepo) QueueIndex(ctx context.Context, uids ...string) error {
+	for _, uid := range uids {
+		r.queue[uid] = time.Now().UTC()
+	}
+	return nil
+}
+
+func (r *newsRepo) GetIndexQueue(ctx context.Context, limit int) ([]string, []entity.News, error) {
+	var uids []string
+	var news []entity.News
+	for uid := range r.queue {
+		uids = append(uids, uid)
+		if item, ok := r.news[uid]; ok {
+			news = append(news, item)
+		}
+	}
+	return uids, news, nil
+}
+
+func (r *newsRepo) DeleteIndexQueue(ctx context.Context, uids []string, before time.Time) error {
+	for _, uid := range uids {
+		if r.queue[uid].Before(before) {
+			delete(r.queue, uid)
+		}
+	}
+	return nil
+}
+
+// search counts searches and fails to index news if err is set.
+type search struct {
+	checkpoint string
+	err        error
+	indexed    []string
+	searched   int
+}
+
+func (s *search) Index(ctx context.Context, news ...entity.News) error {
+	if s.err != nil {
+		return s.err
+	}
+	for _, item := range news {
+		s.indexed = append(s.indexed, item.UID)
+	}
+	return nil
+}
+
+func (s *search) Search(ctx context.Context, query repo.Query, opts repo.Options) ([]repo.SearchHit, int, error) {
+	s.searched++
+	return nil, 0, nil
+}
+
+func (s *search) Facets(ctx context.Context, query repo.Query, opts repo.FacetOptions) (*entity.Facets, error) {
+	s.searched++
+	return &entity.Facets{}, nil
+}
+
+func (s *search) Checkpoint(ctx context.Context) (string, error) {
+	return s.checkpoint, nil
+}
+
+func (s *search) SetCheckpoint(ctx context.Context, checkpoint string) error {
+	s.checkpoint = checkpoint
+	return nil
+}
+
+func TestNewsSearchBeforeReindex(t *testing.T) {
+	tests := []struct {
+		checkpoint string
+		searched   int
+		queried    int
+	}{
+		{"", 0, 2},
+		// partial index is not searched
+		{"6579a1b2c3d4e5f6a7b8c9d0", 0, 2},
+		{REINDEX_DONE, 2, 0},
+	}
+
+	for _, tt := range tests {
+		r := &newsRepo{}
+		s := &search{checkpoint: tt.checkpoint}
+		n := NewNews(NewsConfig{Repo: r, Search: s})
+
+		query := Query{Text: "выборы"}
+		if _, _, err := n.GetHead(context.Background(), query, Options{}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := n.GetFacets(context.Background(), query, FacetOptions{}); err != nil {
+			t.Fatal(err)
+		}
+
+		if s.searched != tt.searched || r.queried != tt.queried {
+			t.Errorf("checkpoint %q: searched = %d, queried = %d, want %d, %d",
+				tt.checkpoint, s.searched, r.queried, tt.searched, tt.queried)
+		}
+	}
+}
+
+func TestNewsIndexRetry(t *testing.T) {
+	r := &newsRepo{news: make(map[string]entity.News), queue: make(map[string]time.Time)}
+	s := &search{checkpoint: REINDEX_DONE, err: errors.New("index is closed")}
+	n := NewNews(NewsConfig{Repo: r, Search: s})
+
+	stored, err := n.Create(context.Background(), entity.News{Link: "https://ria.ru/20240102/news.html"})
+	if !stored || !errors.Is(err, ErrNotIndexed) {
+		t.Fatalf("Create = %v, %v, want true, %v", stored, err, ErrNotIndexed)
+	}
+
+	if len(r.queue) != 1 {
+		t.Fatalf("queued = %d, want 1", len(r.queue))
+	}
+
+	// news stay in queue until they are indexed
+	if _, err := n.RetryIndex(context.Background()); err == nil {
+		t.Error("RetryIndex with failing index succeeded")
+	}
+
+	if len(r.queue) != 1 {
+		t.Fatalf("queued after failed retry = %d, want 1", len(r.queue))
+	}
+
+	s.err = nil
+	count, err := n.RetryIndex(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 1 || len(s.indexed) != 1 || len(r.queue) != 0 {
+		t.Errorf("RetryIndex = %d, indexed = %d, queued = %d, want 1, 1, 0", count, len(s.indexed), len(r.queue))
+	}
+}
@@ -1,10 +1,22 @@
 package service
 
-import "github.com/qsoulior/news/aggregator/internal/repo"
+import (
+	"time"
+
+	"github.com/qsoulior/news/aggregator/internal/repo"
+)
 
 const (
 	MaxLimit     = 50
 	DefaultLimit = 20
+
+	MaxFacetLimit     = 100
+	DefaultFacetLimit = 20
+
+	MaxRelatedLimit      = 50
+	DefaultRelatedLimit  = 10
+	MaxRelatedWindow     = 30 * 24 * time.Hour
+	DefaultRelatedWindow = 3 * 24 * time.Hour
 )
 
 type Options struct {
@@ -54,3 +66,84 @@ func (o *Options) SetSort(sort int) {
 func (o *Options) GetSort() repo.SortOption {
 	return o.raw.Sort
 }
+
+type FacetOptions struct {
+	raw repo.FacetOptions
+}
+
+func (o *FacetOptions) SetLimit(limit int) {
+	if limit <= 0 {
+		o.raw.Limit = DefaultFacetLimit
+		return
+	}
+
+	if limit > MaxFacetLimit {
+		o.raw.Limit = MaxFacetLimit
+		return
+	}
+
+	o.raw.Limit = uint(limit)
+}
+
+func (o *FacetOptions) GetLimit() uint {
+	if o.raw.Limit == 0 {
+		return DefaultFacetLimit
+	}
+
+	return o.raw.Limit
+}
+
+func (o *FacetOptions) SetInterval(interval string) {
+	o.raw.Interval = repo.DateInterval(interval)
+	if !o.raw.Interval.IsValid() {
+		o.raw.Interval = repo.IntervalDefault
+	}
+}
+
+func (o *FacetOptions) GetInterval() repo.DateInterval {
+	if !o.raw.Interval.IsValid() {
+		return repo.IntervalDefault
+	}
+
+	return o.raw.Interval
+}
+
+type RelatedOptions struct {
+	limit  uint
+	window time.Duration
+}
+
+func (o *RelatedOptions) SetLimit(limit int) {
+	if limit <= 0 {
+		o.limit = DefaultRelatedLimit
+		return
+	}
+
+	o.limit = uint(min(limit, MaxRelatedLimit))
+}
+
+func (o *RelatedOptions) GetLimit() uint {
+	if o.limit == 0 {
+		return DefaultRelatedLimit
+	}
+
+	return o.limit
+}
+
+// SetWindow sets number of days before and after news publication to search related news.
+func (o *RelatedOptions) SetWindow(days int) {
+	if days <= 0 {
+		o.window = DefaultRelatedWindow
+		return
+	}
+
+	o.window = min(time.Duration(days)*24*time.Hour, MaxRelatedWindow)
+}
+
+func (o *RelatedOptions) GetWindow() time.Duration {
+	if o.window == 0 {
+		return DefaultRelatedWindow
+	}
+
+	return o.window
+}
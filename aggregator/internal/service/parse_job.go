@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/aggregator/internal/repo"
+	"github.com/qsoulior/news/aggregator/pkg/message"
+	"github.com/qsoulior/news/aggregator/pkg/querylang"
+)
+
+type (
+	ParseJobConfig struct {
+		Repo repo.ParseJob
+		News News
+		// Sources are parsers expected to handle requests without targets.
+		Sources []string
+		// Timeout is maximum time between updates of unfinished job.
+		Timeout time.Duration
+		// Throttle coalesces identical requests and limits requests per parser.
+		// Requests are not throttled if it is nil.
+		Throttle repo.ParseThrottle
+		// Cooldown is time during which identical requests are coalesced.
+		Cooldown time.Duration
+		// Budget is maximum number of requests sent to each parser per minute.
+		Budget int
+	}
+)
+
+const (
+	DefaultParseJobTimeout = 10 * time.Minute
+	DefaultParseCooldown   = 15 * time.Minute
+	DefaultParseBudget     = 30
+
+	parseBudgetPeriod = time.Minute
+)
+
+var sourcePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+type parseJob struct {
+	ParseJobConfig
+}
+
+func NewParseJob(cfg ParseJobConfig) ParseJob {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultParseJobTimeout
+	}
+
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = DefaultParseCooldown
+	}
+
+	if cfg.Budget <= 0 {
+		cfg.Budget = DefaultParseBudget
+	}
+
+	return &parseJob{cfg}
+}
+
+// Create stores job and sends its parse request to parsers having budget. Job of
+// identical request sent to the same parsers within cooldown is returned instead
+// of sending new request. Budget of request which is not sent is refunded.
+func (p *parseJob) Create(ctx context.Context, req ParseRequest) (*entity.ParseJob, error) {
+	req.Query = normalizeQuery(req.Query)
+	if req.Query == "" {
+		return nil, ErrEmptyQuery
+	}
+
+	targets := req.Targets
+	if len(targets) == 0 {
+		targets = p.Sources
+	}
+
+	for _, target := range targets {
+		if !sourcePattern.MatchString(target) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSource, target)
+		}
+	}
+
+	now := time.Now().UTC()
+	id := uuid.NewString()
+
+	window := now.Truncate(parseBudgetPeriod)
+
+	var key string
+	if p.Throttle != nil {
+		allowed, err := p.spend(ctx, targets, window)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(allowed) == 0 {
+			return nil, ErrRateLimited
+		}
+
+		// request is sent to parsers having budget only,
+		// so it is coalesced with requests sent to the same parsers
+		if len(allowed) < len(targets) {
+			targets, req.Targets = allowed, allowed
+		}
+
+		key = p.key(req, targets)
+		holder, ok, err := p.Throttle.Acquire(ctx, key, id, now.Add(p.Cooldown))
+		if err != nil || !ok {
+			if err := p.refund(ctx, targets, window); err != nil {
+				return nil, err
+			}
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("p.Throttle.Acquire: %w", err)
+		}
+
+		if !ok {
+			return p.coalesce(ctx, holder)
+		}
+	}
+
+	job := entity.ParseJob{
+		ID:        id,
+		Query:     req.Query,
+		Targets:   targets,
+		State:     entity.ParseJobQueued,
+		Sources:   make(map[string]entity.ParseJobSource, len(targets)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	for _, target := range targets {
+		job.Sources[target] = entity.ParseJobSource{State: entity.ParseJobQueued}
+	}
+
+	// job is stored first as parsers may report progress immediately
+	if err := p.Repo.Create(ctx, job); err != nil {
+		if err := p.release(ctx, key, job.ID, targets, window); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("p.Repo.Create: %w", err)
+	}
+
+	req.ID = job.ID
+	sentID, err := p.News.SendToParse(ctx, req)
+	if err != nil {
+		if err := p.Repo.SetState(ctx, job.ID, entity.ParseJobFailed, "request is not sent"); err != nil {
+			return nil, fmt.Errorf("p.Repo.SetState: %w", err)
+		}
+		if err := p.release(ctx, key, job.ID, targets, window); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("p.News.SendToParse: %w", err)
+	}
+
+	if sentID == "" {
+		if err := p.Repo.Delete(ctx, job.ID); err != nil {
+			return nil, fmt.Errorf("p.Repo.Delete: %w", err)
+		}
+		if err := p.release(ctx, key, job.ID, targets, window); err != nil {
+			return nil, err
+		}
+		return nil, ErrEmptyQuery
+	}
+
+	return &job, nil
+}
+
+// coalesce returns job of identical request sent within cooldown.
+func (p *parseJob) coalesce(ctx context.Context, id string) (*entity.ParseJob, error) {
+	if id == "" {
+		return nil, ErrCooldown
+	}
+
+	job, err := p.Repo.GetByID(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, ErrCooldown
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("p.Repo.GetByID: %w", err)
+	}
+
+	return job, nil
+}
+
+// release allows identical requests to be sent before cooldown ends
+// and refunds budget of request which is not sent.
+func (p *parseJob) release(ctx context.Context, key string, id string, targets []string, window time.Time) error {
+	if p.Throttle == nil {
+		return nil
+	}
+
+	if err := p.Throttle.Release(ctx, key, id); err != nil {
+		return fmt.Errorf("p.Throttle.Release: %w", err)
+	}
+
+	return p.refund(ctx, targets, window)
+}
+
+// spend returns targets having budget within window. Budget spent
+// by targets without budget is refunded.
+func (p *parseJob) spend(ctx context.Context, targets []string, window time.Time) ([]string, error) {
+	allowed := make([]string, 0, len(targets))
+	exceeded := make([]string, 0)
+	for _, target := range targets {
+		count, err := p.Throttle.Spend(ctx, target, window, parseBudgetPeriod)
+		if err != nil {
+			if err := p.refund(ctx, append(allowed, exceeded...), window); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("p.Throttle.Spend: %w", err)
+		}
+
+		if count <= p.Budget {
+			allowed = append(allowed, target)
+		} else {
+			exceeded = append(exceeded, target)
+		}
+	}
+
+	if err := p.refund(ctx, exceeded, window); err != nil {
+		return nil, err
+	}
+
+	return allowed, nil
+}
+
+// refund returns budget spent by targets within window.
+func (p *parseJob) refund(ctx context.Context, targets []string, window time.Time) error {
+	for _, target := range targets {
+		if err := p.Throttle.Refund(ctx, target, window); err != nil {
+			return fmt.Errorf("p.Throttle.Refund: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// key returns identifier of request independent of order of keywords and targets.
+func (p *parseJob) key(req ParseRequest, targets []string) string {
+	keywords := strings.Fields(req.Query)
+	slices.Sort(keywords)
+
+	targets = slices.Clone(targets)
+	slices.Sort(targets)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s|%s|%d", strings.Join(keywords, " "), strings.Join(targets, ","), req.Depth)
+	for _, date := range []*time.Time{req.DateFrom, req.DateTo} {
+		b.WriteByte('|')
+		if date != nil {
+			b.WriteString(date.Format(time.DateOnly))
+		}
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:16])
+}
+
+// normalizeQuery returns lowercase keywords of query without duplicates.
+func normalizeQuery(query string) string {
+	keywords := []string{query}
+	if expr, err := querylang.Parse(query); err == nil {
+		keywords = querylang.Keywords(expr)
+	}
+
+	words := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		for _, word := range strings.Fields(strings.ToLower(keyword)) {
+			if !slices.Contains(words, word) {
+				words = append(words, word)
+			}
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// Get returns job or nil if job is not found. Unfinished job without updates
+// within timeout is failed.
+func (p *parseJob) Get(ctx context.Context, id string) (*entity.ParseJob, error) {
+	job, err := p.Repo.GetByID(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("p.Repo.GetByID: %w", err)
+	}
+
+	if !job.State.IsFinished() && time.Since(job.UpdatedAt) > p.Timeout {
+		job.State, job.Error = entity.ParseJobFailed, "timeout"
+		if err := p.Repo.SetState(ctx, job.ID, job.State, job.Error); err != nil {
+			return nil, fmt.Errorf("p.Repo.SetState: %w", err)
+		}
+	}
+
+	return job, nil
+}
+
+func (p *parseJob) HandleProgress(ctx context.Context, progress *message.ParseProgress) error {
+	if !sourcePattern.MatchString(progress.Source) {
+		return fmt.Errorf("%w: %q", ErrInvalidSource, progress.Source)
+	}
+
+	update := entity.ParseJobSource{
+		State: progress.State,
+		Count: progress.Count,
+		Error: progress.Error,
+	}
+
+	timestamp := progress.Timestamp.UTC()
+	update.StartedAt = &timestamp
+	if progress.State.IsFinished() {
+		update.FinishedAt = &timestamp
+	}
+
+	// state of job is updated with its source
+	_, err := p.Repo.UpdateSource(ctx, progress.RequestID, progress.Source, update)
+	if errors.Is(err, repo.ErrNotFound) {
+		// request is not sent as job
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("p.Repo.UpdateSource: %w", err)
+	}
+
+	return nil
+}
+
+// AddNews counts news stored as result of job.
+func (p *parseJob) AddNews(ctx context.Context, requestID string, source string) error {
+	if requestID == "" || !sourcePattern.MatchString(source) {
+		return nil
+	}
+
+	err := p.Repo.IncrementNew(ctx, requestID, source)
+	if err != nil && !errors.Is(err, repo.ErrNotFound) {
+		return fmt.Errorf("p.Repo.IncrementNew: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/aggregator/internal/repo"
+	"github.com/qsoulior/news/aggregator/pkg/message"
+)
+
+type jobRepo struct {
+	repo.ParseJob
+	jobs map[string]entity.ParseJob
+}
+
+func (r *jobRepo) Create(ctx context.Context, job entity.ParseJob) error {
+	r.jobs[job.ID] = job
+	return nil
+}
+
+func (r *jobRepo) GetByID(ctx context.Context, id string) (*entity.ParseJob, error) {
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+	return &job, nil
+}
+
+func (r *jobRepo) SetState(ctx context.Context, id string, state entity.ParseJobState, reason string) error {
+	job := r.jobs[id]
+	job.State, job.Error = state, reason
+	r.jobs[id] = job
+	return nil
+}
+
+func (r *jobRepo) UpdateSource(ctx context.Context, id string, source string, progress entity.ParseJobSource) (*entity.ParseJob, error) {
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+	job.Sources[source] = progress
+	return &job, nil
+}
+
+func (r *jobRepo) Delete(ctx context.Context, id string) error {
+	delete(r.jobs, id)
+	return nil
+}
+
+type throttle struct {
+	holders map[string]string
+	budgets map[string]int
+}
+
+func (t *throttle) Acquire(ctx context.Context, key string, jobID string, until time.Time) (string, bool, error) {
+	if holder, ok := t.holders[key]; ok {
+		return holder, false, nil
+	}
+	t.holders[key] = jobID
+	return jobID, true, nil
+}
+
+func (t *throttle) Release(ctx context.Context, key string, jobID string) error {
+	if t.holders[key] == jobID {
+		delete(t.holders, key)
+	}
+	return nil
+}
+
+func (t *throttle) Spend(ctx context.Context, source string, window time.Time, period time.Duration) (int, error) {
+	t.budgets[source]++
+	return t.budgets[source], nil
+}
+
+func (t *throttle) Refund(ctx context.Context, source string, window time.Time) error {
+	t.budgets[source]--
+	return nil
+}
+
+type sender struct {
+	News
+	err  error
+	sent []ParseRequest
+}
+
+func (s *sender) SendToParse(ctx context.Context, req ParseRequest) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	s.sent = append(s.sent, req)
+	return req.ID, nil
+}
+
+func newTestParseJob(budget int) (*parseJob, *throttle, *sender) {
+	t := &throttle{holders: make(map[string]string), budgets: make(map[string]int)}
+	s := &sender{}
+	p := NewParseJob(ParseJobConfig{
+		Repo:     &jobRepo{jobs: make(map[string]entity.ParseJob)},
+		News:     s,
+		Sources:  []string{"lenta", "ria"},
+		Throttle: t,
+		Budget:   budget,
+	})
+	return p.(*parseJob), t, s
+}
+
+func TestParseJobCreateCoalesce(t *testing.T) {
+	p, throttle, sender := newTestParseJob(1)
+	ctx := context.Background()
+
+	// budget of lenta is spent, so request is sent to ria only
+	throttle.budgets["lenta"] = 1
+
+	job, err := p.Create(ctx, ParseRequest{Query: "Вакцина"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(job.Targets) != "[ria]" || len(sender.sent) != 1 {
+		t.Fatalf("job is sent to %v, want [ria]", job.Targets)
+	}
+
+	if throttle.budgets["lenta"] != 1 || throttle.budgets["ria"] != 1 {
+		t.Fatalf("budgets = %v, want spent by dispatched request only", throttle.budgets)
+	}
+
+	// identical request sent to the same parser is coalesced and does not spend budget
+	throttle.budgets["ria"] = 0
+	same, err := p.Create(ctx, ParseRequest{Query: "вакцина", Targets: []string{"ria"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if same.ID != job.ID || len(sender.sent) != 1 {
+		t.Errorf("request to dispatched targets is not coalesced")
+	}
+
+	if throttle.budgets["ria"] != 0 {
+		t.Errorf("budget of ria = %d, want coalesced request refunded", throttle.budgets["ria"])
+	}
+}
+
+func TestParseJobCreateRateLimited(t *testing.T) {
+	p, throttle, sender := newTestParseJob(1)
+	throttle.budgets["lenta"], throttle.budgets["ria"] = 1, 1
+
+	_, err := p.Create(context.Background(), ParseRequest{Query: "вакцина"})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Create error = %v, want ErrRateLimited", err)
+	}
+
+	if len(sender.sent) != 0 || len(throttle.holders) != 0 {
+		t.Error("rate limited request is sent or reserved")
+	}
+
+	if throttle.budgets["lenta"] != 1 || throttle.budgets["ria"] != 1 {
+		t.Errorf("budgets = %v, want rate limited request refunded", throttle.budgets)
+	}
+}
+
+func TestParseJobCreateNotSent(t *testing.T) {
+	p, throttle, sender := newTestParseJob(1)
+	sender.err = errors.New("channel is closed")
+
+	_, err := p.Create(context.Background(), ParseRequest{Query: "вакцина"})
+	if !errors.Is(err, sender.err) {
+		t.Fatalf("Create error = %v, want send error", err)
+	}
+
+	if len(throttle.holders) != 0 {
+		t.Error("cooldown of request which is not sent is not released")
+	}
+
+	if throttle.budgets["lenta"] != 0 || throttle.budgets["ria"] != 0 {
+		t.Errorf("budgets = %v, want request which is not sent refunded", throttle.budgets)
+	}
+}
+
+func TestParseJobHandleProgress(t *testing.T) {
+	p, _, _ := newTestParseJob(1)
+	ctx := context.Background()
+
+	job, err := p.Create(ctx, ParseRequest{Query: "вакцина"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = p.HandleProgress(ctx, &message.ParseProgress{
+		RequestID: job.ID,
+		Source:    "ria",
+		State:     entity.ParseJobDone,
+		Count:     12,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, _ := p.Repo.GetByID(ctx, job.ID)
+	source := stored.Sources["ria"]
+	if source.State != entity.ParseJobDone || source.Count != 12 || source.FinishedAt == nil {
+		t.Errorf("source = %+v, want done with 12 news", source)
+	}
+
+	// progress of request which is not sent as job is ignored
+	err = p.HandleProgress(ctx, &message.ParseProgress{RequestID: "feed", Source: "ria", State: entity.ParseJobRunning})
+	if err != nil {
+		t.Errorf("HandleProgress of unknown job error = %v, want nil", err)
+	}
+
+	err = p.HandleProgress(ctx, &message.ParseProgress{RequestID: job.ID, Source: "RIA!"})
+	if !errors.Is(err, ErrInvalidSource) {
+		t.Errorf("HandleProgress error = %v, want ErrInvalidSource", err)
+	}
+}
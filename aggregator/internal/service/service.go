@@ -5,16 +5,33 @@ import (
 
 	"github.com/qsoulior/news/aggregator/entity"
 	"github.com/qsoulior/news/aggregator/internal/repo"
+	"github.com/qsoulior/news/aggregator/pkg/message"
 )
 
 type News interface {
-	Create(ctx context.Context, news entity.News) error
+	Create(ctx context.Context, news entity.News) (bool, error)
 	CreateMany(ctx context.Context, news []entity.News) error
 	Get(ctx context.Context, id string) (*entity.News, error)
 	GetHead(ctx context.Context, query repo.Query, opts Options) ([]entity.NewsHead, int, error)
-	SendToParse(ctx context.Context, query string) error
+	GetRelated(ctx context.Context, id string, opts RelatedOptions) ([]entity.NewsHead, error)
+	GetFacets(ctx context.Context, query repo.Query, opts FacetOptions) (*entity.Facets, error)
+	GetRevisions(ctx context.Context, id string) ([]entity.Revision, error)
+	SendToParse(ctx context.Context, req ParseRequest) (string, error)
+	Reindex(ctx context.Context) (int, error)
+	RetryIndex(ctx context.Context) (int, error)
+	BackfillUID(ctx context.Context) (int, error)
+	BackfillTimestamps(ctx context.Context) (int, error)
+}
+
+type ParseJob interface {
+	Create(ctx context.Context, req ParseRequest) (*entity.ParseJob, error)
+	Get(ctx context.Context, id string) (*entity.ParseJob, error)
+	HandleProgress(ctx context.Context, progress *message.ParseProgress) error
+	AddNews(ctx context.Context, requestID string, source string) error
 }
 
 type (
-	Query = repo.Query
+	Query        = repo.Query
+	ParseRequest = message.ParseRequest
+	DateField    = repo.DateField
 )
@@ -2,17 +2,19 @@ package handler
 
 import (
 	"context"
-	"encoding/json"
 
-	"github.com/qsoulior/news/aggregator/entity"
 	"github.com/qsoulior/news/aggregator/internal/service"
+	"github.com/qsoulior/news/aggregator/pkg/message"
 	"github.com/qsoulior/news/aggregator/pkg/rabbitmq"
 	"github.com/rs/zerolog"
 )
 
 type NewsConfig struct {
-	Logger  *zerolog.Logger
-	Service service.News
+	Logger       *zerolog.Logger
+	Service      service.News
+	Jobs         service.ParseJob
+	Producer     rabbitmq.Producer
+	InvalidQueue string
 }
 
 type news struct {
@@ -24,15 +26,44 @@ func NewNews(cfg NewsConfig) *news {
 }
 
 func (n *news) Handle(ctx context.Context, msg *rabbitmq.Delivery) {
-	var news entity.News
-	err := json.Unmarshal(msg.Body, &news)
+	env, err := message.Unmarshal(msg.Body, msg.ContentType, msg.ContentEncoding)
 	if err != nil {
-		n.Logger.Error().Err(err).Send()
+		n.Logger.Warn().Err(err).Str("app", msg.AppId).Str("id", msg.MessageId).Msg("invalid message")
+		n.reject(ctx, msg, err)
 		return
 	}
 
-	err = n.Service.Create(ctx, news)
+	if env.SchemaVersion < message.CurrentVersion {
+		n.Logger.Debug().Int("version", env.SchemaVersion).Str("app", msg.AppId).Msg("outdated schema version")
+	}
+
+	stored, err := n.Service.Create(ctx, env.News)
 	if err != nil {
 		n.Logger.Error().Err(err).Send()
 	}
+
+	if stored && env.RequestID != "" {
+		if err := n.Jobs.AddNews(ctx, env.RequestID, env.Source); err != nil {
+			n.Logger.Error().Err(err).Str("request", env.RequestID).Send()
+		}
+	}
+}
+
+// reject routes invalid message to queue for inspection.
+func (n *news) reject(ctx context.Context, msg *rabbitmq.Delivery, reason error) {
+	if n.Producer == nil || n.InvalidQueue == "" {
+		return
+	}
+
+	err := n.Producer.Produce(ctx, "", n.InvalidQueue, rabbitmq.Message{
+		AppId:        msg.AppId,
+		MessageId:    msg.MessageId,
+		ContentType:  msg.ContentType,
+		DeliveryMode: 2,
+		Headers:      map[string]any{"error": reason.Error()},
+		Body:         msg.Body,
+	})
+	if err != nil {
+		n.Logger.Error().Err(err).Msg("reject message")
+	}
 }
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/qsoulior/news/aggregator/internal/service"
+	"github.com/qsoulior/news/aggregator/pkg/message"
+	"github.com/qsoulior/news/aggregator/pkg/rabbitmq"
+	"github.com/rs/zerolog"
+)
+
+type ParseJobConfig struct {
+	Logger  *zerolog.Logger
+	Service service.ParseJob
+}
+
+type parseJob struct {
+	ParseJobConfig
+}
+
+func NewParseJob(cfg ParseJobConfig) *parseJob {
+	return &parseJob{cfg}
+}
+
+func (p *parseJob) Handle(ctx context.Context, msg *rabbitmq.Delivery) {
+	progress, err := message.DecodeParseProgress(msg.Body)
+	if err != nil {
+		p.Logger.Warn().Err(err).Str("app", msg.AppId).Msg("invalid progress")
+		return
+	}
+
+	err = p.Service.HandleProgress(ctx, progress)
+	if err != nil {
+		p.Logger.Error().Err(err).Str("request", progress.RequestID).Send()
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/qsoulior/news/aggregator/internal/service"
 	"github.com/qsoulior/news/aggregator/internal/transport/amqp/handler"
+	"github.com/qsoulior/news/aggregator/pkg/message"
 	"github.com/qsoulior/news/aggregator/pkg/rabbitmq"
 	"github.com/rs/zerolog"
 )
@@ -12,15 +13,33 @@ import (
 type Config struct {
 	Logger      *zerolog.Logger
 	NewsService service.News
+	JobService  service.ParseJob
+	Producer    rabbitmq.Producer
 }
 
-func NewRouter(logger *zerolog.Logger, service service.News) rabbitmq.Handler {
+// NewRouter returns handler dispatching messages by queue.
+func NewRouter(cfg Config) rabbitmq.Handler {
 	news := handler.NewNews(handler.NewsConfig{
-		Logger:  logger,
-		Service: service,
+		Logger:       cfg.Logger,
+		Service:      cfg.NewsService,
+		Jobs:         cfg.JobService,
+		Producer:     cfg.Producer,
+		InvalidQueue: "news.invalid",
 	})
 
+	parseJob := handler.NewParseJob(handler.ParseJobConfig{
+		Logger:  cfg.Logger,
+		Service: cfg.JobService,
+	})
+
+	logger := cfg.Logger
 	return func(ctx context.Context, msg *rabbitmq.Delivery) {
+		// routing key of default exchange is queue name
+		if msg.RoutingKey == message.ProgressQueue {
+			parseJob.Handle(ctx, msg)
+			return
+		}
+
 		logger.Info().Str("app", msg.AppId).Str("id", msg.MessageId).Msg("message accepted")
 		news.Handle(ctx, msg)
 	}
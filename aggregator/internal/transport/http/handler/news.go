@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/url"
 	"slices"
@@ -10,17 +12,26 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/qsoulior/news/aggregator/entity"
 	"github.com/qsoulior/news/aggregator/internal/service"
+	"github.com/qsoulior/news/aggregator/pkg/httpserver"
 	"github.com/rs/zerolog"
 )
 
-const MIN_COUNT = 30
+const (
+	MIN_COUNT      = 30
+	PARSE_PRIORITY = 5
+	// MAX_PARSES limits jobs created in background at once, PARSE_TIMEOUT limits time of creation.
+	MAX_PARSES    = 16
+	PARSE_TIMEOUT = 10 * time.Second
+)
 
 type news struct {
 	service service.News
+	jobs    service.ParseJob
+	parses  chan struct{}
 }
 
-func NewNews(service service.News) *news {
-	return &news{service}
+func NewNews(service service.News, jobs service.ParseJob) *news {
+	return &news{service, jobs, make(chan struct{}, MAX_PARSES)}
 }
 
 type GetResponse struct {
@@ -29,6 +40,10 @@ type GetResponse struct {
 	Limit      uint              `json:"limit"`
 	Count      int               `json:"count"`
 	TotalCount int               `json:"total_count"`
+	// ParseQueued reports that parse request of query is queued, its job is
+	// created in background and may be rejected by cooldown or budget.
+	// Client tracks it by job returned by POST /parse-jobs with the same query.
+	ParseQueued bool `json:"parse_queued,omitempty"`
 }
 
 func (n *news) getInt(values url.Values, key string) (int, bool) {
@@ -53,10 +68,7 @@ func (n *news) isOutdated(news []entity.NewsHead) bool {
 	return time.Since(maxItem.PublishedAt) > 5*24*time.Hour
 }
 
-func (n *news) List(w http.ResponseWriter, r *http.Request) {
-	logger := zerolog.Ctx(r.Context())
-
-	values := r.URL.Query()
+func (n *news) parseQuery(values url.Values) service.Query {
 	query := service.Query{
 		Text: values.Get("text"),
 	}
@@ -73,6 +85,10 @@ func (n *news) List(w http.ResponseWriter, r *http.Request) {
 		copy(query.Tags, tags)
 	}
 
+	if dateField := service.DateField(values.Get("date_field")); dateField.IsValid() {
+		query.DateField = dateField
+	}
+
 	dateFrom := values.Get("date_from")
 	if dateFrom != "" {
 		dateFromObj, err := time.Parse(time.DateOnly, dateFrom)
@@ -89,6 +105,15 @@ func (n *news) List(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	return query
+}
+
+func (n *news) List(w http.ResponseWriter, r *http.Request) {
+	logger := zerolog.Ctx(r.Context())
+
+	values := r.URL.Query()
+	query := n.parseQuery(values)
+
 	var opts service.Options
 	if skip, ok := n.getInt(values, "skip"); ok {
 		opts.SetSkip(skip)
@@ -103,8 +128,15 @@ func (n *news) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	news, count, err := n.service.GetHead(r.Context(), query, opts)
+
+	var syntaxErr *service.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		httpserver.ErrorDetailsJSON(w, "invalid query syntax", syntaxErr, http.StatusBadRequest)
+		return
+	}
+
 	if err != nil {
-		ErrorJSON(w, "unexpected error while receiving data", http.StatusInternalServerError)
+		httpserver.ErrorJSON(w, "unexpected error while receiving data", http.StatusInternalServerError)
 		logger.Error().Err(err).Send()
 		return
 	}
@@ -117,21 +149,149 @@ func (n *news) List(w http.ResponseWriter, r *http.Request) {
 		TotalCount: count,
 	}
 
-	EncodeJSON(w, respData, http.StatusOK)
-
 	wantParse := query.Text != "" &&
 		(count < MIN_COUNT || (opts.GetSort() == 0 && opts.GetSkip() == 0 && n.isOutdated(news)))
 
-	if !wantParse {
+	if wantParse {
+		respData.ParseQueued = n.parse(r.Context(), service.ParseRequest{
+			Query:    query.Text,
+			DateFrom: query.DateFrom,
+			DateTo:   query.DateTo,
+			Targets:  query.Sources,
+			Priority: PARSE_PRIORITY,
+		})
+	}
+
+	httpserver.EncodeJSON(w, respData, http.StatusOK)
+}
+
+// parse creates job of request in background, so response does not wait
+// for job to be stored and sent. Request is dropped if too many jobs are created,
+// it reports whether request is queued.
+func (n *news) parse(ctx context.Context, req service.ParseRequest) bool {
+	logger := zerolog.Ctx(ctx)
+
+	select {
+	case n.parses <- struct{}{}:
+	default:
+		logger.Debug().Str("text", req.Query).Msg("message not sent, too many parses")
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), PARSE_TIMEOUT)
+	go func() {
+		defer func() { <-n.parses }()
+		defer cancel()
+
+		job, err := n.jobs.Create(ctx, req)
+		switch {
+		case err == nil:
+			logger.Info().Str("text", req.Query).Str("job", job.ID).Msg("message sent")
+		case errors.Is(err, service.ErrCooldown), errors.Is(err, service.ErrRateLimited):
+			logger.Debug().Err(err).Str("text", req.Query).Msg("message not sent")
+		case !errors.Is(err, service.ErrEmptyQuery) && !errors.Is(err, service.ErrInvalidSource):
+			logger.Error().Err(err).Send()
+		}
+	}()
+
+	return true
+}
+
+func (n *news) Facets(w http.ResponseWriter, r *http.Request) {
+	logger := zerolog.Ctx(r.Context())
+
+	values := r.URL.Query()
+	query := n.parseQuery(values)
+
+	var opts service.FacetOptions
+	if limit, ok := n.getInt(values, "limit"); ok {
+		opts.SetLimit(limit)
+	}
+
+	if interval := values.Get("interval"); interval != "" {
+		opts.SetInterval(interval)
+	}
+
+	facets, err := n.service.GetFacets(r.Context(), query, opts)
+
+	var syntaxErr *service.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		httpserver.ErrorDetailsJSON(w, "invalid query syntax", syntaxErr, http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		httpserver.ErrorJSON(w, "unexpected error while receiving data", http.StatusInternalServerError)
+		logger.Error().Err(err).Send()
 		return
 	}
 
-	err = n.service.SendToParse(r.Context(), query.Text)
+	httpserver.EncodeJSON(w, facets, http.StatusOK)
+}
+
+type RelatedResponse struct {
+	Results []entity.NewsHead `json:"results"`
+	Count   int               `json:"count"`
+}
+
+func (n *news) Related(w http.ResponseWriter, r *http.Request) {
+	logger := zerolog.Ctx(r.Context())
+	id := chi.URLParam(r, "id")
+
+	values := r.URL.Query()
+
+	var opts service.RelatedOptions
+	if limit, ok := n.getInt(values, "limit"); ok {
+		opts.SetLimit(limit)
+	}
+
+	if window, ok := n.getInt(values, "window"); ok {
+		opts.SetWindow(window)
+	}
+
+	news, err := n.service.GetRelated(r.Context(), id, opts)
 	if err != nil {
+		httpserver.ErrorJSON(w, "unexpected error while receiving data", http.StatusInternalServerError)
 		logger.Error().Err(err).Send()
 		return
 	}
-	logger.Info().Str("text", query.Text).Msg("message sent")
+
+	if news == nil {
+		httpserver.ErrorJSON(w, "news with given ID not found", http.StatusNotFound)
+		return
+	}
+
+	httpserver.EncodeJSON(w, &RelatedResponse{
+		Results: news,
+		Count:   len(news),
+	}, http.StatusOK)
+}
+
+type RevisionsResponse struct {
+	Results []entity.Revision `json:"results"`
+	Count   int               `json:"count"`
+}
+
+func (n *news) Revisions(w http.ResponseWriter, r *http.Request) {
+	logger := zerolog.Ctx(r.Context())
+	id := chi.URLParam(r, "id")
+
+	revisions, err := n.service.GetRevisions(r.Context(), id)
+	if err != nil {
+		httpserver.ErrorJSON(w, "unexpected error while receiving data", http.StatusInternalServerError)
+		logger.Error().Err(err).Send()
+		return
+	}
+
+	if revisions == nil {
+		httpserver.ErrorJSON(w, "news with given ID not found", http.StatusNotFound)
+		return
+	}
+
+	httpserver.EncodeJSON(w, &RevisionsResponse{
+		Results: revisions,
+		Count:   len(revisions),
+	}, http.StatusOK)
 }
 
 func (n *news) Get(w http.ResponseWriter, r *http.Request) {
@@ -140,15 +300,15 @@ func (n *news) Get(w http.ResponseWriter, r *http.Request) {
 
 	news, err := n.service.Get(r.Context(), id)
 	if err != nil {
-		ErrorJSON(w, "unexpected error while receiving data", http.StatusInternalServerError)
+		httpserver.ErrorJSON(w, "unexpected error while receiving data", http.StatusInternalServerError)
 		logger.Error().Err(err).Send()
 		return
 	}
 
 	if news == nil {
-		ErrorJSON(w, "news with given ID not found", http.StatusNotFound)
+		httpserver.ErrorJSON(w, "news with given ID not found", http.StatusNotFound)
 		return
 	}
 
-	EncodeJSON(w, news, http.StatusOK)
+	httpserver.EncodeJSON(w, news, http.StatusOK)
 }
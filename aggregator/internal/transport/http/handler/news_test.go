@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/aggregator/internal/service"
+)
+
+type newsService struct {
+	service.News
+}
+
+func (n *newsService) GetHead(ctx context.Context, query service.Query, opts service.Options) ([]entity.NewsHead, int, error) {
+	return []entity.NewsHead{}, 0, nil
+}
+
+// jobService blocks creation of jobs until it is released.
+type jobService struct {
+	service.ParseJob
+	release chan struct{}
+	created chan error
+}
+
+func (j *jobService) Create(ctx context.Context, req service.ParseRequest) (*entity.ParseJob, error) {
+	<-j.release
+	// error of context the job is created with
+	j.created <- ctx.Err()
+	return &entity.ParseJob{ID: "job", Query: req.Query}, nil
+}
+
+func TestListParsesInBackground(t *testing.T) {
+	jobs := &jobService{release: make(chan struct{}), created: make(chan error, 1)}
+	handler := NewNews(&newsService{}, jobs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/news?text=вакцина", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	// response is written while job is not created
+	handler.List(w, req)
+	cancel()
+
+	var resp GetResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if !resp.ParseQueued {
+		t.Error("parse request of thin result is not queued")
+	}
+
+	close(jobs.release)
+	select {
+	case err := <-jobs.created:
+		if err != nil {
+			t.Errorf("job is created with done context: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("job is not created")
+	}
+}
+
+func TestListParsesLimited(t *testing.T) {
+	jobs := &jobService{release: make(chan struct{}), created: make(chan error, MAX_PARSES)}
+	handler := NewNews(&newsService{}, jobs)
+	defer close(jobs.release)
+
+	for i := 0; i <= MAX_PARSES; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/news?text=вакцина", nil)
+		w := httptest.NewRecorder()
+		handler.List(w, req)
+
+		var resp GetResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+
+		// request over limit is dropped
+		if want := i < MAX_PARSES; resp.ParseQueued != want {
+			t.Fatalf("ParseQueued of request %d = %v, want %v", i, resp.ParseQueued, want)
+		}
+	}
+}
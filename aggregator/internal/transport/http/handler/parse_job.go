@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qsoulior/news/aggregator/internal/service"
+	"github.com/qsoulior/news/aggregator/pkg/httpserver"
+	"github.com/rs/zerolog"
+)
+
+type parseJob struct {
+	service service.ParseJob
+}
+
+func NewParseJob(service service.ParseJob) *parseJob {
+	return &parseJob{service}
+}
+
+type ParseJobRequest struct {
+	Query    string   `json:"query"`
+	Targets  []string `json:"targets"`
+	Depth    int      `json:"depth"`
+	DateFrom string   `json:"date_from"`
+	DateTo   string   `json:"date_to"`
+}
+
+func (p *parseJob) parseDate(value string) (*time.Time, bool) {
+	if value == "" {
+		return nil, true
+	}
+
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, false
+	}
+
+	return &date, true
+}
+
+func (p *parseJob) Create(w http.ResponseWriter, r *http.Request) {
+	logger := zerolog.Ctx(r.Context())
+
+	body, err := httpserver.DecodeJSON[ParseJobRequest](r)
+	if err != nil {
+		httpserver.ErrorJSON(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	query := strings.TrimSpace(body.Query)
+	if query == "" {
+		httpserver.ErrorJSON(w, "query is required", http.StatusBadRequest)
+		return
+	}
+
+	dateFrom, okFrom := p.parseDate(body.DateFrom)
+	dateTo, okTo := p.parseDate(body.DateTo)
+	if !okFrom || !okTo {
+		httpserver.ErrorJSON(w, "invalid date format", http.StatusBadRequest)
+		return
+	}
+
+	job, err := p.service.Create(r.Context(), service.ParseRequest{
+		Query:    query,
+		Targets:  body.Targets,
+		Depth:    max(body.Depth, 0),
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+		Priority: PARSE_PRIORITY,
+	})
+
+	if errors.Is(err, service.ErrEmptyQuery) || errors.Is(err, service.ErrInvalidSource) {
+		httpserver.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if errors.Is(err, service.ErrCooldown) || errors.Is(err, service.ErrRateLimited) {
+		httpserver.ErrorJSON(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+
+	if err != nil {
+		httpserver.ErrorJSON(w, "unexpected error while creating job", http.StatusInternalServerError)
+		logger.Error().Err(err).Send()
+		return
+	}
+
+	httpserver.EncodeJSON(w, job, http.StatusAccepted)
+}
+
+func (p *parseJob) Get(w http.ResponseWriter, r *http.Request) {
+	logger := zerolog.Ctx(r.Context())
+	id := chi.URLParam(r, "id")
+
+	job, err := p.service.Get(r.Context(), id)
+	if err != nil {
+		httpserver.ErrorJSON(w, "unexpected error while receiving data", http.StatusInternalServerError)
+		logger.Error().Err(err).Send()
+		return
+	}
+
+	if job == nil {
+		httpserver.ErrorJSON(w, "job with given ID not found", http.StatusNotFound)
+		return
+	}
+
+	httpserver.EncodeJSON(w, job, http.StatusOK)
+}
@@ -10,7 +10,7 @@ import (
 	"github.com/rs/cors"
 )
 
-func NewRouter(service service.News) http.Handler {
+func NewRouter(service service.News, jobService service.ParseJob) http.Handler {
 	mux := chi.NewMux()
 	mux.Use(cors.Default().Handler)
 	mux.Use(middleware.AllowContentType("application/json"))
@@ -18,9 +18,16 @@ func NewRouter(service service.News) http.Handler {
 	mux.Use(LoggerMiddleware())
 	mux.Use(RecovererMiddleware())
 
-	news := handler.NewNews(service)
+	news := handler.NewNews(service, jobService)
 	mux.Get("/news", news.List)
+	mux.Get("/news/facets", news.Facets)
 	mux.Get("/news/{id}", news.Get)
+	mux.Get("/news/{id}/related", news.Related)
+	mux.Get("/news/{id}/revisions", news.Revisions)
+
+	parseJob := handler.NewParseJob(jobService)
+	mux.Post("/parse-jobs", parseJob.Create)
+	mux.Get("/parse-jobs/{id}", parseJob.Get)
 
 	return mux
 }
@@ -10,6 +10,9 @@ db.createCollection("news", {
         description: {
           bsonType: "string",
         },
+        uid: {
+          bsonType: "string",
+        },
         link: {
           bsonType: "string",
         },
@@ -19,6 +22,15 @@ db.createCollection("news", {
         published_at: {
           bsonType: "date",
         },
+        modified_at: {
+          bsonType: "date",
+        },
+        fetched_at: {
+          bsonType: "date",
+        },
+        ingested_at: {
+          bsonType: "date",
+        },
         authors: {
           bsonType: "array",
           items: {
@@ -40,6 +52,9 @@ db.createCollection("news", {
         content: {
           bsonType: "string",
         },
+        changed: {
+          bsonType: "bool",
+        },
       },
     },
   },
@@ -0,0 +1,2 @@
+db.createCollection("parse_jobs");
+db.parse_jobs.createIndex({ created_at: 1 }, { expireAfterSeconds: 7 * 24 * 60 * 60 });
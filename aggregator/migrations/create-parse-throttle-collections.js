@@ -0,0 +1,5 @@
+db.createCollection("parse_cooldowns");
+db.parse_cooldowns.createIndex({ expires_at: 1 }, { expireAfterSeconds: 0 });
+
+db.createCollection("parse_budgets");
+db.parse_budgets.createIndex({ expires_at: 1 }, { expireAfterSeconds: 0 });
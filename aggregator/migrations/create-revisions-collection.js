@@ -0,0 +1,2 @@
+db.createCollection("news_revisions");
+db.news_revisions.createIndex({ news_id: 1, version: 1 }, { unique: true });
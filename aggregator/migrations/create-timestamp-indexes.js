@@ -0,0 +1,4 @@
+// Timestamps of news stored before they were introduced are backfilled by aggregator on startup.
+db.news.createIndex({ modified_at: -1 });
+db.news.createIndex({ fetched_at: -1 });
+db.news.createIndex({ ingested_at: -1 });
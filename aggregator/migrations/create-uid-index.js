@@ -0,0 +1,2 @@
+db.news.createIndex({ uid: 1 }, { unique: true, partialFilterExpression: { uid: { $type: "string" } } });
+db.news.createIndex({ link: 1 });
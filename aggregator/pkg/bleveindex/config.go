@@ -0,0 +1,10 @@
+package bleveindex
+
+import "github.com/blevesearch/bleve/v2/mapping"
+
+type Config struct {
+	Path    string
+	Mapping mapping.IndexMapping
+	// Version of mapping, index of another version is rebuilt.
+	Version string
+}
@@ -0,0 +1,66 @@
+package bleveindex
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/blevesearch/bleve/v2"
+)
+
+var versionKey = []byte("version")
+
+type Index struct {
+	bleve.Index
+	Created bool
+}
+
+// New opens index at cfg.Path or creates it with cfg.Mapping if it does not exist.
+// Index of another version is removed and created again.
+func New(cfg *Config) (*Index, error) {
+	index, err := bleve.Open(cfg.Path)
+	if err == nil {
+		version, err := index.GetInternal(versionKey)
+		if err != nil {
+			index.Close()
+			return nil, fmt.Errorf("index.GetInternal: %w", err)
+		}
+
+		if string(version) == cfg.Version {
+			return &Index{Index: index}, nil
+		}
+
+		if err := index.Close(); err != nil {
+			return nil, fmt.Errorf("index.Close: %w", err)
+		}
+
+		if err := os.RemoveAll(cfg.Path); err != nil {
+			return nil, fmt.Errorf("os.RemoveAll: %w", err)
+		}
+	} else if !errors.Is(err, bleve.ErrorIndexPathDoesNotExist) {
+		return nil, fmt.Errorf("bleve.Open: %w", err)
+	}
+
+	index, err = bleve.New(cfg.Path, cfg.Mapping)
+	if err != nil {
+		return nil, fmt.Errorf("bleve.New: %w", err)
+	}
+
+	if err := index.SetInternal(versionKey, []byte(cfg.Version)); err != nil {
+		index.Close()
+		return nil, fmt.Errorf("index.SetInternal: %w", err)
+	}
+
+	return &Index{Index: index, Created: true}, nil
+}
+
+func (i *Index) Close() error {
+	if i.Index != nil {
+		err := i.Index.Close()
+		if err != nil {
+			return fmt.Errorf("i.Index.Close: %w", err)
+		}
+	}
+
+	return nil
+}
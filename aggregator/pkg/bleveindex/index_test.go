@@ -0,0 +1,49 @@
+package bleveindex
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2"
+)
+
+func TestNewVersion(t *testing.T) {
+	cfg := &Config{
+		Path:    filepath.Join(t.TempDir(), "index"),
+		Mapping: bleve.NewIndexMapping(),
+		Version: "1",
+	}
+
+	open := func(created bool) *Index {
+		t.Helper()
+		index, err := New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if index.Created != created {
+			t.Fatalf("Created = %t, want %t", index.Created, created)
+		}
+		return index
+	}
+
+	index := open(true)
+	if err := index.Index.Index("a", map[string]string{"title": "a"}); err != nil {
+		t.Fatal(err)
+	}
+	index.Close()
+
+	// index of the same version is opened
+	index = open(false)
+	if count, _ := index.DocCount(); count != 1 {
+		t.Errorf("DocCount = %d, want 1", count)
+	}
+	index.Close()
+
+	// index of another version is rebuilt
+	cfg.Version = "2"
+	index = open(true)
+	if count, _ := index.DocCount(); count != 0 {
+		t.Errorf("DocCount of rebuilt index = %d, want 0", count)
+	}
+	index.Close()
+}
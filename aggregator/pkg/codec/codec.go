@@ -0,0 +1,53 @@
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+)
+
+type Codec interface {
+	ContentType() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+var (
+	ErrUnsupportedType     = errors.New("unsupported content type")
+	ErrUnsupportedEncoding = errors.New("unsupported content encoding")
+	ErrNotProto            = errors.New("value does not support protobuf encoding")
+)
+
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeMsgpack  = "application/msgpack"
+)
+
+var codecs = map[string]Codec{
+	ContentTypeJSON:           JSON{},
+	ContentTypeProtobuf:       Protobuf{},
+	"application/protobuf":    Protobuf{},
+	ContentTypeMsgpack:        Msgpack{},
+	"application/x-msgpack":   Msgpack{},
+	"application/vnd.msgpack": Msgpack{},
+}
+
+// Get returns codec for content type. Empty content type means JSON.
+func Get(contentType string) (Codec, error) {
+	if contentType == "" {
+		return JSON{}, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+
+	codec, ok := codecs[mediaType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+
+	return codec, nil
+}
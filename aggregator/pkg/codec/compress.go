@@ -0,0 +1,63 @@
+package codec
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	EncodingIdentity = ""
+	EncodingZstd     = "zstd"
+)
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+func initZstd() {
+	zstdEncoder, zstdErr = zstd.NewWriter(nil)
+	if zstdErr != nil {
+		return
+	}
+
+	zstdDecoder, zstdErr = zstd.NewReader(nil)
+}
+
+// Compress compresses data with content encoding.
+func Compress(encoding string, data []byte) ([]byte, error) {
+	switch encoding {
+	case EncodingIdentity, "identity":
+		return data, nil
+	case EncodingZstd:
+		zstdOnce.Do(initZstd)
+		if zstdErr != nil {
+			return nil, fmt.Errorf("zstd: %w", zstdErr)
+		}
+
+		return zstdEncoder.EncodeAll(data, nil), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, encoding)
+}
+
+// Decompress decompresses data with content encoding.
+func Decompress(encoding string, data []byte) ([]byte, error) {
+	switch encoding {
+	case EncodingIdentity, "identity":
+		return data, nil
+	case EncodingZstd:
+		zstdOnce.Do(initZstd)
+		if zstdErr != nil {
+			return nil, fmt.Errorf("zstd: %w", zstdErr)
+		}
+
+		return zstdDecoder.DecodeAll(data, nil)
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, encoding)
+}
@@ -0,0 +1,11 @@
+package codec
+
+import "encoding/json"
+
+type JSON struct{}
+
+func (JSON) ContentType() string { return ContentTypeJSON }
+
+func (JSON) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+func (JSON) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
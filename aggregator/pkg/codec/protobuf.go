@@ -0,0 +1,33 @@
+package codec
+
+// ProtoMarshaler is implemented by values encoded to protobuf wire format.
+type ProtoMarshaler interface {
+	MarshalProto() ([]byte, error)
+}
+
+type ProtoUnmarshaler interface {
+	UnmarshalProto(data []byte) error
+}
+
+// Protobuf encodes values implementing ProtoMarshaler and ProtoUnmarshaler.
+type Protobuf struct{}
+
+func (Protobuf) ContentType() string { return ContentTypeProtobuf }
+
+func (Protobuf) Marshal(v any) ([]byte, error) {
+	m, ok := v.(ProtoMarshaler)
+	if !ok {
+		return nil, ErrNotProto
+	}
+
+	return m.MarshalProto()
+}
+
+func (Protobuf) Unmarshal(data []byte, v any) error {
+	m, ok := v.(ProtoUnmarshaler)
+	if !ok {
+		return ErrNotProto
+	}
+
+	return m.UnmarshalProto(data)
+}
@@ -0,0 +1,215 @@
+package highlight
+
+import (
+	"html"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type Options struct {
+	FragmentSize int
+	MaxFragments int
+	PreTag       string
+	PostTag      string
+	Ellipsis     string
+}
+
+var (
+	DefaultFragmentSize = 150
+	DefaultMaxFragments = 3
+	DefaultPreTag       = "<mark>"
+	DefaultPostTag      = "</mark>"
+	DefaultEllipsis     = "…"
+)
+
+func (o *Options) SetDefault() {
+	if o.FragmentSize <= 0 {
+		o.FragmentSize = DefaultFragmentSize
+	}
+	if o.MaxFragments <= 0 {
+		o.MaxFragments = DefaultMaxFragments
+	}
+	if o.PreTag == "" && o.PostTag == "" {
+		o.PreTag, o.PostTag = DefaultPreTag, DefaultPostTag
+	}
+	if o.Ellipsis == "" {
+		o.Ellipsis = DefaultEllipsis
+	}
+}
+
+// Span is a matched part of text in byte offsets [Start, End).
+type Span struct {
+	Start int
+	End   int
+}
+
+// Fragments returns up to opts.MaxFragments parts of text around spans
+// with every span wrapped into opts.PreTag and opts.PostTag.
+// Text is HTML-escaped, tags and ellipsis are written as is.
+// Fragment size is measured in runes.
+func Fragments(text string, spans []Span, opts Options) []string {
+	spans = normalize(text, spans)
+	if len(spans) == 0 {
+		return nil
+	}
+
+	// byte offset to rune offset
+	runes := []rune(text)
+	offsets := make([]int, len(text)+1)
+	pos := 0
+	for i := range text {
+		offsets[i] = pos
+		pos++
+	}
+	offsets[len(text)] = pos
+
+	for i := range spans {
+		spans[i] = Span{offsets[spans[i].Start], offsets[spans[i].End]}
+	}
+
+	fragments := make([]string, 0, opts.MaxFragments)
+	for i := 0; i < len(spans) && len(fragments) < opts.MaxFragments; {
+		start, end := window(runes, spans[i], opts.FragmentSize)
+
+		var b strings.Builder
+		if start > 0 {
+			b.WriteString(opts.Ellipsis)
+		}
+
+		cursor := start
+		for ; i < len(spans) && spans[i].End <= end; i++ {
+			b.WriteString(html.EscapeString(string(runes[cursor:spans[i].Start])))
+			b.WriteString(opts.PreTag)
+			b.WriteString(html.EscapeString(string(runes[spans[i].Start:spans[i].End])))
+			b.WriteString(opts.PostTag)
+			cursor = spans[i].End
+		}
+		b.WriteString(html.EscapeString(string(runes[cursor:end])))
+
+		if end < len(runes) {
+			b.WriteString(opts.Ellipsis)
+		}
+
+		fragments = append(fragments, b.String())
+	}
+
+	return fragments
+}
+
+// normalize sorts spans, drops invalid ones and merges overlapping.
+func normalize(text string, spans []Span) []Span {
+	valid := make([]Span, 0, len(spans))
+	for _, span := range spans {
+		if span.Start >= 0 && span.Start < span.End && span.End <= len(text) {
+			valid = append(valid, span)
+		}
+	}
+
+	slices.SortFunc(valid, func(a, b Span) int { return a.Start - b.Start })
+
+	merged := valid[:0]
+	for _, span := range valid {
+		if n := len(merged); n > 0 && span.Start <= merged[n-1].End {
+			merged[n-1].End = max(merged[n-1].End, span.End)
+			continue
+		}
+		merged = append(merged, span)
+	}
+
+	return merged
+}
+
+// window returns rune range of fragment centered on span and aligned to words.
+func window(runes []rune, span Span, size int) (int, int) {
+	if len(runes) <= size {
+		return 0, len(runes)
+	}
+
+	start := max(0, span.Start-(size-(span.End-span.Start))/2)
+	end := min(len(runes), start+size)
+	start = max(0, min(start, end-size))
+
+	if start > 0 && !unicode.IsSpace(runes[start-1]) {
+		for i := start; i < span.Start; i++ {
+			if unicode.IsSpace(runes[i]) {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	if end < len(runes) && !unicode.IsSpace(runes[end]) {
+		for i := end - 1; i >= span.End; i-- {
+			if unicode.IsSpace(runes[i]) {
+				end = i
+				break
+			}
+		}
+	}
+
+	return start, max(end, span.End)
+}
+
+// Match returns spans of words of text starting with any of terms ignoring case.
+// It is used for backends not reporting match positions. Last rune of long terms
+// is dropped to match inflected forms of words, e.g. "вакцина" matches "вакцины".
+func Match(text string, terms []string) []Span {
+	prefixes := make([][]rune, 0, len(terms))
+	for _, term := range terms {
+		prefix := []rune(strings.ToLower(term))
+		if len(prefix) > 4 {
+			prefix = prefix[:len(prefix)-1]
+		}
+		if len(prefix) > 0 {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+
+	spans := make([]Span, 0)
+	var prev rune
+	for i, r := range text {
+		if isWord(r) && !isWord(prev) {
+			for _, prefix := range prefixes {
+				if n := hasPrefix(text[i:], prefix); n > 0 {
+					spans = append(spans, Span{i, wordEnd(text, i+n)})
+					break
+				}
+			}
+		}
+		prev = r
+	}
+
+	return spans
+}
+
+// hasPrefix returns byte length of lower-cased prefix of s or 0 if s doesn't start with it.
+func hasPrefix(s string, prefix []rune) int {
+	n := 0
+	for _, p := range prefix {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if size == 0 || unicode.ToLower(r) != p {
+			return 0
+		}
+		n += size
+	}
+
+	return n
+}
+
+func wordEnd(text string, i int) int {
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !isWord(r) {
+			break
+		}
+		i += size
+	}
+
+	return i
+}
+
+func isWord(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
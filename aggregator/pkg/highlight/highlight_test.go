@@ -0,0 +1,151 @@
+package highlight
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// spans returns spans of every occurrence of words in text.
+func spans(text string, words ...string) []Span {
+	result := make([]Span, 0)
+	for _, word := range words {
+		for i := 0; ; {
+			j := strings.Index(text[i:], word)
+			if j < 0 {
+				break
+			}
+			result = append(result, Span{i + j, i + j + len(word)})
+			i += j + len(word)
+		}
+	}
+	return result
+}
+
+func TestFragments(t *testing.T) {
+	opts := Options{FragmentSize: 20, MaxFragments: 2, PreTag: "[", PostTag: "]", Ellipsis: "..."}
+
+	tests := []struct {
+		name  string
+		text  string
+		spans []Span
+		want  []string
+	}{
+		{
+			name:  "no spans",
+			text:  "short text",
+			spans: nil,
+			want:  nil,
+		},
+		{
+			name:  "whole text",
+			text:  "short text",
+			spans: spans("short text", "text"),
+			want:  []string{"short [text]"},
+		},
+		{
+			name:  "text start",
+			text:  "alpha beta gamma delta epsilon zeta eta",
+			spans: spans("alpha beta gamma delta epsilon zeta eta", "alpha"),
+			want:  []string{"[alpha] beta gamma..."},
+		},
+		{
+			name:  "text end",
+			text:  "alpha beta gamma delta epsilon zeta eta",
+			spans: []Span{{36, 39}},
+			want:  []string{"...epsilon zeta [eta]"},
+		},
+		{
+			name:  "fragment aligned to words",
+			text:  "alpha beta gamma delta epsilon zeta eta",
+			spans: spans("alpha beta gamma delta epsilon zeta eta", "delta"),
+			want:  []string{"...gamma [delta] epsilon..."},
+		},
+		{
+			name:  "spans in one fragment",
+			text:  "alpha beta gamma delta epsilon zeta eta",
+			spans: spans("alpha beta gamma delta epsilon zeta eta", "beta", "alpha"),
+			want:  []string{"[alpha] [beta] gamma..."},
+		},
+		{
+			name:  "max fragments",
+			text:  "alpha beta gamma delta epsilon zeta eta theta iota kappa lambda",
+			spans: spans("alpha beta gamma delta epsilon zeta eta theta iota kappa lambda", "alpha", "zeta", "lambda"),
+			want:  []string{"[alpha] beta gamma...", "...epsilon [zeta] eta..."},
+		},
+		{
+			name:  "overlapping spans",
+			text:  "alpha beta",
+			spans: []Span{{0, 3}, {2, 5}, {6, 10}},
+			want:  []string{"[alpha] [beta]"},
+		},
+		{
+			name:  "invalid spans",
+			text:  "alpha beta",
+			spans: []Span{{-1, 3}, {5, 5}, {6, 11}},
+			want:  nil,
+		},
+		{
+			name:  "multibyte runes",
+			text:  "вакцина от гриппа поступила в аптеки города",
+			spans: spans("вакцина от гриппа поступила в аптеки города", "аптеки"),
+			want:  []string{"...в [аптеки] города"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Fragments(tt.text, tt.spans, opts)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Fragments = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFragmentsEscape(t *testing.T) {
+	var opts Options
+	opts.SetDefault()
+
+	text := `<script>alert("x")</script> & <b>news</b>`
+	got := Fragments(text, spans(text, "news"), opts)
+	want := []string{`&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; &lt;b&gt;<mark>news</mark>&lt;/b&gt;`}
+	if !slices.Equal(got, want) {
+		t.Errorf("Fragments = %q, want %q", got, want)
+	}
+
+	text = "a <img src=x onerror=alert(1)> b"
+	got = Fragments(text, spans(text, "<img"), opts)
+	want = []string{"a <mark>&lt;img</mark> src=x onerror=alert(1)&gt; b"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Fragments = %q, want %q", got, want)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		text  string
+		terms []string
+		want  []string
+	}{
+		{"Вакцины от гриппа", []string{"вакцина"}, []string{"Вакцины"}},
+		{"Путин и путинизм", []string{"Путин"}, []string{"Путин", "путинизм"}},
+		{"компьютер", []string{"пьют"}, nil},
+		{"Red Square, red squares", []string{"red square"}, []string{"Red Square", "red squares"}},
+		{"covid-19 cases", []string{"covid-19"}, []string{"covid-19"}},
+		{"nothing here", []string{""}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, span := range Match(tt.text, tt.terms) {
+				got = append(got, tt.text[span.Start:span.End])
+			}
+
+			if len(got) != len(tt.want) || (len(got) > 0 && !slices.Equal(got, tt.want)) {
+				t.Errorf("Match(%q, %q) = %q, want %q", tt.text, tt.terms, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+func DecodeJSON[T any](r *http.Request) (*T, error) {
+	defer r.Body.Close()
+	data := new(T)
+	d := json.NewDecoder(r.Body)
+
+	err := d.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("d.Decode: %w", err)
+	}
+
+	return data, nil
+}
+
+func EncodeJSON(w http.ResponseWriter, data any, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	e := json.NewEncoder(w)
+	e.Encode(data)
+}
+
+type JSONError struct {
+	Status  string `json:"status"`
+	Error   string `json:"error"`
+	Details any    `json:"details,omitempty"`
+}
+
+func ErrorJSON(w http.ResponseWriter, error string, code int) {
+	EncodeJSON(w, &JSONError{
+		Status: http.StatusText(code),
+		Error:  error,
+	}, code)
+}
+
+func ErrorDetailsJSON(w http.ResponseWriter, error string, details any, code int) {
+	EncodeJSON(w, &JSONError{
+		Status:  http.StatusText(code),
+		Error:   error,
+		Details: details,
+	}, code)
+}
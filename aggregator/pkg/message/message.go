@@ -0,0 +1,235 @@
+package message
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/aggregator/pkg/codec"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Schema versions of news message.
+// Version 1 is plain JSON encoding of entity.News without envelope.
+const (
+	Version1       = 1
+	Version2       = 2
+	CurrentVersion = Version2
+)
+
+// Envelope wraps news with metadata of parser produced it.
+type Envelope struct {
+	SchemaVersion int         `json:"schema_version"`
+	Source        string      `json:"source"`
+	ParserVersion string      `json:"parser_version"`
+	FetchedAt     time.Time   `json:"fetched_at"`
+	RequestID     string      `json:"request_id,omitempty"`
+	News          entity.News `json:"news"`
+}
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported schema version")
+	ErrMalformed          = errors.New("malformed message")
+)
+
+type ValidationError struct {
+	Version int
+	Err     error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("schema version %d: %s", e.Version, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+//go:embed schema/*.json
+var schemaFS embed.FS
+
+// schemaURL is base URL of embedded schemas.
+const schemaURL = "mem:///schema/"
+
+var schemas = map[int]*jsonschema.Schema{
+	Version1: mustCompile("news.json"),
+	Version2: mustCompile("envelope.v2.json"),
+}
+
+func mustCompile(name string) *jsonschema.Schema {
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat = true
+
+	entries, err := schemaFS.ReadDir("schema")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, entry := range entries {
+		data, err := schemaFS.ReadFile("schema/" + entry.Name())
+		if err != nil {
+			panic(err)
+		}
+
+		if err := compiler.AddResource(schemaURL+entry.Name(), bytes.NewReader(data)); err != nil {
+			panic(err)
+		}
+	}
+
+	return compiler.MustCompile(schemaURL + name)
+}
+
+// New returns envelope of current schema version.
+func New(news entity.News, parserVersion string) *Envelope {
+	fetchedAt := news.FetchedAt
+	if fetchedAt.IsZero() {
+		fetchedAt = time.Now().UTC()
+	}
+
+	return &Envelope{
+		SchemaVersion: CurrentVersion,
+		Source:        news.Source,
+		ParserVersion: parserVersion,
+		FetchedAt:     fetchedAt,
+		News:          news,
+	}
+}
+
+// Encode validates envelope against its schema and returns JSON body.
+func Encode(env *Envelope) ([]byte, error) {
+	body, err := json.Marshal(env)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	if _, err := validate(body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// Decode validates body against schema of its version and returns envelope.
+// Body of version 1 is wrapped into envelope.
+func Decode(body []byte) (*Envelope, error) {
+	version, err := validate(body)
+	if err != nil {
+		return nil, err
+	}
+
+	env := new(Envelope)
+	if version == Version1 {
+		if err := json.Unmarshal(body, &env.News); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+		}
+
+		env.SchemaVersion = Version1
+		env.Source = env.News.Source
+		env.FetchedAt = env.News.FetchedAt
+		return env, nil
+	}
+
+	if err := json.Unmarshal(body, env); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	env.fill()
+	return env, nil
+}
+
+// fill copies metadata of envelope to news. Fields of envelope take precedence.
+func (e *Envelope) fill() {
+	e.News.Source = e.Source
+	if e.News.FetchedAt.IsZero() {
+		e.News.FetchedAt = e.FetchedAt
+	}
+}
+
+// Marshal validates envelope and encodes it with codec of content type
+// compressed with content encoding.
+func Marshal(env *Envelope, contentType string, contentEncoding string) ([]byte, error) {
+	c, err := codec.Get(contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := Encode(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.ContentType() != codec.ContentTypeJSON {
+		body, err = c.Marshal(env)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+		}
+	}
+
+	return codec.Compress(contentEncoding, body)
+}
+
+// Unmarshal decodes body encoded with content type and content encoding
+// and validates it against schema of its version.
+func Unmarshal(body []byte, contentType string, contentEncoding string) (*Envelope, error) {
+	c, err := codec.Get(contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err = codec.Decompress(contentEncoding, body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	if c.ContentType() == codec.ContentTypeJSON {
+		return Decode(body)
+	}
+
+	env := new(Envelope)
+	if err := c.Unmarshal(body, env); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	// schema is defined for JSON representation
+	if _, err := Encode(env); err != nil {
+		return nil, err
+	}
+
+	env.fill()
+	return env, nil
+}
+
+// validate detects schema version of body and validates body against it.
+func validate(body []byte) (int, error) {
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	object, ok := doc.(map[string]any)
+	if !ok {
+		return 0, fmt.Errorf("%w: not an object", ErrMalformed)
+	}
+
+	version := Version1
+	if value, ok := object["schema_version"]; ok {
+		number, ok := value.(float64)
+		if !ok || number != float64(int(number)) {
+			return 0, fmt.Errorf("%w: %v", ErrUnsupportedVersion, value)
+		}
+		version = int(number)
+	}
+
+	schema, ok := schemas[version]
+	if !ok {
+		return 0, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+
+	if err := schema.Validate(doc); err != nil {
+		return version, &ValidationError{version, err}
+	}
+
+	return version, nil
+}
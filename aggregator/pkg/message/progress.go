@@ -0,0 +1,50 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/qsoulior/news/aggregator/entity"
+)
+
+// ProgressQueue receives progress of parse requests from parsers.
+const ProgressQueue = "parse.progress"
+
+// ParseProgress reports state of parse request in parser of source.
+type ParseProgress struct {
+	RequestID string               `json:"request_id"`
+	Source    string               `json:"source"`
+	State     entity.ParseJobState `json:"state"`
+	Count     int                  `json:"count"`
+	Error     string               `json:"error,omitempty"`
+	Timestamp time.Time            `json:"timestamp"`
+}
+
+func EncodeParseProgress(progress *ParseProgress) ([]byte, error) {
+	body, err := json.Marshal(progress)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	return body, nil
+}
+
+func DecodeParseProgress(body []byte) (*ParseProgress, error) {
+	progress := new(ParseProgress)
+	if err := json.Unmarshal(body, progress); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	if progress.RequestID == "" || progress.Source == "" {
+		return nil, fmt.Errorf("%w: missing request ID or source", ErrMalformed)
+	}
+
+	switch progress.State {
+	case entity.ParseJobRunning, entity.ParseJobDone, entity.ParseJobFailed:
+	default:
+		return nil, fmt.Errorf("%w: invalid state %q", ErrMalformed, progress.State)
+	}
+
+	return progress, nil
+}
@@ -0,0 +1,209 @@
+package message
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qsoulior/news/aggregator/entity"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Protobuf encoding follows schema/envelope.proto, tests check it against compiled schema.
+
+func (e *Envelope) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = appendVarint(b, 1, uint64(e.SchemaVersion))
+	b = appendString(b, 2, e.Source)
+	b = appendString(b, 3, e.ParserVersion)
+	b = appendTime(b, 4, e.FetchedAt)
+	b = protowire.AppendTag(b, 5, protowire.BytesType)
+	b = protowire.AppendBytes(b, marshalNews(&e.News))
+	b = appendString(b, 6, e.RequestID)
+	return b, nil
+}
+
+func (e *Envelope) UnmarshalProto(data []byte) error {
+	return consumeFields(data, func(num protowire.Number, typ protowire.Type, data []byte) (int, error) {
+		switch {
+		case num == 1 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(data)
+			e.SchemaVersion = int(v)
+			return n, nil
+		case num == 2 && typ == protowire.BytesType:
+			return consumeString(data, &e.Source)
+		case num == 3 && typ == protowire.BytesType:
+			return consumeString(data, &e.ParserVersion)
+		case num == 4 && typ == protowire.BytesType:
+			return consumeTime(data, &e.FetchedAt)
+		case num == 5 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return n, nil
+			}
+			return n, unmarshalNews(v, &e.News)
+		case num == 6 && typ == protowire.BytesType:
+			return consumeString(data, &e.RequestID)
+		}
+
+		return protowire.ConsumeFieldValue(num, typ, data), nil
+	})
+}
+
+func marshalNews(news *entity.News) []byte {
+	var b []byte
+	b = appendString(b, 1, news.Title)
+	b = appendString(b, 2, news.Description)
+	b = appendString(b, 3, news.Source)
+	b = appendString(b, 4, news.Link)
+	b = appendTime(b, 5, news.PublishedAt)
+	b = appendTime(b, 6, news.ModifiedAt)
+	b = appendTime(b, 7, news.FetchedAt)
+	b = appendStrings(b, 8, news.Authors)
+	b = appendStrings(b, 9, news.Tags)
+	b = appendStrings(b, 10, news.Categories)
+	b = appendString(b, 11, news.Content)
+	return b
+}
+
+func unmarshalNews(data []byte, news *entity.News) error {
+	return consumeFields(data, func(num protowire.Number, typ protowire.Type, data []byte) (int, error) {
+		if typ != protowire.BytesType {
+			return protowire.ConsumeFieldValue(num, typ, data), nil
+		}
+
+		switch num {
+		case 1:
+			return consumeString(data, &news.Title)
+		case 2:
+			return consumeString(data, &news.Description)
+		case 3:
+			return consumeString(data, &news.Source)
+		case 4:
+			return consumeString(data, &news.Link)
+		case 5:
+			return consumeTime(data, &news.PublishedAt)
+		case 6:
+			return consumeTime(data, &news.ModifiedAt)
+		case 7:
+			return consumeTime(data, &news.FetchedAt)
+		case 8:
+			return consumeStrings(data, &news.Authors)
+		case 9:
+			return consumeStrings(data, &news.Tags)
+		case 10:
+			return consumeStrings(data, &news.Categories)
+		case 11:
+			return consumeString(data, &news.Content)
+		}
+
+		return protowire.ConsumeFieldValue(num, typ, data), nil
+	})
+}
+
+// consumeFields calls fn for each field of message. fn returns length of consumed value.
+func consumeFields(data []byte, fn func(num protowire.Number, typ protowire.Type, data []byte) (int, error)) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return fmt.Errorf("%w: %w", ErrMalformed, protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		n, err := fn(num, typ, data)
+		if err != nil {
+			return err
+		}
+
+		if n < 0 {
+			return fmt.Errorf("%w: %w", ErrMalformed, protowire.ParseError(n))
+		}
+		data = data[n:]
+	}
+
+	return nil
+}
+
+func appendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+func appendStrings(b []byte, num protowire.Number, s []string) []byte {
+	for _, v := range s {
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		b = protowire.AppendString(b, v)
+	}
+
+	return b
+}
+
+func appendTime(b []byte, num protowire.Number, t time.Time) []byte {
+	if t.IsZero() {
+		return b
+	}
+
+	var ts []byte
+	ts = appendVarint(ts, 1, uint64(t.Unix()))
+	ts = appendVarint(ts, 2, uint64(t.Nanosecond()))
+
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, ts)
+}
+
+func consumeString(data []byte, s *string) (int, error) {
+	v, n := protowire.ConsumeString(data)
+	*s = v
+	return n, nil
+}
+
+func consumeStrings(data []byte, s *[]string) (int, error) {
+	v, n := protowire.ConsumeString(data)
+	if n >= 0 {
+		*s = append(*s, v)
+	}
+
+	return n, nil
+}
+
+func consumeTime(data []byte, t *time.Time) (int, error) {
+	v, n := protowire.ConsumeBytes(data)
+	if n < 0 {
+		return n, nil
+	}
+
+	var seconds, nanos uint64
+	err := consumeFields(v, func(num protowire.Number, typ protowire.Type, data []byte) (int, error) {
+		if typ != protowire.VarintType {
+			return protowire.ConsumeFieldValue(num, typ, data), nil
+		}
+
+		value, n := protowire.ConsumeVarint(data)
+		switch num {
+		case 1:
+			seconds = value
+		case 2:
+			nanos = value
+		}
+
+		return n, nil
+	})
+	if err != nil {
+		return n, err
+	}
+
+	*t = time.Unix(int64(seconds), int64(nanos)).UTC()
+	return n, nil
+}
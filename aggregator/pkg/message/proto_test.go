@@ -0,0 +1,209 @@
+package message
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/qsoulior/news/aggregator/entity"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// schemaMessage compiles schema/envelope.proto and returns its message by name.
+func schemaMessage(t *testing.T, name protoreflect.Name) protoreflect.MessageDescriptor {
+	t.Helper()
+	compiler := protocompile.Compiler{
+		Resolver: &protocompile.SourceResolver{ImportPaths: []string{"schema"}},
+	}
+
+	files, err := compiler.Compile(context.Background(), "envelope.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	desc := files[0].Messages().ByName(name)
+	if desc == nil {
+		t.Fatalf("message %s is not in schema", name)
+	}
+
+	return desc
+}
+
+func testEnvelope() *Envelope {
+	return &Envelope{
+		SchemaVersion: Version2,
+		Source:        "ria",
+		ParserVersion: "1.2.3",
+		FetchedAt:     time.Date(2024, 1, 2, 12, 4, 5, 6, time.UTC),
+		RequestID:     "request",
+		News: entity.News{
+			NewsHead: entity.NewsHead{
+				Title:       "Заголовок",
+				Description: "Описание",
+				Source:      "ria",
+				PublishedAt: time.Date(2024, 1, 2, 10, 0, 0, 100, time.UTC),
+				ModifiedAt:  time.Date(2024, 1, 2, 11, 0, 0, 500, time.UTC),
+			},
+			Link:       "https://ria.ru/20240102/news.html",
+			Authors:    []string{"Иванов", "Петров"},
+			Tags:       []string{"политика"},
+			Categories: []string{"мир", "экономика"},
+			Content:    "Текст новости",
+			FetchedAt:  time.Date(2024, 1, 2, 12, 4, 5, 6, time.UTC),
+		},
+	}
+}
+
+// schemaEnvelope builds message of schema with values of envelope.
+func schemaEnvelope(desc protoreflect.MessageDescriptor, e *Envelope) *dynamicpb.Message {
+	timestamp := func(fd protoreflect.FieldDescriptor, t time.Time) protoreflect.Value {
+		m := dynamicpb.NewMessage(fd.Message())
+		m.Set(fd.Message().Fields().ByName("seconds"), protoreflect.ValueOfInt64(t.Unix()))
+		m.Set(fd.Message().Fields().ByName("nanos"), protoreflect.ValueOfInt32(int32(t.Nanosecond())))
+		return protoreflect.ValueOfMessage(m)
+	}
+
+	strings := func(m *dynamicpb.Message, fd protoreflect.FieldDescriptor, s []string) {
+		list := m.Mutable(fd).List()
+		for _, v := range s {
+			list.Append(protoreflect.ValueOfString(v))
+		}
+	}
+
+	fields := desc.Fields()
+	envelope := dynamicpb.NewMessage(desc)
+	envelope.Set(fields.ByName("schema_version"), protoreflect.ValueOfInt32(int32(e.SchemaVersion)))
+	envelope.Set(fields.ByName("source"), protoreflect.ValueOfString(e.Source))
+	envelope.Set(fields.ByName("parser_version"), protoreflect.ValueOfString(e.ParserVersion))
+	envelope.Set(fields.ByName("fetched_at"), timestamp(fields.ByName("fetched_at"), e.FetchedAt))
+	envelope.Set(fields.ByName("request_id"), protoreflect.ValueOfString(e.RequestID))
+
+	newsDesc := fields.ByName("news").Message()
+	fields = newsDesc.Fields()
+	news := dynamicpb.NewMessage(newsDesc)
+	news.Set(fields.ByName("title"), protoreflect.ValueOfString(e.News.Title))
+	news.Set(fields.ByName("description"), protoreflect.ValueOfString(e.News.Description))
+	news.Set(fields.ByName("source"), protoreflect.ValueOfString(e.News.Source))
+	news.Set(fields.ByName("link"), protoreflect.ValueOfString(e.News.Link))
+	news.Set(fields.ByName("published_at"), timestamp(fields.ByName("published_at"), e.News.PublishedAt))
+	news.Set(fields.ByName("modified_at"), timestamp(fields.ByName("modified_at"), e.News.ModifiedAt))
+	news.Set(fields.ByName("fetched_at"), timestamp(fields.ByName("fetched_at"), e.News.FetchedAt))
+	strings(news, fields.ByName("authors"), e.News.Authors)
+	strings(news, fields.ByName("tags"), e.News.Tags)
+	strings(news, fields.ByName("categories"), e.News.Categories)
+	news.Set(fields.ByName("content"), protoreflect.ValueOfString(e.News.Content))
+	envelope.Set(desc.Fields().ByName("news"), protoreflect.ValueOfMessage(news))
+
+	return envelope
+}
+
+// populated reports first field of message or its submessages that is not set.
+func populated(m protoreflect.Message) (protoreflect.FullName, bool) {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !m.Has(fd) {
+			return fd.FullName(), false
+		}
+
+		if fd.Message() != nil && !fd.IsList() {
+			if name, ok := populated(m.Get(fd).Message()); !ok {
+				return name, false
+			}
+		}
+	}
+
+	return "", true
+}
+
+func TestEnvelopeProtoSchema(t *testing.T) {
+	desc := schemaMessage(t, "Envelope")
+	envelope := testEnvelope()
+	want := schemaEnvelope(desc, envelope)
+
+	// every field of schema is covered, so fields added to schema fail test
+	// until codec and this test encode them
+	if name, ok := populated(want); !ok {
+		t.Fatalf("field %s of schema is not encoded", name)
+	}
+
+	t.Run("marshal", func(t *testing.T) {
+		data, err := envelope.MarshalProto()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := dynamicpb.NewMessage(desc)
+		if err := proto.Unmarshal(data, got); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(got.GetUnknown()) > 0 {
+			t.Errorf("fields are not in schema: %v", got.GetUnknown())
+		}
+
+		if !proto.Equal(got, want) {
+			t.Errorf("MarshalProto decoded by schema = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		data, err := proto.Marshal(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := new(Envelope)
+		if err := got.UnmarshalProto(data); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(got, envelope) {
+			t.Errorf("UnmarshalProto of schema message = %+v, want %+v", got, envelope)
+		}
+	})
+}
+
+func TestEnvelopeProtoRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		envelope *Envelope
+	}{
+		{"full", testEnvelope()},
+		{"empty", &Envelope{}},
+		{"news only", &Envelope{SchemaVersion: Version2, News: entity.News{Link: "https://iz.ru/1"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.envelope.MarshalProto()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := new(Envelope)
+			if err := got.UnmarshalProto(data); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.envelope) {
+				t.Errorf("round trip = %+v, want %+v", got, tt.envelope)
+			}
+		})
+	}
+}
+
+func TestEnvelopeProtoMalformed(t *testing.T) {
+	data, err := testEnvelope().MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := new(Envelope).UnmarshalProto(data[:len(data)-1]); err == nil {
+		t.Error("UnmarshalProto of truncated message succeeded")
+	}
+}
@@ -0,0 +1,106 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/qsoulior/news/aggregator/pkg/codec"
+)
+
+// Parse requests are routed via topic exchange by key of target source.
+const (
+	ParseExchange   = "parse"
+	ParseRoutingAll = "parse.all"
+	MaxPriority     = 9
+)
+
+func ParseRoutingKey(source string) string {
+	return "parse." + source
+}
+
+// Parsers of previous release consume plain text queries from "query.<id>"
+// queues bound to fanout exchange. Aggregator publishes requests to both
+// exchanges until they are removed in next release.
+const LegacyParseExchange = "query"
+
+func LegacyParseQueue(source string) string {
+	return "query." + source
+}
+
+// ParseRequest asks parsers to search news.
+type ParseRequest struct {
+	ID       string     `json:"id"`
+	Query    string     `json:"query"`
+	Page     string     `json:"page,omitempty"`
+	Depth    int        `json:"depth,omitempty"`
+	DateFrom *time.Time `json:"date_from,omitempty"`
+	DateTo   *time.Time `json:"date_to,omitempty"`
+	// Targets are sources requested to parse, empty means all sources.
+	Targets   []string  `json:"targets,omitempty"`
+	Priority  uint8     `json:"priority,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+var parseRequestSchema = mustCompile("parse_request.json")
+
+func EncodeParseRequest(req *ParseRequest) ([]byte, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	if err := parseRequestSchema.Validate(doc); err != nil {
+		return nil, &ValidationError{CurrentVersion, err}
+	}
+
+	return body, nil
+}
+
+// DecodeParseRequest decodes and validates request.
+// Plain text body is accepted as query of legacy request.
+func DecodeParseRequest(body []byte, contentType string, id string) (*ParseRequest, error) {
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "text/plain" {
+		query := strings.TrimSpace(string(body))
+		if query == "" {
+			return nil, fmt.Errorf("%w: empty query", ErrMalformed)
+		}
+
+		return &ParseRequest{ID: id, Query: query}, nil
+	}
+
+	if c, err := codec.Get(contentType); err != nil {
+		return nil, err
+	} else if c.ContentType() != codec.ContentTypeJSON {
+		return nil, fmt.Errorf("%w: %s", codec.ErrUnsupportedType, contentType)
+	}
+
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	if err := parseRequestSchema.Validate(doc); err != nil {
+		return nil, &ValidationError{CurrentVersion, err}
+	}
+
+	req := new(ParseRequest)
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	return req, nil
+}
+
+// IsTarget reports whether request targets source.
+func (r *ParseRequest) IsTarget(source string) bool {
+	return len(r.Targets) == 0 || slices.Contains(r.Targets, source)
+}
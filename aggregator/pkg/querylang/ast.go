@@ -0,0 +1,111 @@
+package querylang
+
+import "time"
+
+type Field string
+
+const (
+	FieldAny         Field = ""
+	FieldTitle       Field = "title"
+	FieldDescription Field = "description"
+	FieldContent     Field = "content"
+	FieldSource      Field = "source"
+	FieldAuthor      Field = "author"
+	FieldTag         Field = "tag"
+	FieldCategory    Field = "category"
+	FieldAfter       Field = "after"
+	FieldBefore      Field = "before"
+	FieldDate        Field = "date"
+)
+
+var fieldAliases = map[string]Field{
+	"title":       FieldTitle,
+	"description": FieldDescription,
+	"content":     FieldContent,
+	"text":        FieldContent,
+	"source":      FieldSource,
+	"sources":     FieldSource,
+	"author":      FieldAuthor,
+	"authors":     FieldAuthor,
+	"tag":         FieldTag,
+	"tags":        FieldTag,
+	"category":    FieldCategory,
+	"categories":  FieldCategory,
+	"after":       FieldAfter,
+	"before":      FieldBefore,
+	"date":        FieldDate,
+}
+
+// IsText reports whether field values are matched against article text.
+func (f Field) IsText() bool {
+	return f == FieldAny || f == FieldTitle || f == FieldDescription || f == FieldContent
+}
+
+func (f Field) IsDate() bool {
+	return f == FieldAfter || f == FieldBefore || f == FieldDate
+}
+
+type Expr interface {
+	expr()
+}
+
+type And struct {
+	Exprs []Expr
+}
+
+type Or struct {
+	Exprs []Expr
+}
+
+type Not struct {
+	Expr Expr
+}
+
+type Term struct {
+	Field    Field
+	Value    string
+	Phrase   bool
+	Wildcard bool
+}
+
+// DateRange is a half-open interval [From, To), any bound may be nil.
+type DateRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+func (*And) expr()       {}
+func (*Or) expr()        {}
+func (*Not) expr()       {}
+func (*Term) expr()      {}
+func (*DateRange) expr() {}
+
+// Keywords returns positive text terms of expr that can be passed to
+// plain-text search engines.
+func Keywords(expr Expr) []string {
+	keywords := make([]string, 0)
+
+	var walk func(expr Expr)
+	walk = func(expr Expr) {
+		switch e := expr.(type) {
+		case *And:
+			for _, item := range e.Exprs {
+				walk(item)
+			}
+		case *Or:
+			for _, item := range e.Exprs {
+				walk(item)
+			}
+		case *Term:
+			if e.Field.IsText() && !e.Wildcard {
+				keywords = append(keywords, e.Value)
+			}
+		}
+	}
+
+	if expr != nil {
+		walk(expr)
+	}
+
+	return keywords
+}
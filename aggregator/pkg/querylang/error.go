@@ -0,0 +1,21 @@
+package querylang
+
+import "fmt"
+
+type SyntaxError struct {
+	Pos     int    `json:"pos"`
+	Token   string `json:"token,omitempty"`
+	Message string `json:"message"`
+}
+
+func newSyntaxError(tok token, message string) *SyntaxError {
+	return &SyntaxError{Pos: tok.pos, Token: tok.text, Message: message}
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("syntax error at position %d: %s", e.Pos, e.Message)
+	}
+
+	return fmt.Sprintf("syntax error at position %d near %q: %s", e.Pos, e.Token, e.Message)
+}
@@ -0,0 +1,124 @@
+package querylang
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenPhrase
+	tokenField
+	tokenLParen
+	tokenRParen
+	tokenMinus
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type lexer struct {
+	input []rune
+	pos   int
+}
+
+func newLexer(input string) *lexer {
+	return &lexer{input: []rune(input)}
+}
+
+func (l *lexer) isDelimiter(r rune) bool {
+	return unicode.IsSpace(r) || r == '(' || r == ')' || r == '"'
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) && unicode.IsSpace(l.input[l.pos]) {
+		l.pos++
+	}
+
+	if l.pos >= len(l.input) {
+		return token{kind: tokenEOF, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	switch r := l.input[l.pos]; {
+	case r == '(':
+		l.pos++
+		return token{kind: tokenLParen, text: "(", pos: start}, nil
+	case r == ')':
+		l.pos++
+		return token{kind: tokenRParen, text: ")", pos: start}, nil
+	case r == '"':
+		return l.phrase()
+	case r == '-' && l.pos+1 < len(l.input) && !unicode.IsSpace(l.input[l.pos+1]) && l.input[l.pos+1] != ')':
+		l.pos++
+		return token{kind: tokenMinus, text: "-", pos: start}, nil
+	}
+
+	return l.word()
+}
+
+func (l *lexer) phrase() (token, error) {
+	start := l.pos
+	l.pos++
+
+	var text strings.Builder
+	for ; l.pos < len(l.input); l.pos++ {
+		r := l.input[l.pos]
+		if r == '\\' && l.pos+1 < len(l.input) {
+			l.pos++
+			text.WriteRune(l.input[l.pos])
+			continue
+		}
+
+		if r == '"' {
+			l.pos++
+			return token{kind: tokenPhrase, text: text.String(), pos: start}, nil
+		}
+
+		text.WriteRune(r)
+	}
+
+	return token{}, &SyntaxError{Pos: start, Token: `"`, Message: "unterminated phrase"}
+}
+
+func (l *lexer) word() (token, error) {
+	start := l.pos
+	for ; l.pos < len(l.input) && !l.isDelimiter(l.input[l.pos]); l.pos++ {
+		if l.input[l.pos] != ':' {
+			continue
+		}
+
+		name := strings.ToLower(string(l.input[start:l.pos]))
+		if _, ok := fieldAliases[name]; !ok {
+			continue
+		}
+
+		l.pos++
+		if l.pos >= len(l.input) || unicode.IsSpace(l.input[l.pos]) || l.input[l.pos] == ')' {
+			return token{}, &SyntaxError{Pos: start, Token: name + ":", Message: "missing value for field"}
+		}
+
+		return token{kind: tokenField, text: name, pos: start}, nil
+	}
+
+	text := string(l.input[start:l.pos])
+	switch text {
+	case "AND", "&&":
+		return token{kind: tokenAnd, text: text, pos: start}, nil
+	case "OR", "||":
+		return token{kind: tokenOr, text: text, pos: start}, nil
+	case "NOT":
+		return token{kind: tokenNot, text: text, pos: start}, nil
+	}
+
+	return token{kind: tokenWord, text: text, pos: start}, nil
+}
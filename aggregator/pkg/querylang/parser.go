@@ -0,0 +1,349 @@
+package querylang
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type parser struct {
+	lexer *lexer
+	tok   token
+	now   time.Time
+}
+
+// Parse parses query language input into expression tree.
+// Empty input produces nil expression.
+func Parse(input string) (Expr, error) {
+	return ParseAt(input, time.Now())
+}
+
+// ParseAt is like Parse but resolves relative dates against now.
+// Input without operators, fields and phrases is plain text, so it
+// is parsed as plain text if it is not valid query.
+func ParseAt(input string, now time.Time) (Expr, error) {
+	expr, err := parseAt(input, now)
+	if err != nil && !hasSyntax(input) {
+		return Plain(input), nil
+	}
+
+	return expr, err
+}
+
+func parseAt(input string, now time.Time) (Expr, error) {
+	p := &parser{lexer: newLexer(input), now: now.UTC()}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind == tokenEOF {
+		return nil, nil
+	}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind != tokenEOF {
+		return nil, newSyntaxError(p.tok, "unexpected token")
+	}
+
+	return expr, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	expr, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := []Expr{expr}
+	for p.tok.kind == tokenOr {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+
+	return &Or{exprs}, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	expr, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := []Expr{expr}
+	for p.tok.kind != tokenEOF && p.tok.kind != tokenRParen && p.tok.kind != tokenOr {
+		if p.tok.kind == tokenAnd {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+
+	return &And{exprs}, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.tok.kind == tokenMinus || p.tok.kind == tokenNot {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &Not{expr}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokenLParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		if p.tok.kind == tokenRParen {
+			return nil, newSyntaxError(p.tok, "empty group")
+		}
+
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.tok.kind != tokenRParen {
+			return nil, newSyntaxError(tok, "unclosed parenthesis")
+		}
+
+		return expr, p.advance()
+	case tokenField:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		field := fieldAliases[tok.text]
+
+		// relative date is negative offset like "after:-3d"
+		if field.IsDate() && p.tok.kind == tokenMinus {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			p.tok.text = "-" + p.tok.text
+		}
+
+		if p.tok.kind != tokenWord && p.tok.kind != tokenPhrase {
+			return nil, newSyntaxError(tok, "missing value for field")
+		}
+
+		if field.IsDate() {
+			expr, err := p.parseDate(field, p.tok)
+			if err != nil {
+				return nil, err
+			}
+
+			return expr, p.advance()
+		}
+
+		return p.parseTerm(field)
+	case tokenWord, tokenPhrase:
+		return p.parseTerm(FieldAny)
+	case tokenEOF:
+		return nil, newSyntaxError(tok, "unexpected end of query")
+	}
+
+	return nil, newSyntaxError(tok, "unexpected token")
+}
+
+func (p *parser) parseTerm(field Field) (Expr, error) {
+	tok := p.tok
+	term := &Term{
+		Field:  field,
+		Value:  strings.TrimSpace(tok.text),
+		Phrase: tok.kind == tokenPhrase,
+	}
+
+	if term.Value == "" {
+		return nil, newSyntaxError(tok, "empty phrase")
+	}
+
+	// trailing question mark is punctuation of plain text
+	if value := strings.TrimRight(term.Value, "?"); !term.Phrase && value != "" {
+		term.Value = value
+	}
+
+	if !term.Phrase && strings.ContainsAny(term.Value, "*?") {
+		if strings.Trim(term.Value, "*?") == "" {
+			return nil, newSyntaxError(tok, "wildcard without text")
+		}
+		term.Wildcard = true
+	}
+
+	return term, p.advance()
+}
+
+var relativeDateRe = regexp.MustCompile(`^-?(\d+)([hdwmy])$`)
+
+func (p *parser) parseDate(field Field, tok token) (Expr, error) {
+	from, to, ok := p.dateBounds(strings.ToLower(tok.text))
+	if !ok {
+		return nil, newSyntaxError(tok, "invalid date value")
+	}
+
+	switch field {
+	case FieldAfter:
+		return &DateRange{From: &from}, nil
+	case FieldBefore:
+		return &DateRange{To: &from}, nil
+	}
+
+	return &DateRange{From: &from, To: to}, nil
+}
+
+// dateBounds returns start of period represented by value and
+// exclusive end of it if the period is bounded.
+func (p *parser) dateBounds(value string) (time.Time, *time.Time, bool) {
+	today := p.now.Truncate(24 * time.Hour)
+
+	switch value {
+	case "now":
+		return p.now, nil, true
+	case "today", "сегодня":
+		to := today.AddDate(0, 0, 1)
+		return today, &to, true
+	case "yesterday", "вчера":
+		return today.AddDate(0, 0, -1), &today, true
+	}
+
+	if match := relativeDateRe.FindStringSubmatch(value); match != nil {
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			return time.Time{}, nil, false
+		}
+
+		var from time.Time
+		switch match[2] {
+		case "h":
+			from = p.now.Add(-time.Duration(n) * time.Hour)
+		case "d":
+			from = p.now.AddDate(0, 0, -n)
+		case "w":
+			from = p.now.AddDate(0, 0, -7*n)
+		case "m":
+			from = p.now.AddDate(0, -n, 0)
+		case "y":
+			from = p.now.AddDate(-n, 0, 0)
+		}
+
+		return from, nil, true
+	}
+
+	layouts := []struct {
+		layout string
+		years  int
+		months int
+		days   int
+	}{
+		{time.DateOnly, 0, 0, 1},
+		{"02.01.2006", 0, 0, 1},
+		{"2006-01", 0, 1, 0},
+		{"2006", 1, 0, 0},
+	}
+
+	for _, l := range layouts {
+		from, err := time.Parse(l.layout, value)
+		if err == nil {
+			to := from.AddDate(l.years, l.months, l.days)
+			return from, &to, true
+		}
+	}
+
+	return time.Time{}, nil, false
+}
+
+// hasSyntax reports whether input uses operators, fields or phrases of query language.
+func hasSyntax(input string) bool {
+	l := newLexer(input)
+	for {
+		tok, err := l.next()
+		if err != nil {
+			// field without value is query, lone quote is plain text
+			var syntaxErr *SyntaxError
+			return errors.As(err, &syntaxErr) && syntaxErr.Token != `"`
+		}
+
+		if tok.kind == tokenEOF {
+			return false
+		}
+
+		switch tok.kind {
+		case tokenAnd, tokenOr, tokenNot, tokenField, tokenPhrase:
+			return true
+		}
+	}
+}
+
+// Plain returns conjunction of words of plain text input,
+// characters of query language are not special in it.
+func Plain(input string) Expr {
+	words := strings.FieldsFunc(input, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	})
+
+	exprs := make([]Expr, 0, len(words))
+	for _, word := range words {
+		if word = strings.Trim(word, "-"); word != "" {
+			exprs = append(exprs, &Term{Field: FieldAny, Value: word})
+		}
+	}
+
+	switch len(exprs) {
+	case 0:
+		return nil
+	case 1:
+		return exprs[0]
+	}
+
+	return &And{exprs}
+}
@@ -0,0 +1,166 @@
+package querylang
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// format prints expr in prefix notation to compare trees.
+func format(expr Expr) string {
+	switch e := expr.(type) {
+	case nil:
+		return "<nil>"
+	case *And:
+		return "AND(" + formatAll(e.Exprs) + ")"
+	case *Or:
+		return "OR(" + formatAll(e.Exprs) + ")"
+	case *Not:
+		return "NOT(" + format(e.Expr) + ")"
+	case *Term:
+		value := e.Value
+		if e.Phrase {
+			value = fmt.Sprintf("%q", value)
+		}
+		if e.Wildcard {
+			value += "~"
+		}
+		if e.Field != FieldAny {
+			value = string(e.Field) + ":" + value
+		}
+		return value
+	case *DateRange:
+		bound := func(t *time.Time) string {
+			if t == nil {
+				return "-"
+			}
+			return t.Format(time.DateTime)
+		}
+		return "[" + bound(e.From) + ", " + bound(e.To) + ")"
+	}
+
+	return fmt.Sprintf("%T", expr)
+}
+
+func formatAll(exprs []Expr) string {
+	items := make([]string, len(exprs))
+	for i, expr := range exprs {
+		items[i] = format(expr)
+	}
+	return strings.Join(items, " ")
+}
+
+var now = time.Date(2024, 3, 15, 12, 30, 0, 0, time.UTC)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "<nil>"},
+		{"путин", "путин"},
+		{"a b", "AND(a b)"},
+		{"a AND b", "AND(a b)"},
+		{"a && b", "AND(a b)"},
+		{"a OR b", "OR(a b)"},
+		{"a || b c", "OR(a AND(b c))"},
+		{"a b OR c", "OR(AND(a b) c)"},
+		{"a AND b OR c AND d", "OR(AND(a b) AND(c d))"},
+		{"a (b OR c)", "AND(a OR(b c))"},
+		{"(a OR b) -c", "AND(OR(a b) NOT(c))"},
+		{"NOT a", "NOT(a)"},
+		{"-a b", "AND(NOT(a) b)"},
+		{"- a", "AND(- a)"},
+		{"covid-19", "covid-19"},
+		{`"red square"`, `"red square"`},
+		{`"say \"hi\""`, `"say \"hi\""`},
+		{`a -"b c"`, `AND(a NOT("b c"))`},
+		{`title:"b c" source:ria`, `AND(title:"b c" source:ria)`},
+		{"TITLE:a", "title:a"},
+		{"tags:спорт", "tag:спорт"},
+		{"url:x", "url:x"},
+		{"вакцин*", "вакцин*~"},
+		{"w?man", "w?man~"},
+		{"что?", "что"},
+		{"after:2024-01-02", "[2024-01-02 00:00:00, -)"},
+		{"before:2024-01", "[-, 2024-01-01 00:00:00)"},
+		{"date:2024-01", "[2024-01-01 00:00:00, 2024-02-01 00:00:00)"},
+		{"date:2023", "[2023-01-01 00:00:00, 2024-01-01 00:00:00)"},
+		{"date:02.01.2024", "[2024-01-02 00:00:00, 2024-01-03 00:00:00)"},
+		{"date:today", "[2024-03-15 00:00:00, 2024-03-16 00:00:00)"},
+		{"date:вчера", "[2024-03-14 00:00:00, 2024-03-15 00:00:00)"},
+		{"after:3d", "[2024-03-12 12:30:00, -)"},
+		{"after:-2w", "[2024-03-01 12:30:00, -)"},
+		{"after:12h", "[2024-03-15 00:30:00, -)"},
+		{"after:1m", "[2024-02-15 12:30:00, -)"},
+		{"after:1y", "[2023-03-15 12:30:00, -)"},
+		{"before:now", "[-, 2024-03-15 12:30:00)"},
+		// plain text with stray characters of query language
+		{"путин (президент", "AND(путин президент)"},
+		{"ООО \"Ромашка", "AND(ООО Ромашка)"},
+		{"*", "<nil>"},
+		{"цена * 2", "AND(цена 2)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			expr, err := ParseAt(tt.input, now)
+			if err != nil {
+				t.Fatalf("ParseAt(%q) error: %v", tt.input, err)
+			}
+
+			if got := format(expr); got != tt.want {
+				t.Errorf("ParseAt(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSyntaxError(t *testing.T) {
+	tests := []struct {
+		input   string
+		pos     int
+		message string
+	}{
+		{"a AND (b", 6, "unclosed parenthesis"},
+		{"a OR b)", 6, "unexpected token"},
+		{"a AND ()", 7, "empty group"},
+		{"a OR", 4, "unexpected end of query"},
+		{"title: a", 0, "missing value for field"},
+		{`title:""`, 6, "empty phrase"},
+		{`a AND "b`, 6, "unterminated phrase"},
+		{"date:soon", 5, "invalid date value"},
+		{"a AND *", 6, "wildcard without text"},
+		{"NOT", 3, "unexpected end of query"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseAt(tt.input, now)
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("ParseAt(%q) error = %v, want SyntaxError", tt.input, err)
+			}
+
+			if syntaxErr.Pos != tt.pos || syntaxErr.Message != tt.message {
+				t.Errorf("ParseAt(%q) error at %d %q, want at %d %q",
+					tt.input, syntaxErr.Pos, syntaxErr.Message, tt.pos, tt.message)
+			}
+		})
+	}
+}
+
+func TestKeywords(t *testing.T) {
+	expr, err := ParseAt(`a -b title:c (d OR e*) "f g" source:ria after:1d`, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.Join(Keywords(expr), ",")
+	if want := "a,c,d,f g"; got != want {
+		t.Errorf("Keywords = %s, want %s", got, want)
+	}
+}
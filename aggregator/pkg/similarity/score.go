@@ -0,0 +1,100 @@
+package similarity
+
+import (
+	"math"
+	"strings"
+)
+
+type Vector map[string]float64
+
+// TFIDF weights tokens of documents by inverse document frequency within a corpus.
+type TFIDF struct {
+	idf  map[string]float64
+	size int
+}
+
+func NewTFIDF(docs [][]string) *TFIDF {
+	df := make(map[string]int)
+	for _, doc := range docs {
+		seen := make(map[string]struct{}, len(doc))
+		for _, token := range doc {
+			if _, ok := seen[token]; !ok {
+				seen[token] = struct{}{}
+				df[token]++
+			}
+		}
+	}
+
+	idf := make(map[string]float64, len(df))
+	for token, count := range df {
+		idf[token] = math.Log(float64(1+len(docs))/float64(1+count)) + 1
+	}
+
+	return &TFIDF{idf: idf, size: len(docs)}
+}
+
+func (t *TFIDF) Vector(doc []string) Vector {
+	vector := make(Vector, len(doc))
+	for _, token := range doc {
+		vector[token]++
+	}
+
+	for token, tf := range vector {
+		idf, ok := t.idf[token]
+		if !ok {
+			idf = math.Log(float64(1+t.size)) + 1
+		}
+		vector[token] = tf * idf
+	}
+
+	return vector
+}
+
+func Cosine(a, b Vector) float64 {
+	var dot, normA, normB float64
+	for token, weight := range a {
+		normA += weight * weight
+		dot += weight * b[token]
+	}
+
+	for _, weight := range b {
+		normB += weight * weight
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// Jaccard returns case-insensitive similarity of two sets.
+func Jaccard(a, b []string) float64 {
+	set := make(map[string]struct{}, len(a))
+	for _, item := range a {
+		set[strings.ToLower(item)] = struct{}{}
+	}
+
+	union := len(set)
+	intersection := 0
+	seen := make(map[string]struct{}, len(b))
+	for _, item := range b {
+		item = strings.ToLower(item)
+		if _, ok := seen[item]; ok {
+			continue
+		}
+		seen[item] = struct{}{}
+
+		if _, ok := set[item]; ok {
+			intersection++
+		} else {
+			union++
+		}
+	}
+
+	if union == 0 {
+		return 0
+	}
+
+	return float64(intersection) / float64(union)
+}
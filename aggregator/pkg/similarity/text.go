@@ -0,0 +1,102 @@
+package similarity
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// stemSize limits token length to merge inflected forms of words.
+const stemSize = 6
+
+var stopWords = map[string]struct{}{
+	"для": {}, "что": {}, "как": {}, "это": {}, "его": {}, "она": {}, "они": {}, "было": {}, "был": {},
+	"была": {}, "были": {}, "быть": {}, "будет": {}, "так": {}, "все": {}, "всех": {}, "при": {}, "или": {},
+	"еще": {}, "уже": {}, "также": {}, "который": {}, "которые": {}, "которая": {}, "которое": {},
+	"этот": {}, "эти": {}, "этом": {}, "этого": {}, "этой": {}, "тем": {}, "чем": {}, "после": {},
+	"перед": {}, "через": {}, "между": {}, "над": {}, "под": {}, "про": {}, "без": {}, "того": {},
+	"только": {}, "если": {}, "когда": {}, "где": {}, "кто": {}, "них": {}, "нее": {}, "него": {},
+	"заявил": {}, "заявила": {}, "сообщил": {}, "сообщила": {}, "говорится": {},
+	"the": {}, "and": {}, "for": {}, "with": {},
+}
+
+func split(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Tokenize splits text into lowercase stemmed tokens without stop words.
+func Tokenize(text string) []string {
+	words := split(text)
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.ToLower(word)
+		if len([]rune(word)) < 3 {
+			continue
+		}
+
+		if _, ok := stopWords[word]; ok {
+			continue
+		}
+
+		tokens = append(tokens, stem(word))
+	}
+
+	return tokens
+}
+
+func stem(word string) string {
+	runes := []rune(word)
+	if len(runes) > stemSize {
+		return string(runes[:stemSize])
+	}
+
+	return word
+}
+
+// Keywords returns up to limit most frequent tokens of text.
+func Keywords(text string, limit int) []string {
+	freq := make(map[string]int)
+	for _, token := range Tokenize(text) {
+		freq[token]++
+	}
+
+	keywords := make([]string, 0, len(freq))
+	for token := range freq {
+		keywords = append(keywords, token)
+	}
+
+	slices.SortFunc(keywords, func(a, b string) int {
+		if freq[a] != freq[b] {
+			return freq[b] - freq[a]
+		}
+		return strings.Compare(a, b)
+	})
+
+	return keywords[:min(limit, len(keywords))]
+}
+
+// Entities returns capitalized words that do not start a sentence,
+// which is a cheap approximation of named entities.
+func Entities(text string) []string {
+	entities := make([]string, 0)
+	sentenceStart := true
+
+	for _, field := range strings.Fields(text) {
+		word := strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+
+		runes := []rune(word)
+		if len(runes) > 1 && unicode.IsUpper(runes[0]) && !sentenceStart {
+			entities = append(entities, stem(strings.ToLower(word)))
+		}
+
+		last, _ := utf8.DecodeLastRuneInString(field)
+		sentenceStart = strings.ContainsRune(".!?…", last)
+	}
+
+	return entities
+}
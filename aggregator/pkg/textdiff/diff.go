@@ -0,0 +1,138 @@
+package textdiff
+
+import "strings"
+
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+type Change struct {
+	Op   Op
+	Text string
+}
+
+// maxCells limits memory used by LCS table to 4 MiB,
+// larger changed parts are replaced entirely.
+const maxCells = 1 << 20
+
+func Words(a, b string) []Change {
+	return Diff(strings.Fields(a), strings.Fields(b), " ")
+}
+
+func Lines(a, b string) []Change {
+	return Diff(strings.Split(a, "\n"), strings.Split(b, "\n"), "\n")
+}
+
+// Diff returns changes transforming tokens a into tokens b.
+// Consecutive tokens with the same operation are joined with sep.
+func Diff(a, b []string, sep string) []Change {
+	// common prefix and suffix
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]Op, 0, len(a)+len(b))
+	tokens := make([]string, 0, len(a)+len(b))
+	push := func(op Op, token string) {
+		ops = append(ops, op)
+		tokens = append(tokens, token)
+	}
+
+	for _, token := range a[:prefix] {
+		push(OpEqual, token)
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA)*len(midB) > maxCells {
+		for _, token := range midA {
+			push(OpDelete, token)
+		}
+		for _, token := range midB {
+			push(OpInsert, token)
+		}
+	} else {
+		lcs(midA, midB, push)
+	}
+
+	for _, token := range a[len(a)-suffix:] {
+		push(OpEqual, token)
+	}
+
+	return merge(ops, tokens, sep)
+}
+
+func lcs(a, b []string, push func(op Op, token string)) {
+	n, m := len(a), len(b)
+
+	// table[i*(m+1)+j] is length of LCS of a[i:] and b[j:]
+	width := m + 1
+	table := make([]int32, (n+1)*width)
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i*width+j] = table[(i+1)*width+j+1] + 1
+			} else {
+				table[i*width+j] = max(table[(i+1)*width+j], table[i*width+j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			push(OpEqual, a[i])
+			i++
+			j++
+		case table[(i+1)*width+j] >= table[i*width+j+1]:
+			push(OpDelete, a[i])
+			i++
+		default:
+			push(OpInsert, b[j])
+			j++
+		}
+	}
+
+	for ; i < n; i++ {
+		push(OpDelete, a[i])
+	}
+	for ; j < m; j++ {
+		push(OpInsert, b[j])
+	}
+}
+
+func merge(ops []Op, tokens []string, sep string) []Change {
+	changes := make([]Change, 0)
+	for i := 0; i < len(ops); {
+		j := i
+		for j < len(ops) && ops[j] == ops[i] {
+			j++
+		}
+
+		changes = append(changes, Change{ops[i], strings.Join(tokens[i:j], sep)})
+		i = j
+	}
+
+	return changes
+}
+
+// Changed reports whether changes contain insertions or deletions.
+func Changed(changes []Change) bool {
+	for _, change := range changes {
+		if change.Op != OpEqual {
+			return true
+		}
+	}
+
+	return false
+}
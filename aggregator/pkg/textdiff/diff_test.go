@@ -0,0 +1,86 @@
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// format prints changes as "=equal", "+insert" and "-delete" items.
+func format(changes []Change) string {
+	signs := map[Op]string{OpEqual: "=", OpInsert: "+", OpDelete: "-"}
+	items := make([]string, len(changes))
+	for i, change := range changes {
+		items[i] = signs[change.Op] + change.Text
+	}
+	return strings.Join(items, "|")
+}
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"", "", ""},
+		{"a b c", "a b c", "=a b c"},
+		{"", "a b", "+a b"},
+		{"a b", "", "-a b"},
+		{"a b c", "a x c", "=a|-b|+x|=c"},
+		{"a b c d", "a c d e", "=a|-b|=c d|+e"},
+		{"a  b\tc", "a b c", "=a b c"},
+		{"Путин провёл встречу", "Путин провёл совещание", "=Путин провёл|-встречу|+совещание"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := format(Words(tt.a, tt.b)); got != tt.want {
+				t.Errorf("Words(%q, %q) = %s, want %s", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLines(t *testing.T) {
+	a := "first\nsecond\nthird"
+	b := "first\nchanged\nthird\nfourth"
+
+	want := "=first|-second|+changed|=third|+fourth"
+	if got := format(Lines(a, b)); got != want {
+		t.Errorf("Lines = %s, want %s", got, want)
+	}
+}
+
+func TestDiffLimit(t *testing.T) {
+	a := make([]string, 2000)
+	b := make([]string, 2000)
+	for i := range a {
+		a[i] = fmt.Sprint("a", i)
+		b[i] = fmt.Sprint("b", i)
+	}
+
+	// common prefix and suffix are kept, middle exceeding limit is replaced
+	a = append(append([]string{"x"}, a...), "y")
+	b = append(append([]string{"x"}, b...), "y")
+
+	changes := Diff(a, b, " ")
+	if len(changes) != 4 {
+		t.Fatalf("Diff returned %d changes, want 4", len(changes))
+	}
+
+	ops := []Op{OpEqual, OpDelete, OpInsert, OpEqual}
+	for i, change := range changes {
+		if change.Op != ops[i] {
+			t.Errorf("change %d op = %s, want %s", i, change.Op, ops[i])
+		}
+	}
+}
+
+func TestChanged(t *testing.T) {
+	if Changed(Words("a b", "a b")) {
+		t.Error("Changed of equal texts = true")
+	}
+
+	if !Changed(Words("a b", "a c")) {
+		t.Error("Changed of different texts = false")
+	}
+}
@@ -0,0 +1,171 @@
+package urlcanon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+)
+
+var ErrNotAbsolute = errors.New("url is not absolute")
+
+// trackingParams are query parameters removed from canonical URL.
+var trackingParams = map[string]struct{}{
+	"fbclid":    {},
+	"gclid":     {},
+	"yclid":     {},
+	"ysclid":    {},
+	"dclid":     {},
+	"msclkid":   {},
+	"igshid":    {},
+	"mc_cid":    {},
+	"mc_eid":    {},
+	"_openstat": {},
+	"amp":       {},
+}
+
+// hostPrefixes are subdomains of mobile, AMP and www versions of site.
+var hostPrefixes = []string{"www.", "m.", "mobile.", "amp."}
+
+// Canonicalize returns normalized absolute URL: https scheme, lowercase host
+// without www, mobile and AMP prefixes and default port, clean path without
+// AMP suffix and sorted query without tracking parameters and fragment.
+func Canonicalize(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", err
+	}
+
+	if !u.IsAbs() || u.Host == "" {
+		return "", ErrNotAbsolute
+	}
+
+	u.Scheme = "https"
+	u.Host = normalizeHost(u.Host)
+	u.User = nil
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	u.Path = normalizePath(u.Path)
+	u.RawPath = ""
+
+	u.RawQuery = normalizeQuery(u.Query())
+	return u.String(), nil
+}
+
+// Resolve returns absolute URL declared by page with link rel=canonical href
+// relative to base or base if it is not declared. Declared URL of another site
+// is ignored. URL is not normalized, use UID to identify it.
+func Resolve(base string, canonical string) (string, error) {
+	baseURL, err := Canonicalize(base)
+	if err != nil {
+		return "", err
+	}
+
+	if canonical == "" {
+		return base, nil
+	}
+
+	u, err := url.Parse(base)
+	if err != nil {
+		return base, nil
+	}
+
+	ref, err := url.Parse(strings.TrimSpace(canonical))
+	if err != nil {
+		return base, nil
+	}
+
+	resolved := u.ResolveReference(ref).String()
+	canonicalURL, err := Canonicalize(resolved)
+	if err != nil || Host(canonicalURL) != Host(baseURL) {
+		return base, nil
+	}
+
+	return resolved, nil
+}
+
+// Host returns host of URL.
+func Host(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+
+	return u.Host
+}
+
+// UID returns stable identifier of URL, variants of same URL have same identifier.
+// Raw URL is identified as is if it is not absolute.
+func UID(raw string) string {
+	if canonical, err := Canonicalize(raw); err == nil {
+		return ID(canonical)
+	}
+
+	return ID(raw)
+}
+
+// ID returns stable identifier of canonical URL.
+func ID(canonical string) string {
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:16])
+}
+
+func normalizeHost(host string) string {
+	host = strings.ToLower(host)
+	if h, port, err := net.SplitHostPort(host); err == nil && (port == "80" || port == "443") {
+		host = h
+	}
+
+	host = strings.TrimSuffix(host, ".")
+	for trimmed := true; trimmed; {
+		trimmed = false
+		for _, prefix := range hostPrefixes {
+			rest, ok := strings.CutPrefix(host, prefix)
+			if ok && strings.Contains(rest, ".") {
+				host, trimmed = rest, true
+			}
+		}
+	}
+
+	return host
+}
+
+func normalizePath(p string) string {
+	if p == "" {
+		return "/"
+	}
+
+	trailingSlash := strings.HasSuffix(p, "/")
+	p = path.Clean("/" + p)
+
+	if rest, ok := strings.CutSuffix(p, "/amp"); ok && rest != "" {
+		p = rest
+	}
+
+	if p != "/" && trailingSlash {
+		p += "/"
+	}
+
+	return p
+}
+
+func normalizeQuery(values url.Values) string {
+	for key := range values {
+		lower := strings.ToLower(key)
+		if _, ok := trackingParams[lower]; ok || strings.HasPrefix(lower, "utm_") {
+			values.Del(key)
+		}
+	}
+
+	for _, v := range values {
+		slices.Sort(v)
+	}
+
+	// Encode sorts by key
+	return values.Encode()
+}
@@ -5,28 +5,56 @@ import (
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/qsoulior/news/parser/app"
 )
 
 type (
 	Config struct {
-		RabbitMQ ConfigRabbitMQ `yaml:"rabbitmq"`
-		Redis    ConfigRedis    `yaml:"redis"`
-		Service  ConfigService  `yaml:"service"`
+		RabbitMQ ConfigRabbitMQ  `yaml:"rabbitmq"`
+		Redis    ConfigRedis     `yaml:"redis"`
+		Service  ConfigService   `yaml:"service"`
+		HTTP     app.HTTPOptions `yaml:"http"`
+		Admin    ConfigAdmin     `yaml:"admin"`
 	}
 
 	ConfigService struct {
-		URL          string        `yaml:"url"`
-		FeedDelay    time.Duration `yaml:"feed_delay"`
-		ArchiveDelay time.Duration `yaml:"archive_delay"`
+		URL            string        `yaml:"url"`
+		FeedDelay      time.Duration `yaml:"feed_delay"`
+		ArchiveDelay   time.Duration `yaml:"archive_delay"`
+		SearchDepth    int           `yaml:"search_depth"`
+		MaxSearchDepth int           `yaml:"max_search_depth"`
+
+		// Timezone of dates without zone, zone of site is used if it is empty.
+		Timezone string `yaml:"timezone"`
+
+		// Backfill crawls feed by days from newest date to oldest date
+		// split into shards of given number of days.
+		Backfill struct {
+			From string `yaml:"from"`
+			To   string `yaml:"to"`
+			Days int    `yaml:"days"`
+		} `yaml:"backfill"`
+
+		FeedSchedule    app.ScheduleOptions `yaml:"feed_schedule"`
+		ArchiveSchedule app.ScheduleOptions `yaml:"archive_schedule"`
 	}
 
 	ConfigRabbitMQ struct {
-		URL string `yaml:"url"`
+		URL             string `yaml:"url"`
+		ContentType     string `yaml:"content_type"`
+		ContentEncoding string `yaml:"content_encoding"`
 	}
 
 	ConfigRedis struct {
 		URL string `yaml:"url"`
 	}
+
+	// ConfigAdmin is address of admin API, token is required on non-loopback host.
+	ConfigAdmin struct {
+		Host  string `yaml:"host"`
+		Port  string `yaml:"port"`
+		Token string `yaml:"token" env:"ADMIN_TOKEN"`
+	}
 )
 
 func NewConfig(path string) (*Config, error) {
@@ -10,9 +10,9 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/DataHenHQ/useragent"
 	"github.com/PuerkitoBio/goquery"
 	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/parser/pkg/dateparse"
 	"github.com/qsoulior/news/parser/pkg/httpclient"
 	"github.com/qsoulior/news/parser/pkg/httpclient/httpresponse"
 	"github.com/rs/zerolog"
@@ -22,11 +22,12 @@ type newsArchive struct {
 	*news
 }
 
-func NewNewsArchive(appID string, client *httpclient.Client, logger *zerolog.Logger) *newsArchive {
+func NewNewsArchive(appID string, client *httpclient.Client, dates *dateparse.Parser, logger *zerolog.Logger) *newsArchive {
 	log := logger.With().Str("service", "archive").Logger()
 	news := &news{
 		appID:  appID,
 		client: client,
+		dates:  dates,
 		logger: &log,
 	}
 
@@ -74,13 +75,7 @@ func (n *newsArchive) parseURLs(ctx context.Context, page string) ([]string, err
 	reqData.Set("view_display_id", "page_feed")
 	reqData.Set("page", page)
 
-	ua, err := useragent.Desktop()
-	if err != nil {
-		return nil, fmt.Errorf("useragent.Desktop: %w", err)
-	}
-
 	resp, err := n.client.Post(ctx, u.String(), strings.NewReader(reqData.Encode()), map[string]string{
-		"User-Agent":   ua,
 		"Content-Type": "application/x-www-form-urlencoded; charset=UTF-8",
 	})
 
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/parser/pkg/dateparse"
+	"github.com/qsoulior/news/parser/pkg/httpclient"
+	"github.com/rs/zerolog"
+)
+
+// DAY_LAYOUT is format of pages of day archive.
+const DAY_LAYOUT = "20060102"
+
+// DAY_MAX_PAGES limits number of feed pages of single day,
+// DAY_MAX_SEARCH_PAGE limits depth of feed searched for day.
+const (
+	DAY_MAX_PAGES       = 50
+	DAY_MAX_SEARCH_PAGE = 1 << 16
+)
+
+// urlDate matches date in news URL "/1612345/2024-01-01/slug".
+var urlDate = regexp.MustCompile(`^/\d+/(\d{4}-\d{2}-\d{2})/`)
+
+// newsDays parses feed of site by days from newest day to oldest one.
+// Feed is not split by days, so page of day is searched by dates in URLs
+// starting from page of previous day. News of day are listed once
+// and removed only after they are parsed.
+type newsDays struct {
+	*newsArchive
+
+	day  string
+	urls []string
+	hint int
+}
+
+func NewNewsDays(appID string, client *httpclient.Client, dates *dateparse.Parser, logger *zerolog.Logger) *newsDays {
+	log := logger.With().Str("service", "days").Logger()
+
+	news := &news{
+		appID:  appID,
+		client: client,
+		dates:  dates,
+		logger: &log,
+	}
+
+	days := &newsDays{
+		newsArchive: &newsArchive{news: news},
+	}
+
+	return days
+}
+
+func (n *newsDays) Parse(ctx context.Context, query string, page string) ([]entity.News, string, error) {
+	day, err := time.Parse(DAY_LAYOUT, page)
+	if err != nil {
+		return nil, "", fmt.Errorf("time.Parse: %w", err)
+	}
+
+	if n.day != page {
+		urls, err := n.parseDay(ctx, day.Format(time.DateOnly))
+		if err != nil {
+			return nil, "", err
+		}
+
+		n.day, n.urls = page, urls
+	}
+
+	const limit = 20
+	urls := n.urls[:min(len(n.urls), limit)]
+
+	news, err := n.parseMany(ctx, urls)
+	if err != nil {
+		return nil, "", fmt.Errorf("n.parseMany: %w", err)
+	}
+
+	n.urls = n.urls[len(urls):]
+	if len(n.urls) > 0 {
+		return news, page, nil
+	}
+
+	n.day = ""
+	return news, day.AddDate(0, 0, -1).Format(DAY_LAYOUT), nil
+}
+
+// feedPage is page of feed with date of its oldest news.
+// Date is empty if page has no news.
+type feedPage struct {
+	urls   []string
+	dates  []string
+	oldest string
+}
+
+func (n *newsDays) feedPage(ctx context.Context, pages map[int]*feedPage, i int) (*feedPage, error) {
+	if page, ok := pages[i]; ok {
+		return page, nil
+	}
+
+	urls, err := n.parseURLs(ctx, strconv.Itoa(i))
+	if err != nil {
+		return nil, err
+	}
+
+	page := &feedPage{urls: make([]string, 0, len(urls)), dates: make([]string, 0, len(urls))}
+	for _, url := range urls {
+		match := urlDate.FindStringSubmatch(url)
+		if match == nil {
+			continue
+		}
+
+		page.urls = append(page.urls, url)
+		page.dates = append(page.dates, match[1])
+		if page.oldest == "" || match[1] < page.oldest {
+			page.oldest = match[1]
+		}
+	}
+
+	pages[i] = page
+	return page, nil
+}
+
+// parseDay returns URLs of news of day in format "2006-01-02".
+// Feed is sorted from newest news to oldest ones, so first page
+// with news not newer than day is found by exponential and binary search.
+func (n *newsDays) parseDay(ctx context.Context, day string) ([]string, error) {
+	pages := make(map[int]*feedPage)
+
+	// reached reports whether page i contains day or older news or is after end of feed
+	reached := func(i int) (bool, error) {
+		page, err := n.feedPage(ctx, pages, i)
+		if err != nil {
+			return false, err
+		}
+		return page.oldest <= day, nil
+	}
+
+	// page lo is not reached, page -1 is before start of feed
+	lo, hi := -1, n.hint
+	ok, err := reached(hi)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && hi > 0 {
+		// page of previous day is usually first page of next day
+		if ok, err := reached(hi - 1); err != nil {
+			return nil, err
+		} else if !ok {
+			lo = hi - 1
+		}
+	}
+
+	if !ok {
+		// first reached page is searched in (lo, hi]
+		for step := 1; ; step *= 2 {
+			lo, hi = hi, hi+step
+			if hi > DAY_MAX_SEARCH_PAGE {
+				return nil, fmt.Errorf("day %s is not found in %d pages of feed", day, DAY_MAX_SEARCH_PAGE)
+			}
+
+			ok, err := reached(hi)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				break
+			}
+		}
+	}
+
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		ok, err := reached(mid)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	n.hint = hi
+
+	urls := make([]string, 0)
+	for i := hi; i < hi+DAY_MAX_PAGES; i++ {
+		page, err := n.feedPage(ctx, pages, i)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, url := range page.urls {
+			if page.dates[j] == day {
+				urls = append(urls, url)
+			}
+		}
+
+		// news of day continue on next page only if oldest news of page is of day
+		if page.oldest != day {
+			break
+		}
+	}
+
+	return urls, nil
+}
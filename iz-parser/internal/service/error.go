@@ -1,10 +1,13 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 )
 
+var ErrForeignHost = errors.New("url is not of site")
+
 type statusError struct {
 	Code int
 	Text string
@@ -5,31 +5,59 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
-	"github.com/DataHenHQ/useragent"
 	"github.com/PuerkitoBio/goquery"
 	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/aggregator/pkg/urlcanon"
+	"github.com/qsoulior/news/parser/pkg/dateparse"
 	"github.com/qsoulior/news/parser/pkg/httpclient"
 	"github.com/rs/zerolog"
 )
 
+// TIMEZONE is zone of dates on pages of site.
+const TIMEZONE = "Europe/Moscow"
+
 type news struct {
 	appID  string
 	client *httpclient.Client
+	dates  *dateparse.Parser
 	logger *zerolog.Logger
 }
 
-func (n *news) parseOne(ctx context.Context, url string) (*entity.News, error) {
-	ua, err := useragent.Desktop()
+// ParseOne parses single news by URL. Client resolves path against
+// URL of site, so absolute URL of other host is rejected.
+func (n *news) ParseOne(ctx context.Context, link string) (*entity.News, error) {
+	u, err := url.Parse(link)
 	if err != nil {
-		return nil, fmt.Errorf("useragent.Desktop: %w", err)
+		return nil, fmt.Errorf("url.Parse: %w", err)
 	}
 
-	resp, err := n.client.Get(ctx, url, map[string]string{
-		"User-Agent": ua,
-	})
+	if u.IsAbs() {
+		site, err := url.Parse(n.client.BaseURL())
+		if err != nil {
+			return nil, fmt.Errorf("url.Parse: %w", err)
+		}
+
+		if host(u) != host(site) {
+			return nil, fmt.Errorf("%w: %s", ErrForeignHost, u.Host)
+		}
+
+		link = u.RequestURI()
+	}
+
+	return n.parseOne(ctx, link)
+}
+
+// host returns host of URL without www subdomain.
+func host(u *url.URL) string {
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+func (n *news) parseOne(ctx context.Context, url string) (*entity.News, error) {
+	resp, err := n.client.Get(ctx, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("n.client.Get: %w", err)
 	}
@@ -38,17 +66,26 @@ func (n *news) parseOne(ctx context.Context, url string) (*entity.News, error) {
 		return nil, newStatusError(resp.StatusCode)
 	}
 
+	fetchedAt := time.Now().UTC()
+
 	defer resp.Body.Close()
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("goquery.NewDocumentFromReader: %w", err)
 	}
 
+	href, _ := doc.Find("link[rel=canonical]").Attr("href")
+	link, err := urlcanon.Resolve(resp.Request.URL.String(), href)
+	if err != nil {
+		return nil, fmt.Errorf("urlcanon.Resolve: %w", err)
+	}
+
 	news := &entity.News{
 		NewsHead: entity.NewsHead{
 			Source: n.appID,
 		},
-		Link: resp.Request.URL.String(),
+		Link:      link,
+		FetchedAt: fetchedAt,
 	}
 
 	article := doc.Find("[role=\"article\"]")
@@ -63,10 +100,14 @@ func (n *news) parseOne(ctx context.Context, url string) (*entity.News, error) {
 	if !ok {
 		return nil, errors.New("empty datetime")
 	}
-	datetimeLayout := "2006-01-02T15:04:05Z"
-	news.PublishedAt, err = time.Parse(datetimeLayout, datetimeStr)
+	news.PublishedAt, err = n.dates.Parse(datetimeStr)
 	if err != nil {
-		return nil, fmt.Errorf("time.Parse: %w", err)
+		return nil, fmt.Errorf("n.dates.Parse: %w", err)
+	}
+
+	news.ModifiedAt = news.PublishedAt
+	if modifiedAt, ok := n.dates.Meta(doc.Selection, "article:modified_time"); ok && modifiedAt.After(news.PublishedAt) {
+		news.ModifiedAt = modifiedAt
 	}
 
 	news.Tags = article.Find(".article_page__left__top__left__hash_tags a").
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/qsoulior/news/parser/pkg/httpclient"
+)
+
+func TestParseOneForeignHost(t *testing.T) {
+	n := &news{client: httpclient.New(httpclient.URL("https://iz.ru"))}
+
+	for _, link := range []string{"https://lenta.ru/news/2024/01/01/a/", "http://iz.ru.example.com/1/2024-01-01/a"} {
+		_, err := n.ParseOne(context.Background(), link)
+		if !errors.Is(err, ErrForeignHost) {
+			t.Errorf("ParseOne(%q) error = %v, want ErrForeignHost", link, err)
+		}
+	}
+}
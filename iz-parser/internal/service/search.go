@@ -5,10 +5,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 
-	"github.com/DataHenHQ/useragent"
 	"github.com/PuerkitoBio/goquery"
 	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/parser/pkg/dateparse"
 	"github.com/qsoulior/news/parser/pkg/httpclient"
 	"github.com/rs/zerolog"
 )
@@ -17,11 +18,12 @@ type newsSearch struct {
 	*news
 }
 
-func NewNewsSearch(appID string, client *httpclient.Client, logger *zerolog.Logger) *newsSearch {
+func NewNewsSearch(appID string, client *httpclient.Client, dates *dateparse.Parser, logger *zerolog.Logger) *newsSearch {
 	log := logger.With().Str("service", "search").Logger()
 	news := &news{
 		appID:  appID,
 		client: client,
+		dates:  dates,
 		logger: &log,
 	}
 
@@ -32,8 +34,18 @@ func NewNewsSearch(appID string, client *httpclient.Client, logger *zerolog.Logg
 	return search
 }
 
+// Parse parses page of search results. Page is offset of results.
 func (n *newsSearch) Parse(ctx context.Context, query string, page string) ([]entity.News, string, error) {
-	urls, err := n.parseURLs(ctx, query, page)
+	from := 0
+	if page != "" {
+		var err error
+		from, err = strconv.Atoi(page)
+		if err != nil {
+			return nil, "", fmt.Errorf("strconv.Atoi: %w", err)
+		}
+	}
+
+	urls, err := n.parseURLs(ctx, query, from)
 	if err != nil {
 		return nil, "", err
 	}
@@ -43,28 +55,30 @@ func (n *newsSearch) Parse(ctx context.Context, query string, page string) ([]en
 		return nil, "", fmt.Errorf("n.parseMany: %w", err)
 	}
 
-	return news, "", nil
+	nextPage := ""
+	if len(urls) > 0 {
+		nextPage = strconv.Itoa(from + len(urls))
+	}
+
+	return news, nextPage, nil
 }
 
-func (n *newsSearch) parseURLs(ctx context.Context, query string, from string) ([]string, error) {
+// SEARCH_SORT_DATE sorts results from newest to oldest, so search stops
+// at first page older than date range of request.
+const SEARCH_SORT_DATE = "1"
+
+func (n *newsSearch) parseURLs(ctx context.Context, query string, from int) ([]string, error) {
 	u, _ := url.Parse("/search")
 	values := u.Query()
 	values.Set("text", query)
-	values.Set("sort", "0")
+	values.Set("sort", SEARCH_SORT_DATE)
 	values.Set("type", "1")
-	if from != "" {
-		values.Set("from", from)
+	if from > 0 {
+		values.Set("from", strconv.Itoa(from))
 	}
 	u.RawQuery = values.Encode()
 
-	ua, err := useragent.Desktop()
-	if err != nil {
-		return nil, fmt.Errorf("useragent.Desktop: %w", err)
-	}
-
-	resp, err := n.client.Get(ctx, u.String(), map[string]string{
-		"User-Agent": ua,
-	})
+	resp, err := n.client.Get(ctx, u.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("n.client.Get: %w", err)
 	}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/qsoulior/news/parser/pkg/httpclient"
+	"github.com/rs/zerolog"
+)
+
+func TestSearchSortedByDate(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+	}))
+	defer server.Close()
+
+	logger := zerolog.Nop()
+	search := NewNewsSearch("iz", httpclient.New(httpclient.URL(server.URL)), nil, &logger)
+	if _, _, err := search.Parse(context.Background(), "выборы", "40"); err != nil {
+		t.Fatal(err)
+	}
+
+	// search stops at results out of date range, so they must be ordered by date
+	want := url.Values{"text": {"выборы"}, "sort": {SEARCH_SORT_DATE}, "type": {"1"}, "from": {"40"}}
+	if query.Encode() != want.Encode() {
+		t.Errorf("query = %v, want %v", query, want)
+	}
+}
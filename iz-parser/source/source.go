@@ -0,0 +1,26 @@
+// Package source registers iz parser in parser binary hosting several sources.
+package source
+
+import (
+	"context"
+	"fmt"
+
+	iz "github.com/qsoulior/news/iz-parser/internal/app"
+	"github.com/qsoulior/news/parser/app"
+	"github.com/qsoulior/news/parser/pkg/httpclient"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	app.Register(iz.APP_ID, New)
+}
+
+// New returns parsers of iz source.
+func New(ctx context.Context, decode func(cfg any) error, opts []httpclient.Option, logger *zerolog.Logger) (*app.Source, error) {
+	cfg := new(iz.Config)
+	if err := decode(cfg); err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+
+	return iz.New(ctx, cfg, opts, logger)
+}
@@ -1,41 +1,118 @@
 package app
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"github.com/qsoulior/news/lenta-parser/internal/service"
 	"github.com/qsoulior/news/parser/app"
+	"github.com/qsoulior/news/parser/pkg/dateparse"
 	"github.com/qsoulior/news/parser/pkg/httpclient"
+	"github.com/qsoulior/news/parser/pkg/shard"
 	"github.com/rs/zerolog"
 )
 
-func Run(cfg *Config) {
-	appID := "lenta"
+const APP_ID = "lenta"
 
+func Run(cfg *Config) {
 	out := zerolog.NewConsoleWriter(func(w *zerolog.ConsoleWriter) {
 		w.TimeFormat = time.RFC3339
 	})
 
 	logger := zerolog.New(out).With().Timestamp().Logger()
 
-	client := httpclient.New()
-	searchParser := service.NewNewsSearch(appID, cfg.Service.Search.URL, client, &logger)
-	archiveParser := service.NewNewsArchive(appID, cfg.Service.Archive.URL, client, &logger)
-	feedParser := service.NewNewsFeed(appID, cfg.Service.Feed.URL, client, &logger)
-
-	app.Run(
-		&app.Config{
-			ID:            appID,
-			SearchParser:  searchParser,
-			ArchiveParser: archiveParser,
-			FeedParser:    feedParser,
-			Logger:        &logger,
+	source, err := New(context.Background(), cfg, nil, &logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to init parser")
+	}
+
+	app.Run(source.Config(APP_ID, &logger), source.Options)
+}
+
+// New returns parsers of source built from cfg.
+// Options of HTTP client override options of config.
+func New(ctx context.Context, cfg *Config, opts []httpclient.Option, logger *zerolog.Logger) (*app.Source, error) {
+	appID := APP_ID
+
+	var backfillShards []shard.Shard
+	if backfill := cfg.Service.Backfill; backfill.From != "" && backfill.To != "" {
+		from, err := time.Parse(time.DateOnly, backfill.From)
+		if err != nil {
+			return nil, fmt.Errorf("invalid backfill range: %w", err)
+		}
+
+		to, err := time.Parse(time.DateOnly, backfill.To)
+		if err != nil {
+			return nil, fmt.Errorf("invalid backfill range: %w", err)
+		}
+
+		backfillShards = shard.Days(from, to, backfill.Days, service.DAY_LAYOUT)
+	}
+
+	dates, err := cfg.Service.dates()
+	if err != nil {
+		return nil, err
+	}
+
+	httpOpts, err := cfg.HTTP.ClientOptions()
+	if err != nil {
+		return nil, fmt.Errorf("invalid http options: %w", err)
+	}
+
+	client := httpclient.New(append(httpOpts, opts...)...)
+
+	source := &app.Source{
+		Search: func() app.Parser {
+			return service.NewNewsSearch(appID, cfg.Service.Search.URL, client, dates, logger)
+		},
+		Archive: func() app.Parser {
+			return service.NewNewsArchive(appID, cfg.Service.Archive.URL, client, dates, logger)
 		},
-		&app.Options{
+		Feed: func() app.Parser {
+			return service.NewNewsFeed(appID, cfg.Service.Feed.URL, client, dates, logger)
+		},
+		Backfill: func() app.Parser {
+			return service.NewNewsDays(appID, cfg.Service.Backfill.URL, client, dates, logger)
+		},
+
+		BackfillShards: backfillShards,
+
+		Options: &app.Options{
 			RabbitURL:    cfg.RabbitMQ.URL,
 			RedisURL:     cfg.Redis.URL,
 			FeedDelay:    &cfg.Service.Feed.Delay,
 			ArchiveDelay: &cfg.Service.Archive.Delay,
+
+			SearchDepth:    cfg.Service.Search.Depth,
+			MaxSearchDepth: cfg.Service.Search.MaxDepth,
+
+			FeedSchedule:    cfg.Service.Feed.Schedule,
+			ArchiveSchedule: cfg.Service.Archive.Schedule,
+
+			AdminHost:  cfg.Admin.Host,
+			AdminPort:  cfg.Admin.Port,
+			AdminToken: cfg.Admin.Token,
+
+			ContentType:     cfg.RabbitMQ.ContentType,
+			ContentEncoding: cfg.RabbitMQ.ContentEncoding,
 		},
-	)
+	}
+
+	return source, nil
+}
+
+// dates returns parser of dates in timezone of config or site.
+func (c ConfigService) dates() (*dateparse.Parser, error) {
+	timezone := c.Timezone
+	if timezone == "" {
+		timezone = service.TIMEZONE
+	}
+
+	loc, err := dateparse.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone: %w", err)
+	}
+
+	return dateparse.New(loc), nil
 }
@@ -5,38 +5,66 @@ import (
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/qsoulior/news/parser/app"
 )
 
 type (
 	Config struct {
-		RabbitMQ ConfigRabbitMQ `yaml:"rabbitmq"`
-		Redis    ConfigRedis    `yaml:"redis"`
-		Service  ConfigService  `yaml:"service"`
+		RabbitMQ ConfigRabbitMQ  `yaml:"rabbitmq"`
+		Redis    ConfigRedis     `yaml:"redis"`
+		Service  ConfigService   `yaml:"service"`
+		HTTP     app.HTTPOptions `yaml:"http"`
+		Admin    ConfigAdmin     `yaml:"admin"`
 	}
 
 	ConfigService struct {
 		Search struct {
-			URL string `yaml:"url"`
+			URL      string `yaml:"url"`
+			Depth    int    `yaml:"depth"`
+			MaxDepth int    `yaml:"max_depth"`
 		} `yaml:"search"`
 
 		Archive struct {
-			URL   string        `yaml:"url"`
-			Delay time.Duration `yaml:"delay"`
+			URL      string              `yaml:"url"`
+			Delay    time.Duration       `yaml:"delay"`
+			Schedule app.ScheduleOptions `yaml:"schedule"`
 		} `yaml:"archive"`
 
+		// Backfill crawls day archive of site from newest date to oldest date
+		// split into shards of given number of days.
+		Backfill struct {
+			URL  string `yaml:"url"`
+			From string `yaml:"from"`
+			To   string `yaml:"to"`
+			Days int    `yaml:"days"`
+		} `yaml:"backfill"`
+
 		Feed struct {
-			URL   string        `yaml:"url"`
-			Delay time.Duration `yaml:"delay"`
+			URL      string              `yaml:"url"`
+			Delay    time.Duration       `yaml:"delay"`
+			Schedule app.ScheduleOptions `yaml:"schedule"`
 		} `yaml:"feed"`
+
+		// Timezone of dates without zone, zone of site is used if it is empty.
+		Timezone string `yaml:"timezone"`
 	}
 
 	ConfigRabbitMQ struct {
-		URL string `yaml:"url"`
+		URL             string `yaml:"url"`
+		ContentType     string `yaml:"content_type"`
+		ContentEncoding string `yaml:"content_encoding"`
 	}
 
 	ConfigRedis struct {
 		URL string `yaml:"url"`
 	}
+
+	// ConfigAdmin is address of admin API, token is required on non-loopback host.
+	ConfigAdmin struct {
+		Host  string `yaml:"host"`
+		Port  string `yaml:"port"`
+		Token string `yaml:"token" env:"ADMIN_TOKEN"`
+	}
 )
 
 func NewConfig(path string) (*Config, error) {
@@ -8,8 +8,8 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/DataHenHQ/useragent"
 	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/parser/pkg/dateparse"
 	"github.com/qsoulior/news/parser/pkg/httpclient"
 	"github.com/qsoulior/news/parser/pkg/httpclient/httpresponse"
 	"github.com/rs/zerolog"
@@ -20,12 +20,13 @@ type newsArchive struct {
 	url string
 }
 
-func NewNewsArchive(appID string, url string, client *httpclient.Client, logger *zerolog.Logger) *newsArchive {
+func NewNewsArchive(appID string, url string, client *httpclient.Client, dates *dateparse.Parser, logger *zerolog.Logger) *newsArchive {
 	log := logger.With().Str("service", "archive").Logger()
 
 	news := &news{
 		appID:  appID,
 		client: client,
+		dates:  dates,
 		logger: &log,
 	}
 
@@ -88,15 +89,9 @@ type TopicResponse struct {
 }
 
 func (n *newsArchive) parseURLs(ctx context.Context, page string) ([]*newsURL, error) {
-	ua, err := useragent.Desktop()
-	if err != nil {
-		return nil, fmt.Errorf("useragent.Desktop: %w", err)
-	}
 	// rubrics
 	u, _ := url.Parse(n.url + "/v3/rubrics")
-	rubricResp, err := n.client.Get(ctx, u.String(), map[string]string{
-		"User-Agent": ua,
-	})
+	rubricResp, err := n.client.Get(ctx, u.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("n.client.Get: %w", err)
 	}
@@ -121,14 +116,7 @@ func (n *newsArchive) parseURLs(ctx context.Context, page string) ([]*newsURL, e
 	values.Set("offset", page+"00")
 	u.RawQuery = values.Encode()
 
-	ua, err = useragent.Desktop()
-	if err != nil {
-		return nil, fmt.Errorf("useragent.Desktop: %w", err)
-	}
-
-	topicResp, err := n.client.Get(ctx, u.String(), map[string]string{
-		"User-Agent": ua,
-	})
+	topicResp, err := n.client.Get(ctx, u.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("n.client.Get: %w", err)
 	}
@@ -145,8 +133,8 @@ func (n *newsArchive) parseURLs(ctx context.Context, page string) ([]*newsURL, e
 	for _, topic := range topicData.Topics {
 		if topic.Headline.Type == "news" {
 			urls = append(urls, &newsURL{
-				URL:         topic.Headline.Links.Public,
-				PublishedAt: time.Unix(int64(topic.Headline.Info.Modified), 0),
+				URL:        topic.Headline.Links.Public,
+				ModifiedAt: time.Unix(int64(topic.Headline.Info.Modified), 0),
 			})
 		}
 	}
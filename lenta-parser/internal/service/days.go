@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/parser/pkg/dateparse"
+	"github.com/qsoulior/news/parser/pkg/httpclient"
+	"github.com/rs/zerolog"
+)
+
+// DAY_LAYOUT is format of pages of day archive.
+const DAY_LAYOUT = "20060102"
+
+// DAY_MAX_PAGES limits number of listing pages of single day.
+const DAY_MAX_PAGES = 30
+
+// newsDays parses archive of site by days from newest day to oldest one.
+// News of day are listed once and removed only after they are parsed.
+type newsDays struct {
+	*news
+	url string
+
+	day  string
+	urls []*newsURL
+}
+
+func NewNewsDays(appID string, url string, client *httpclient.Client, dates *dateparse.Parser, logger *zerolog.Logger) *newsDays {
+	log := logger.With().Str("service", "days").Logger()
+
+	news := &news{
+		appID:  appID,
+		client: client,
+		dates:  dates,
+		logger: &log,
+	}
+
+	days := &newsDays{
+		news: news,
+		url:  url,
+	}
+
+	return days
+}
+
+func (n *newsDays) Parse(ctx context.Context, query string, page string) ([]entity.News, string, error) {
+	day, err := time.Parse(DAY_LAYOUT, page)
+	if err != nil {
+		return nil, "", fmt.Errorf("time.Parse: %w", err)
+	}
+
+	if n.day != page {
+		urls, err := n.parseURLs(ctx, day)
+		if err != nil {
+			return nil, "", err
+		}
+
+		n.day, n.urls = page, urls
+	}
+
+	const limit = 20
+	urls := n.urls[:min(len(n.urls), limit)]
+
+	news, err := n.parseMany(ctx, urls)
+	if err != nil {
+		return nil, "", fmt.Errorf("n.parseMany: %w", err)
+	}
+
+	n.urls = n.urls[len(urls):]
+	if len(n.urls) > 0 {
+		return news, page, nil
+	}
+
+	n.day = ""
+	return news, day.AddDate(0, 0, -1).Format(DAY_LAYOUT), nil
+}
+
+// parseURLs returns URLs of news of day listed by pages "/news/2006/01/02/page/N/".
+func (n *newsDays) parseURLs(ctx context.Context, day time.Time) ([]*newsURL, error) {
+	base, err := url.Parse(n.url)
+	if err != nil {
+		return nil, fmt.Errorf("url.Parse: %w", err)
+	}
+
+	prefix := "/news/" + day.Format("2006/01/02") + "/"
+
+	var (
+		urls = make([]*newsURL, 0)
+		seen = make(map[string]struct{})
+	)
+
+	for i := 1; i <= DAY_MAX_PAGES; i++ {
+		path := prefix
+		if i > 1 {
+			path += fmt.Sprintf("page/%d/", i)
+		}
+
+		resp, err := n.client.Get(ctx, base.JoinPath(path).String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("n.client.Get: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusNotFound && i > 1 {
+			resp.Body.Close()
+			break
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, newStatusError(resp.StatusCode)
+		}
+
+		doc, err := goquery.NewDocumentFromReader(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("goquery.NewDocumentFromReader: %w", err)
+		}
+
+		count := 0
+		doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
+			href, _ := s.Attr("href")
+			u, err := base.Parse(href)
+			if err != nil || u.Host != base.Host || !strings.HasPrefix(u.Path, prefix) || u.Path == prefix {
+				return
+			}
+
+			// links to listing pages of day
+			if strings.HasPrefix(u.Path, prefix+"page/") {
+				return
+			}
+
+			u.RawQuery, u.Fragment = "", ""
+			link := u.String()
+			if _, ok := seen[link]; ok {
+				return
+			}
+
+			seen[link] = struct{}{}
+			urls = append(urls, &newsURL{URL: link})
+			count++
+		})
+
+		if count == 0 {
+			break
+		}
+	}
+
+	return urls, nil
+}
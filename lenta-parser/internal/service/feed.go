@@ -6,8 +6,8 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/DataHenHQ/useragent"
 	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/parser/pkg/dateparse"
 	"github.com/qsoulior/news/parser/pkg/httpclient"
 	"github.com/qsoulior/news/parser/pkg/rssclient"
 	"github.com/rs/zerolog"
@@ -27,12 +27,13 @@ type newsFeed struct {
 	urlCache  map[string]time.Time
 }
 
-func NewNewsFeed(appID string, url string, client *httpclient.Client, logger *zerolog.Logger) *newsFeed {
+func NewNewsFeed(appID string, url string, client *httpclient.Client, dates *dateparse.Parser, logger *zerolog.Logger) *newsFeed {
 	log := logger.With().Str("service", "feed").Logger()
 
 	news := &news{
 		appID:  appID,
 		client: client,
+		dates:  dates,
 		logger: &log,
 	}
 
@@ -76,14 +77,7 @@ func (n *newsFeed) Parse(ctx context.Context, query string, page string) ([]enti
 func (n *newsFeed) parseURLs(ctx context.Context) ([]*newsURL, error) {
 	u := n.url + "/rss/news"
 
-	ua, err := useragent.Desktop()
-	if err != nil {
-		return nil, fmt.Errorf("useragent.Desktop: %w", err)
-	}
-
-	items, err := n.rssclient.Get(ctx, "item", u, map[string]string{
-		"User-Agent": ua,
-	})
+	items, err := n.rssclient.Get(ctx, "item", u, nil)
 	if err != nil {
 		return nil, fmt.Errorf("n.rssclient.Get: %w", err)
 	}
@@ -96,9 +90,9 @@ func (n *newsFeed) parseURLs(ctx context.Context) ([]*newsURL, error) {
 		url := item.Link
 		urlSet[url] = struct{}{}
 
-		pubDate, err := time.Parse(time.RFC1123Z, item.PubDate)
+		pubDate, err := n.dates.Parse(item.PubDate)
 		if err != nil {
-			return nil, fmt.Errorf("time.Parse: %w", err)
+			return nil, fmt.Errorf("n.dates.Parse: %w", err)
 		}
 
 		if pd, ok := n.urlCache[url]; !ok || pubDate.After(pd) {
@@ -8,9 +8,10 @@ import (
 	"strings"
 	"time"
 
-	"github.com/DataHenHQ/useragent"
 	"github.com/PuerkitoBio/goquery"
 	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/aggregator/pkg/urlcanon"
+	"github.com/qsoulior/news/parser/pkg/dateparse"
 	"github.com/qsoulior/news/parser/pkg/httpclient"
 	"github.com/rs/zerolog"
 )
@@ -18,23 +19,26 @@ import (
 type newsURL struct {
 	URL         string
 	PublishedAt time.Time
+	ModifiedAt  time.Time
 }
 
+// TIMEZONE is zone of dates on pages of site.
+const TIMEZONE = "Europe/Moscow"
+
 type news struct {
 	appID  string
 	client *httpclient.Client
+	dates  *dateparse.Parser
 	logger *zerolog.Logger
 }
 
-func (n *news) parseOne(ctx context.Context, url *newsURL) (*entity.News, error) {
-	ua, err := useragent.Desktop()
-	if err != nil {
-		return nil, fmt.Errorf("useragent.Desktop: %w", err)
-	}
+// ParseOne parses single news by URL.
+func (n *news) ParseOne(ctx context.Context, url string) (*entity.News, error) {
+	return n.parseOne(ctx, &newsURL{URL: url})
+}
 
-	resp, err := n.client.Get(ctx, url.URL, map[string]string{
-		"User-Agent": ua,
-	})
+func (n *news) parseOne(ctx context.Context, url *newsURL) (*entity.News, error) {
+	resp, err := n.client.Get(ctx, url.URL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("n.client.Get: %w", err)
 	}
@@ -43,19 +47,46 @@ func (n *news) parseOne(ctx context.Context, url *newsURL) (*entity.News, error)
 		return nil, newStatusError(resp.StatusCode)
 	}
 
+	fetchedAt := time.Now().UTC()
+
 	defer resp.Body.Close()
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("goquery.NewDocumentFromReader: %w", err)
 	}
 
+	href, _ := doc.Find("link[rel=canonical]").Attr("href")
+	link, err := urlcanon.Resolve(resp.Request.URL.String(), href)
+	if err != nil {
+		return nil, fmt.Errorf("urlcanon.Resolve: %w", err)
+	}
+
 	news := &entity.News{
 		NewsHead: entity.NewsHead{
 			Source:      n.appID,
 			PublishedAt: url.PublishedAt,
+			ModifiedAt:  url.ModifiedAt,
 		},
-		Link: resp.Request.URL.String(),
-		Tags: make([]string, 0),
+		Link:      link,
+		Tags:      make([]string, 0),
+		FetchedAt: fetchedAt,
+	}
+
+	if publishedAt, ok := n.dates.Meta(doc.Selection, "article:published_time"); ok {
+		news.PublishedAt = publishedAt
+	}
+
+	if modifiedAt, ok := n.dates.Meta(doc.Selection, "article:modified_time"); ok && modifiedAt.After(news.ModifiedAt) {
+		news.ModifiedAt = modifiedAt
+	}
+
+	// archive provides modification time only
+	if news.PublishedAt.IsZero() {
+		news.PublishedAt = news.ModifiedAt
+	}
+
+	if news.ModifiedAt.Before(news.PublishedAt) {
+		news.ModifiedAt = news.PublishedAt
 	}
 
 	topic := doc.Find(".topic-page__container")
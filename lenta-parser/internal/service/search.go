@@ -5,10 +5,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
-	"github.com/DataHenHQ/useragent"
 	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/parser/pkg/dateparse"
 	"github.com/qsoulior/news/parser/pkg/httpclient"
 	"github.com/qsoulior/news/parser/pkg/httpclient/httpresponse"
 	"github.com/rs/zerolog"
@@ -19,12 +20,13 @@ type newsSearch struct {
 	url string
 }
 
-func NewNewsSearch(appID string, url string, client *httpclient.Client, logger *zerolog.Logger) *newsSearch {
+func NewNewsSearch(appID string, url string, client *httpclient.Client, dates *dateparse.Parser, logger *zerolog.Logger) *newsSearch {
 	log := logger.With().Str("service", "search").Logger()
 
 	news := &news{
 		client: client,
 		appID:  appID,
+		dates:  dates,
 		logger: &log,
 	}
 
@@ -45,8 +47,20 @@ type MatchResponse struct {
 	Matches []MatchDTO `json:"matches"`
 }
 
+const SEARCH_SIZE = 100
+
+// Parse parses page of search results. Page is offset of results.
 func (n *newsSearch) Parse(ctx context.Context, query string, page string) ([]entity.News, string, error) {
-	urls, err := n.parseURLs(ctx, query, page)
+	from := 0
+	if page != "" {
+		var err error
+		from, err = strconv.Atoi(page)
+		if err != nil {
+			return nil, "", fmt.Errorf("strconv.Atoi: %w", err)
+		}
+	}
+
+	urls, err := n.parseURLs(ctx, query, from)
 	if err != nil {
 		return nil, "", err
 	}
@@ -56,30 +70,29 @@ func (n *newsSearch) Parse(ctx context.Context, query string, page string) ([]en
 		return nil, "", fmt.Errorf("n.parseMany: %w", err)
 	}
 
-	return news, "", nil
+	nextPage := ""
+	if len(urls) == SEARCH_SIZE {
+		nextPage = strconv.Itoa(from + SEARCH_SIZE)
+	}
+
+	return news, nextPage, nil
 }
 
-func (n *newsSearch) parseURLs(ctx context.Context, query string, from string) ([]*newsURL, error) {
+func (n *newsSearch) parseURLs(ctx context.Context, query string, from int) ([]*newsURL, error) {
 	u, _ := url.Parse(n.url + "/search/v2/process")
 	values := u.Query()
 	values.Set("query", query)
-	if from != "" {
-		values.Set("from", from)
+	if from > 0 {
+		values.Set("from", strconv.Itoa(from))
 	}
-	values.Set("size", "100")
+	values.Set("size", strconv.Itoa(SEARCH_SIZE))
 	values.Set("sort", "2")
 	values.Set("domain", "1")
 	values.Set("type", "1")
 	u.RawQuery = values.Encode()
 
-	ua, err := useragent.Desktop()
-	if err != nil {
-		return nil, fmt.Errorf("useragent.Desktop: %w", err)
-	}
 	n.logger.Debug().Msg(u.String())
-	resp, err := n.client.Get(ctx, u.String(), map[string]string{
-		"User-Agent": ua,
-	})
+	resp, err := n.client.Get(ctx, u.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("n.client.Get: %w", err)
 	}
@@ -0,0 +1,26 @@
+// Package source registers lenta parser in parser binary hosting several sources.
+package source
+
+import (
+	"context"
+	"fmt"
+
+	lenta "github.com/qsoulior/news/lenta-parser/internal/app"
+	"github.com/qsoulior/news/parser/app"
+	"github.com/qsoulior/news/parser/pkg/httpclient"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	app.Register(lenta.APP_ID, New)
+}
+
+// New returns parsers of lenta source.
+func New(ctx context.Context, decode func(cfg any) error, opts []httpclient.Option, logger *zerolog.Logger) (*app.Source, error) {
+	cfg := new(lenta.Config)
+	if err := decode(cfg); err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+
+	return lenta.New(ctx, cfg, opts, logger)
+}
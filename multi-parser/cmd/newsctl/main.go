@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/qsoulior/news/multi-parser/internal/app"
+	"github.com/qsoulior/news/parser/cli"
+)
+
+func main() {
+	var path string
+	flag.StringVar(&path, "c", "", "config file path, sources are configured by environment if it is empty")
+	flag.Parse()
+
+	cfg := new(app.Config)
+	if path != "" {
+		var err error
+		cfg, err = app.NewConfig(path)
+		if err != nil {
+			log.Fatalf("failed to read config: %s", err)
+		}
+	}
+
+	if err := cli.Run(cfg.Decoder, flag.Args()); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/qsoulior/news/parser/app"
+	"github.com/rs/zerolog"
+
+	// sources available to parser
+	_ "github.com/qsoulior/news/iz-parser/source"
+	_ "github.com/qsoulior/news/lenta-parser/source"
+	_ "github.com/qsoulior/news/newsdata-parser/source"
+	_ "github.com/qsoulior/news/ria-parser/source"
+)
+
+func Run(cfg *Config) {
+	out := zerolog.NewConsoleWriter(func(w *zerolog.ConsoleWriter) {
+		w.TimeFormat = time.RFC3339
+	})
+	logger := zerolog.New(out).With().Timestamp().Logger()
+
+	// notify context
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	names := cfg.Names()
+	if len(names) == 0 {
+		logger.Fatal().Strs("registered", app.Sources()).Msg("no sources configured")
+	}
+
+	if err := cfg.Validate(); err != nil {
+		logger.Fatal().Err(err).Msg("invalid config")
+	}
+
+	type source struct {
+		log  zerolog.Logger
+		cfg  *app.Config
+		opts *app.Options
+	}
+
+	// sources are built before start, so invalid source stops parser
+	sources := make([]source, 0, len(names))
+	for _, name := range names {
+		log := logger.With().Str("source", name).Logger()
+		appCfg, appOpts, err := app.New(ctx, name, cfg.Decoder(name), &log)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to init source")
+		}
+		sources = append(sources, source{log, appCfg, appOpts})
+	}
+
+	var wg sync.WaitGroup
+	for _, item := range sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			app.RunContext(ctx, item.cfg, item.opts)
+			item.log.Info().Msg("stopped")
+		}()
+	}
+
+	logger.Info().Strs("sources", names).Msg("started")
+	wg.Wait()
+}
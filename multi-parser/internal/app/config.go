@@ -0,0 +1,112 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ilyakaznacheev/cleanenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Config of parser hosting several sources. Sections of sources have
+// format of their own configs, top-level sections are shared by sources
+// and overridden by their sections.
+type Config struct {
+	Sources map[string]yaml.Node `yaml:"sources"`
+
+	root yaml.Node
+}
+
+// ConfigSource is part of source config checked for isolation of sources.
+type ConfigSource struct {
+	Redis struct {
+		URL string `yaml:"url"`
+	} `yaml:"redis"`
+
+	Admin struct {
+		Port string `yaml:"port"`
+	} `yaml:"admin"`
+}
+
+func NewConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	cfg := new(Config)
+	if err := yaml.Unmarshal(data, &cfg.root); err != nil {
+		return nil, fmt.Errorf("yaml.Unmarshal: %w", err)
+	}
+
+	if err := cfg.root.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("cfg.root.Decode: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// Names returns sorted names of configured sources.
+func (c *Config) Names() []string {
+	names := make([]string, 0, len(c.Sources))
+	for name := range c.Sources {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Decoder returns function filling config of source from shared sections
+// and section of source. Environment variables and env-default values
+// of config are applied after sections as cleanenv does for single source.
+func (c *Config) Decoder(name string) func(cfg any) error {
+	return func(cfg any) error {
+		// source without config section is configured by shared sections and environment
+		for _, node := range []yaml.Node{c.root, c.Sources[name]} {
+			if node.IsZero() {
+				continue
+			}
+
+			if err := node.Decode(cfg); err != nil {
+				return fmt.Errorf("node.Decode: %w", err)
+			}
+		}
+
+		if err := cleanenv.ReadEnv(cfg); err != nil {
+			return fmt.Errorf("cleanenv.ReadEnv: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// Validate checks that sources do not share state in Redis and admin port.
+func (c *Config) Validate() error {
+	redisURLs := make(map[string]string)
+	adminPorts := make(map[string]string)
+
+	for _, name := range c.Names() {
+		var source ConfigSource
+		if err := c.Decoder(name)(&source); err != nil {
+			return fmt.Errorf("source %s: %w", name, err)
+		}
+
+		if other, ok := redisURLs[source.Redis.URL]; ok {
+			return fmt.Errorf("sources %s and %s share redis url", other, name)
+		}
+		redisURLs[source.Redis.URL] = name
+
+		if source.Admin.Port == "" {
+			continue
+		}
+
+		if other, ok := adminPorts[source.Admin.Port]; ok {
+			return fmt.Errorf("sources %s and %s share admin port", other, name)
+		}
+		adminPorts[source.Admin.Port] = name
+	}
+
+	return nil
+}
@@ -1,31 +1,86 @@
 package app
 
 import (
+	"context"
+	"fmt"
+	"log"
+
 	"github.com/qsoulior/news/newsdata-parser/internal/service"
 	"github.com/qsoulior/news/parser/app"
+	"github.com/qsoulior/news/parser/pkg/dateparse"
 	"github.com/qsoulior/news/parser/pkg/httpclient"
+	"github.com/rs/zerolog"
 )
 
+const APP_ID = "newsdata"
+
 func Run(cfg *Config) {
-	appID := "newsdata"
+	source, err := New(context.Background(), cfg, nil, nil)
+	if err != nil {
+		log.Fatalf("failed to init parser: %s", err)
+	}
+
+	app.Run(source.Config(APP_ID, nil), source.Options)
+}
+
+// New returns parsers of source built from cfg.
+// Options of HTTP client override options of config.
+func New(ctx context.Context, cfg *Config, opts []httpclient.Option, logger *zerolog.Logger) (*app.Source, error) {
+	appID := APP_ID
 
-	client := httpclient.New(
-		httpclient.URL(cfg.Service.URL),
-	)
+	dates, err := cfg.Service.dates()
+	if err != nil {
+		return nil, err
+	}
 
-	searchParser := service.NewNews(appID, cfg.Service.Search.AccessKey, client)
-	archiveParser := service.NewNews(appID, cfg.Service.Archive.AccessKey, client)
+	httpOpts, err := cfg.HTTP.ClientOptions()
+	if err != nil {
+		return nil, fmt.Errorf("invalid http options: %w", err)
+	}
 
-	app.Run(
-		&app.Config{
-			ID:            appID,
-			SearchParser:  searchParser,
-			ArchiveParser: archiveParser,
+	client := httpclient.New(append(append(httpOpts, opts...), httpclient.URL(cfg.Service.URL))...)
+
+	source := &app.Source{
+		Search: func() app.Parser {
+			return service.NewNews(appID, cfg.Service.Search.AccessKey, client, dates)
+		},
+		Archive: func() app.Parser {
+			return service.NewNews(appID, cfg.Service.Archive.AccessKey, client, dates)
 		},
-		&app.Options{
+
+		Options: &app.Options{
 			RabbitURL:    cfg.RabbitMQ.URL,
 			RedisURL:     cfg.Redis.URL,
 			ArchiveDelay: &cfg.Service.Archive.Delay,
+
+			SearchDepth:    cfg.Service.Search.Depth,
+			MaxSearchDepth: cfg.Service.Search.MaxDepth,
+
+			ArchiveSchedule: cfg.Service.Archive.Schedule,
+
+			AdminHost:  cfg.Admin.Host,
+			AdminPort:  cfg.Admin.Port,
+			AdminToken: cfg.Admin.Token,
+
+			ContentType:     cfg.RabbitMQ.ContentType,
+			ContentEncoding: cfg.RabbitMQ.ContentEncoding,
 		},
-	)
+	}
+
+	return source, nil
+}
+
+// dates returns parser of dates in timezone of config or API.
+func (c ConfigService) dates() (*dateparse.Parser, error) {
+	timezone := c.Timezone
+	if timezone == "" {
+		timezone = service.TIMEZONE
+	}
+
+	loc, err := dateparse.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone: %w", err)
+	}
+
+	return dateparse.New(loc), nil
 }
@@ -5,33 +5,52 @@ import (
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/qsoulior/news/parser/app"
 )
 
 type (
 	Config struct {
-		RabbitMQ ConfigRabbitMQ `yaml:"rabbitmq"`
-		Redis    ConfigRedis    `yaml:"redis"`
-		Service  ConfigService  `yaml:"service"`
+		RabbitMQ ConfigRabbitMQ  `yaml:"rabbitmq"`
+		Redis    ConfigRedis     `yaml:"redis"`
+		Service  ConfigService   `yaml:"service"`
+		HTTP     app.HTTPOptions `yaml:"http"`
+		Admin    ConfigAdmin     `yaml:"admin"`
 	}
 
 	ConfigService struct {
-		URL    string `yaml:"url"`
+		URL string `yaml:"url"`
+
+		// Timezone of dates without zone, zone of API is used if it is empty.
+		Timezone string `yaml:"timezone"`
+
 		Search struct {
 			AccessKey string `yaml:"access_key"`
+			Depth     int    `yaml:"depth"`
+			MaxDepth  int    `yaml:"max_depth"`
 		} `yaml:"search"`
 		Archive struct {
-			AccessKey string        `yaml:"access_key"`
-			Delay     time.Duration `yaml:"delay"`
+			AccessKey string              `yaml:"access_key"`
+			Delay     time.Duration       `yaml:"delay"`
+			Schedule  app.ScheduleOptions `yaml:"schedule"`
 		} `yaml:"archive"`
 	}
 
 	ConfigRabbitMQ struct {
-		URL string `yaml:"url"`
+		URL             string `yaml:"url"`
+		ContentType     string `yaml:"content_type"`
+		ContentEncoding string `yaml:"content_encoding"`
 	}
 
 	ConfigRedis struct {
 		URL string `yaml:"url"`
 	}
+
+	// ConfigAdmin is address of admin API, token is required on non-loopback host.
+	ConfigAdmin struct {
+		Host  string `yaml:"host"`
+		Port  string `yaml:"port"`
+		Token string `yaml:"token" env:"ADMIN_TOKEN"`
+	}
 )
 
 func NewConfig(path string) (*Config, error) {
@@ -5,56 +5,51 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"strings"
 	"time"
 
 	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/parser/pkg/dateparse"
 	"github.com/qsoulior/news/parser/pkg/httpclient"
 	"github.com/qsoulior/news/parser/pkg/httpclient/httpresponse"
 )
 
 const (
 	COUNTRY = "ru"
+	// TIMEZONE is zone of publication dates returned by API.
+	TIMEZONE = "UTC"
 )
 
 type NewsDTO struct {
-	ArticleID   string      `json:"article_id"`
-	Title       string      `json:"title"`
-	Description string      `json:"description"`
-	Link        string      `json:"link"`
-	SourceID    string      `json:"source_id"`
-	PubDate     NewsPubDate `json:"pubDate"`
-	Creator     []string    `json:"creator"`
-	Keywords    []string    `json:"keywords"`
-	Categories  []string    `json:"category"`
-	Content     string      `json:"content"`
+	ArticleID   string   `json:"article_id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Link        string   `json:"link"`
+	SourceID    string   `json:"source_id"`
+	PubDate     string   `json:"pubDate"`
+	Creator     []string `json:"creator"`
+	Keywords    []string `json:"keywords"`
+	Categories  []string `json:"category"`
+	Content     string   `json:"content"`
 }
 
-type NewsPubDate time.Time
-
-func (p *NewsPubDate) UnmarshalJSON(b []byte) error {
-	var t time.Time
-
-	s := strings.Trim(string(b), "\"")
-	if s != "null" {
+// Entity returns news of DTO. Publication date is parsed by dates, it is zero if date is null.
+func (dto *NewsDTO) Entity(dates *dateparse.Parser) (*entity.News, error) {
+	var pubDate time.Time
+	if dto.PubDate != "" {
 		var err error
-		t, err = time.Parse(time.DateTime, s)
+		pubDate, err = dates.Parse(dto.PubDate)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("dates.Parse: %w", err)
 		}
 	}
 
-	*p = NewsPubDate(t)
-	return nil
-}
-
-func (dto *NewsDTO) Entity() *entity.News {
 	entity := &entity.News{
 		NewsHead: entity.NewsHead{
 			Title:       dto.Title,
 			Description: dto.Description,
 			Source:      dto.SourceID,
-			PublishedAt: time.Time(dto.PubDate),
+			PublishedAt: pubDate,
+			ModifiedAt:  pubDate,
 		},
 		Link:       dto.Link,
 		Authors:    make([]string, len(dto.Creator)),
@@ -67,7 +62,7 @@ func (dto *NewsDTO) Entity() *entity.News {
 	copy(entity.Tags, dto.Keywords)
 	copy(entity.Categories, dto.Categories)
 
-	return entity
+	return entity, nil
 }
 
 type NewsResponseSuccess struct {
@@ -89,13 +84,15 @@ type news struct {
 	appID     string
 	accessKey string
 	client    *httpclient.Client
+	dates     *dateparse.Parser
 }
 
-func NewNews(appID string, accessKey string, client *httpclient.Client) *news {
+func NewNews(appID string, accessKey string, client *httpclient.Client, dates *dateparse.Parser) *news {
 	return &news{
 		appID:     appID,
 		accessKey: accessKey,
 		client:    client,
+		dates:     dates,
 	}
 }
 
@@ -135,10 +132,17 @@ func (n *news) parseResult(resp *http.Response) ([]entity.News, string, error) {
 		return nil, "", fmt.Errorf("httpresponse.JSON: %w", err)
 	}
 
+	fetchedAt := time.Now().UTC()
+
 	news := make([]entity.News, len(data.Results))
 	for i, result := range data.Results {
-		entity := result.Entity()
+		entity, err := result.Entity(n.dates)
+		if err != nil {
+			return nil, "", fmt.Errorf("result.Entity: %w", err)
+		}
+
 		entity.Source = n.appID
+		entity.FetchedAt = fetchedAt
 		news[i] = *entity
 	}
 
@@ -0,0 +1,26 @@
+// Package source registers newsdata parser in parser binary hosting several sources.
+package source
+
+import (
+	"context"
+	"fmt"
+
+	newsdata "github.com/qsoulior/news/newsdata-parser/internal/app"
+	"github.com/qsoulior/news/parser/app"
+	"github.com/qsoulior/news/parser/pkg/httpclient"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	app.Register(newsdata.APP_ID, New)
+}
+
+// New returns parsers of newsdata source.
+func New(ctx context.Context, decode func(cfg any) error, opts []httpclient.Option, logger *zerolog.Logger) (*app.Source, error) {
+	cfg := new(newsdata.Config)
+	if err := decode(cfg); err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+
+	return newsdata.New(ctx, cfg, opts, logger)
+}
@@ -3,31 +3,47 @@ package app
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
+	"runtime/debug"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/qsoulior/news/aggregator/pkg/httpserver"
+	"github.com/qsoulior/news/aggregator/pkg/message"
 	"github.com/qsoulior/news/aggregator/pkg/rabbitmq"
 	"github.com/qsoulior/news/aggregator/pkg/rabbitmq/consumer"
 	"github.com/qsoulior/news/aggregator/pkg/rabbitmq/producer"
 	"github.com/qsoulior/news/parser/internal/repo"
 	"github.com/qsoulior/news/parser/internal/service"
 	"github.com/qsoulior/news/parser/internal/transport/amqp"
+	"github.com/qsoulior/news/parser/internal/transport/http"
 	"github.com/qsoulior/news/parser/internal/worker"
+	"github.com/qsoulior/news/parser/pkg/dateparse"
 	"github.com/qsoulior/news/parser/pkg/redis"
+	"github.com/qsoulior/news/parser/pkg/scheduler"
+	"github.com/qsoulior/news/parser/pkg/shard"
 	"github.com/rs/zerolog"
 )
 
-var wg sync.WaitGroup
-
 type Config struct {
 	ID            string
+	Version       string
 	Logger        *zerolog.Logger
 	SearchParser  service.Parser
 	ArchiveParser service.Parser
 	FeedParser    service.Parser
+
+	// NewBackfillParser returns new archive parser crawling one of BackfillShards,
+	// so state of parser is not shared by shards. Shards are shared by replicas.
+	NewBackfillParser func() service.Parser
+	BackfillShards    []shard.Shard
+
+	// Close releases resources of parsers after parser is stopped.
+	Close func() error
 }
 
 type Options struct {
@@ -36,34 +52,150 @@ type Options struct {
 	ReleaseDelay *time.Duration
 	ArchiveDelay *time.Duration
 	FeedDelay    *time.Duration
+
+	// number of search pages parsed per request
+	SearchDepth    int
+	MaxSearchDepth int
+
+	// budget of source shared by search, feed and archive
+	Concurrency  int
+	RateInterval time.Duration
+
+	FeedSchedule    ScheduleOptions
+	ArchiveSchedule ScheduleOptions
+
+	// lease of singleton workers, expired lease is taken by another replica
+	LeaseTTL time.Duration
+
+	// claim of backfill shard expires without progress within ShardTTL
+	ShardTTL         time.Duration
+	ShardMaxAttempts int
+
+	// admin API is disabled if port is empty, it listens on loopback by default.
+	// Requests are authorized by bearer token, it is required on other hosts.
+	AdminHost  string
+	AdminPort  string
+	AdminToken string
+
+	// encoding of produced messages
+	ContentType     string
+	ContentEncoding string
+}
+
+// ScheduleOptions adjust schedule of worker runs.
+type ScheduleOptions struct {
+	// Cron is standard cron expression used instead of delay.
+	Cron string `yaml:"cron"`
+	// QuietHours are ranges of time "15:04-15:04" without runs.
+	QuietHours []string `yaml:"quiet_hours"`
+	// Timezone is IANA name of zone of cron and quiet hours, UTC by default.
+	Timezone string `yaml:"timezone"`
+	// MinDelay and MaxDelay bound delay adapted to number of new news.
+	MinDelay time.Duration `yaml:"min_delay"`
+	MaxDelay time.Duration `yaml:"max_delay"`
+	// MaxBackoff is ceiling of delay after errors.
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+}
+
+func (s ScheduleOptions) config(delay time.Duration) (worker.ScheduleConfig, error) {
+	loc, err := dateparse.LoadLocation(s.Timezone)
+	if err != nil {
+		return worker.ScheduleConfig{}, fmt.Errorf("dateparse.LoadLocation: %w", err)
+	}
+
+	return worker.ScheduleConfig{
+		Delay:      delay,
+		MinDelay:   s.MinDelay,
+		MaxDelay:   s.MaxDelay,
+		MaxBackoff: s.MaxBackoff,
+		Cron:       s.Cron,
+		QuietHours: s.QuietHours,
+		Location:   loc,
+	}, nil
 }
 
 var (
 	DefaultReleaseDelay = 15 * time.Minute
 	DefaultArchiveDelay = 5 * time.Second
 	DefaultFeedDelay    = 1 * time.Minute
+
+	// DefaultBackfillIdleDelay is maximum delay of backfill without shards.
+	DefaultBackfillIdleDelay = 1 * time.Minute
+
+	DefaultAdminHost = "127.0.0.1"
 )
 
 func (o *Options) setDefault() {
-	if o.ReleaseDelay == nil {
+	if o.ReleaseDelay == nil || *o.ReleaseDelay <= 0 {
 		o.ReleaseDelay = &DefaultReleaseDelay
 	}
-	if o.ArchiveDelay == nil {
+	if o.ArchiveDelay == nil || *o.ArchiveDelay <= 0 {
 		o.ArchiveDelay = &DefaultArchiveDelay
 	}
-	if o.FeedDelay == nil {
+	if o.FeedDelay == nil || *o.FeedDelay <= 0 {
 		o.FeedDelay = &DefaultFeedDelay
 	}
+	if o.AdminHost == "" {
+		o.AdminHost = DefaultAdminHost
+	}
 }
 
-func Run(cfg *Config, opts *Options) {
-	opts.setDefault()
+func (c *Config) setDefault() {
+	if c.Version == "" {
+		c.Version = buildVersion()
+	}
+}
+
+// loopback reports whether host is loopback address or localhost.
+func loopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// replicaID returns identifier of replica unique across restarts.
+func replicaID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "parser"
+	}
+
+	return hostname + "-" + uuid.NewString()[:8]
+}
+
+// buildVersion returns version of main module from build info.
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
 
+	return info.Main.Version
+}
+
+func init() {
+	zerolog.DurationFieldUnit = time.Second
+}
+
+func Run(cfg *Config, opts *Options) {
 	// notify context
 	sigCtx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
-	zerolog.DurationFieldUnit = time.Second
+	RunContext(sigCtx, cfg, opts)
+}
+
+// RunContext runs parser of source until context is done.
+// Parsers of several sources may run in one process, each of them
+// has own connections, workers and admin server.
+func RunContext(sigCtx context.Context, cfg *Config, opts *Options) {
+	cfg.setDefault()
+	opts.setDefault()
+
+	var wg sync.WaitGroup
 	var log zerolog.Logger
 
 	if cfg.Logger != nil {
@@ -78,6 +210,56 @@ func Run(cfg *Config, opts *Options) {
 	logger := log.With().Timestamp().Logger()
 	ctx := logger.WithContext(sigCtx)
 
+	if cfg.Close != nil {
+		defer func() {
+			if err := cfg.Close(); err != nil {
+				logger.Error().Err(err).Msg("parsers are not closed")
+			}
+		}()
+	}
+
+	// worker schedules
+	feedConfig, err := opts.FeedSchedule.config(*opts.FeedDelay)
+	if err != nil {
+		logger.Error().Err(err).Str("worker", "feed").Send()
+		return
+	}
+
+	feedSchedule, err := worker.NewSchedule(feedConfig)
+	if err != nil {
+		logger.Error().Err(err).Str("worker", "feed").Send()
+		return
+	}
+
+	archiveConfig, err := opts.ArchiveSchedule.config(*opts.ArchiveDelay)
+	if err != nil {
+		logger.Error().Err(err).Str("worker", "archive").Send()
+		return
+	}
+
+	archiveSchedule, err := worker.NewSchedule(archiveConfig)
+	if err != nil {
+		logger.Error().Err(err).Str("worker", "archive").Send()
+		return
+	}
+
+	releaseSchedule, err := worker.NewSchedule(worker.ScheduleConfig{Delay: *opts.ReleaseDelay})
+	if err != nil {
+		logger.Error().Err(err).Str("worker", "release").Send()
+		return
+	}
+
+	// backfill follows archive schedule
+	backfillConfig := archiveConfig
+	backfillConfig.MaxDelay = max(backfillConfig.MaxDelay, DefaultBackfillIdleDelay)
+	backfillSchedule, err := worker.NewSchedule(backfillConfig)
+	if err != nil {
+		logger.Error().Err(err).Str("worker", "backfill").Send()
+		return
+	}
+
+	workers := make([]worker.Worker, 0, 4)
+
 	// redis client
 	redisLog := logger.With().Str("module", "redis").Logger()
 	redis, err := redis.New(redisLog.WithContext(ctx), &redis.RedisConfig{
@@ -102,11 +284,33 @@ func Run(cfg *Config, opts *Options) {
 	}()
 
 	newsRepo := repo.NewNewsRedis(redis)
-	pageRepo := repo.NewPageRedis(redis)
+	pageService := service.NewPage(service.PageConfig{
+		Repo: repo.NewPageRedis(redis),
+	})
+
+	// pause of workers is shared by replicas
+	pauseService := service.NewPause(service.PauseConfig{
+		Repo: repo.NewPauseRedis(redis),
+	})
+
+	// singleton workers run on replica holding their lease
+	leaseService := service.NewLease(service.LeaseConfig{
+		Repo:   repo.NewLeaseRedis(redis),
+		Holder: replicaID(),
+		TTL:    opts.LeaseTTL,
+	})
+	logger.Info().Str("holder", leaseService.Holder()).Msg("replica started")
+
+	shardService := service.NewShard(service.ShardConfig{
+		Repo:        repo.NewShardRedis(redis),
+		Owner:       leaseService.Holder(),
+		TTL:         opts.ShardTTL,
+		MaxAttempts: opts.ShardMaxAttempts,
+	})
 
 	// rabbit connection
 	rmqLog := logger.With().Str("module", "rmq").Logger()
-	rmqConn, queue, err := runRMQ(rmqLog.WithContext(ctx), opts.RabbitURL, "query."+cfg.ID)
+	rmqConn, queues, err := runRMQ(rmqLog.WithContext(ctx), &wg, opts.RabbitURL, cfg.ID)
 	if err != nil {
 		rmqLog.Error().Err(err).Send()
 		return
@@ -122,46 +326,64 @@ func Run(cfg *Config, opts *Options) {
 		return
 	}
 
+	// search is prior to feed and archive
+	sched := scheduler.New(scheduler.Config{
+		Concurrency: opts.Concurrency,
+		Interval:    opts.RateInterval,
+	})
+
 	searchService := service.NewNews(service.NewsConfig{
 		Repo:   newsRepo,
-		Parser: cfg.SearchParser,
+		Parser: &scheduledParser{cfg.SearchParser, sched, scheduler.PrioritySearch},
 
 		Producer:   rmqProducer,
 		Exchange:   "",
 		RoutingKey: "news",
 		AppID:      cfg.ID,
+		Version:    cfg.Version,
+
+		ContentType:     opts.ContentType,
+		ContentEncoding: opts.ContentEncoding,
+
+		SearchDepth:    opts.SearchDepth,
+		MaxSearchDepth: opts.MaxSearchDepth,
 	})
-	runSearcher(ctx, searchService, rmqConn, queue)
+	runSearcher(ctx, &wg, searchService, rmqConn, queues, cfg.ID)
 
 	// archive worker
 	if cfg.ArchiveParser != nil {
 		archiveService := service.NewNews(service.NewsConfig{
 			Repo:   newsRepo,
-			Parser: cfg.ArchiveParser,
+			Parser: &scheduledParser{cfg.ArchiveParser, sched, scheduler.PriorityArchive},
 
 			Producer:   rmqProducer,
 			Exchange:   "",
 			RoutingKey: "news",
 			AppID:      cfg.ID,
+			Version:    cfg.Version,
+
+			ContentType:     opts.ContentType,
+			ContentEncoding: opts.ContentEncoding,
 		})
-		pageService := service.NewPage(service.PageConfig{
-			Repo: pageRepo,
-		})
-		runArchiver(ctx, archiveService, pageService, *opts.ArchiveDelay)
+		workers = append(workers, runArchiver(ctx, &wg, archiveService, pageService, leaseService, pauseService, archiveSchedule))
 	}
 
 	// feed worker
 	if cfg.FeedParser != nil {
 		feedService := service.NewNews(service.NewsConfig{
 			Repo:   newsRepo,
-			Parser: cfg.FeedParser,
+			Parser: &scheduledParser{cfg.FeedParser, sched, scheduler.PriorityFeed},
 
 			Producer:   rmqProducer,
 			Exchange:   "",
 			RoutingKey: "news",
 			AppID:      cfg.ID,
+			Version:    cfg.Version,
+
+			ContentType:     opts.ContentType,
+			ContentEncoding: opts.ContentEncoding,
 		})
-		runFeeder(ctx, feedService, *opts.FeedDelay)
+		workers = append(workers, runFeeder(ctx, &wg, feedService, leaseService, pauseService, feedSchedule))
 	}
 
 	// release worker
@@ -173,13 +395,53 @@ func Run(cfg *Config, opts *Options) {
 		Exchange:   "",
 		RoutingKey: "news",
 		AppID:      cfg.ID,
+		Version:    cfg.Version,
+
+		ContentType:     opts.ContentType,
+		ContentEncoding: opts.ContentEncoding,
 	})
-	runReleaser(ctx, releaseService, *opts.ReleaseDelay)
+	workers = append(workers, runReleaser(ctx, &wg, releaseService, leaseService, pauseService, releaseSchedule))
+
+	// backfill worker
+	if cfg.NewBackfillParser != nil && len(cfg.BackfillShards) > 0 {
+		count, err := shardService.Register(ctx, cfg.BackfillShards)
+		if err != nil {
+			logger.Error().Err(err).Send()
+			return
+		}
+		logger.Info().Int("count", count).Int("total", len(cfg.BackfillShards)).Msg("shards registered")
+
+		newBackfillService := func() service.News {
+			return service.NewNews(service.NewsConfig{
+				Repo:   newsRepo,
+				Parser: &scheduledParser{cfg.NewBackfillParser(), sched, scheduler.PriorityArchive},
+
+				Producer:   rmqProducer,
+				Exchange:   "",
+				RoutingKey: "news",
+				AppID:      cfg.ID,
+				Version:    cfg.Version,
+
+				ContentType:     opts.ContentType,
+				ContentEncoding: opts.ContentEncoding,
+			})
+		}
+		workers = append(workers, runBackfiller(ctx, &wg, newBackfillService, shardService, pauseService, backfillSchedule))
+	}
+
+	// admin server
+	if opts.AdminPort != "" {
+		bufferService := service.NewBuffer(service.BufferConfig{
+			Repo: newsRepo,
+		})
+		runAdmin(ctx, &wg, workers, shardService, bufferService, releaseService, pageService, leaseService, opts.AdminHost, opts.AdminPort, opts.AdminToken)
+	}
 
 	wg.Wait()
 }
 
-func runRMQ(ctx context.Context, url string, queueName string) (*rabbitmq.Connection, string, error) {
+// runRMQ declares queues of parse requests and returns their names.
+func runRMQ(ctx context.Context, wg *sync.WaitGroup, url string, appID string) (*rabbitmq.Connection, []string, error) {
 	logger := zerolog.Ctx(ctx)
 	rmqConn, err := rabbitmq.New(ctx, &rabbitmq.Config{
 		URL:          url,
@@ -187,22 +449,47 @@ func runRMQ(ctx context.Context, url string, queueName string) (*rabbitmq.Connec
 		AttemptDelay: 10 * time.Second,
 	})
 	if err != nil {
-		return nil, "", fmt.Errorf("rabbitmq.New: %w", err)
+		return nil, nil, fmt.Errorf("rabbitmq.New: %w", err)
+	}
+
+	err = rmqConn.Ch.ExchangeDeclare(message.ParseExchange, "topic", true, false, false, false, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("rmqConn.Ch.ExchangeDeclare: %w", err)
+	}
+
+	queue, err := rmqConn.Ch.QueueDeclare(message.ParseRoutingKey(appID), true, false, false, false, map[string]any{
+		"x-max-priority": message.MaxPriority,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("rmqConn.Ch.QueueDeclare: %w", err)
 	}
 
-	err = rmqConn.Ch.ExchangeDeclare("query", "fanout", true, false, false, false, nil)
+	_, err = rmqConn.Ch.QueueDeclare(message.ProgressQueue, true, false, false, false, nil)
 	if err != nil {
-		return nil, "", fmt.Errorf("rmqConn.Ch.ExchangeDeclare: %w", err)
+		return nil, nil, fmt.Errorf("rmqConn.Ch.QueueDeclare: %w", err)
+	}
+
+	for _, routingKey := range []string{message.ParseRoutingKey(appID), message.ParseRoutingAll} {
+		err = rmqConn.Ch.QueueBind(queue.Name, routingKey, message.ParseExchange, false, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("rmqConn.Ch.QueueBind: %w", err)
+		}
 	}
 
-	queue, err := rmqConn.Ch.QueueDeclare(queueName, true, false, false, false, nil)
+	// queue of previous release still receives requests of aggregators not updated yet
+	err = rmqConn.Ch.ExchangeDeclare(message.LegacyParseExchange, "fanout", true, false, false, false, nil)
 	if err != nil {
-		return nil, "", fmt.Errorf("rmqConn.Ch.QueueDeclare: %w", err)
+		return nil, nil, fmt.Errorf("rmqConn.Ch.ExchangeDeclare: %w", err)
 	}
 
-	err = rmqConn.Ch.QueueBind(queue.Name, "", "query", false, nil)
+	legacyQueue, err := rmqConn.Ch.QueueDeclare(message.LegacyParseQueue(appID), true, false, false, false, nil)
 	if err != nil {
-		return nil, "", fmt.Errorf("rmqConn.Ch.QueueBind: %w", err)
+		return nil, nil, fmt.Errorf("rmqConn.Ch.QueueDeclare: %w", err)
+	}
+
+	err = rmqConn.Ch.QueueBind(legacyQueue.Name, "", message.LegacyParseExchange, false, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("rmqConn.Ch.QueueBind: %w", err)
 	}
 
 	wg.Add(1)
@@ -220,38 +507,40 @@ func runRMQ(ctx context.Context, url string, queueName string) (*rabbitmq.Connec
 		logger.Info().Msg("graceful shutdown")
 	}(ctx)
 
-	return rmqConn, queue.Name, nil
+	return rmqConn, []string{queue.Name, legacyQueue.Name}, nil
 }
 
-func runSearcher(ctx context.Context, news service.News, conn *rabbitmq.Connection, queue string) {
+func runSearcher(ctx context.Context, wg *sync.WaitGroup, news service.News, conn *rabbitmq.Connection, queues []string, appID string) {
 	log := zerolog.Ctx(ctx).With().Str("module", "searcher").Logger()
 
-	amqpRouter := amqp.NewRouter(&log, news)
+	amqpRouter := amqp.NewRouter(&log, news, appID)
 	rmqConsumer := consumer.New(conn, amqpRouter, consumer.Ack(false))
 
-	wg.Add(1)
-	go func(ctx context.Context) {
-		defer wg.Done()
-		for timer := time.NewTimer(0); ; timer.Reset(5 * time.Second) {
-			select {
-			case <-ctx.Done():
-				timer.Stop()
-				return
-			case <-timer.C:
-				err := rmqConsumer.Consume(ctx, queue)
-				if err != nil {
-					log.Error().Err(err).Send()
+	for _, queue := range queues {
+		wg.Add(1)
+		go func(ctx context.Context) {
+			defer wg.Done()
+			for timer := time.NewTimer(0); ; timer.Reset(5 * time.Second) {
+				select {
+				case <-ctx.Done():
+					timer.Stop()
 					return
+				case <-timer.C:
+					err := rmqConsumer.Consume(ctx, queue)
+					if err != nil {
+						log.Error().Err(err).Str("queue", queue).Send()
+						return
+					}
+					log.Info().Str("queue", queue).Msg("graceful shutdown")
 				}
-				log.Info().Msg("graceful shutdown")
 			}
-		}
-	}(ctx)
+		}(ctx)
+	}
 
 	log.Info().Msg("started")
 }
 
-func runWorker(ctx context.Context, worker worker.Worker) {
+func runWorker(ctx context.Context, wg *sync.WaitGroup, worker worker.Worker) {
 	logger := zerolog.Ctx(ctx)
 
 	wg.Add(1)
@@ -268,23 +557,72 @@ func runWorker(ctx context.Context, worker worker.Worker) {
 	logger.Info().Msg("started")
 }
 
-func runArchiver(ctx context.Context, news service.News, page service.Page, delay time.Duration) {
+func runArchiver(ctx context.Context, wg *sync.WaitGroup, news service.News, page service.Page, lease service.Lease, pauses service.Pause, schedule *worker.Schedule) worker.Worker {
 	log := zerolog.Ctx(ctx).With().Str("module", "archiver").Logger()
-	worker := worker.NewArchive(delay, &log, news, page)
+	worker := worker.NewLeased(worker.NewArchive(schedule, pauses, &log, news, page), worker.ArchiveLease, lease, &log)
 
-	runWorker(log.WithContext(ctx), worker)
+	runWorker(log.WithContext(ctx), wg, worker)
+	return worker
 }
 
-func runReleaser(ctx context.Context, news service.News, delay time.Duration) {
+func runReleaser(ctx context.Context, wg *sync.WaitGroup, news service.News, lease service.Lease, pauses service.Pause, schedule *worker.Schedule) worker.Worker {
 	log := zerolog.Ctx(ctx).With().Str("module", "releaser").Logger()
-	worker := worker.NewRelease(delay, &log, news)
+	worker := worker.NewLeased(worker.NewRelease(schedule, pauses, &log, news), "release", lease, &log)
 
-	runWorker(log.WithContext(ctx), worker)
+	runWorker(log.WithContext(ctx), wg, worker)
+	return worker
 }
 
-func runFeeder(ctx context.Context, news service.News, delay time.Duration) {
+func runFeeder(ctx context.Context, wg *sync.WaitGroup, news service.News, lease service.Lease, pauses service.Pause, schedule *worker.Schedule) worker.Worker {
 	log := zerolog.Ctx(ctx).With().Str("module", "feeder").Logger()
-	worker := worker.NewFeed(delay, &log, news)
+	worker := worker.NewLeased(worker.NewFeed(schedule, pauses, &log, news), "feed", lease, &log)
+
+	runWorker(log.WithContext(ctx), wg, worker)
+	return worker
+}
+
+func runBackfiller(ctx context.Context, wg *sync.WaitGroup, newNews func() service.News, shards service.Shard, pauses service.Pause, schedule *worker.Schedule) worker.Worker {
+	log := zerolog.Ctx(ctx).With().Str("module", "backfiller").Logger()
+	worker := worker.NewBackfill(schedule, pauses, &log, newNews, shards)
+
+	runWorker(log.WithContext(ctx), wg, worker)
+	return worker
+}
+
+func runAdmin(ctx context.Context, wg *sync.WaitGroup, workers []worker.Worker, shards service.Shard, buffer service.Buffer, news service.News, page service.Page, lease service.Lease, host string, port string, token string) {
+	log := zerolog.Ctx(ctx).With().Str("module", "admin").Logger()
+
+	if token == "" && !loopback(host) {
+		log.Error().Str("host", host).Msg("admin token is required on non-loopback host")
+		return
+	}
+
+	httpRouter := http.NewRouter(workers, shards, buffer, news, page, lease, token)
+	httpServer := httpserver.New(httpRouter, httpserver.Addr(host, port))
+
+	wg.Add(1)
+	go func(ctx context.Context) {
+		defer wg.Done()
+		httpServer.Start(log.WithContext(ctx))
+
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("term signal accepted")
+		case err := <-httpServer.Err():
+			log.Error().Err(err).Send()
+		}
+
+		// http server graceful shutdown
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		err := httpServer.Stop(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("graceful shutdown")
+			return
+		}
+		log.Info().Msg("graceful shutdown")
+	}(log.WithContext(ctx))
 
-	runWorker(log.WithContext(ctx), worker)
+	log.Info().Str("addr", net.JoinHostPort(host, port)).Msg("started")
 }
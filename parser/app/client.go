@@ -0,0 +1,39 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qsoulior/news/parser/pkg/httpclient"
+)
+
+// HTTPOptions adjust outbound identity of HTTP clients of source.
+type HTTPOptions struct {
+	// Proxies are URLs of HTTP or SOCKS5 proxies, requests are sent directly if it is empty.
+	Proxies []string `yaml:"proxies"`
+	// ProxyCooldown is time proxy is not used after 403 or 429 response.
+	ProxyCooldown time.Duration `yaml:"proxy_cooldown"`
+	// ProxyMaxFailures is number of failed requests in a row after which proxy cools down.
+	ProxyMaxFailures int `yaml:"proxy_max_failures"`
+	// SessionTTL is time of sending same browser headers, they are not renewed if it is zero.
+	SessionTTL time.Duration `yaml:"session_ttl"`
+}
+
+// ClientOptions returns options of HTTP client of source.
+func (o HTTPOptions) ClientOptions() ([]httpclient.Option, error) {
+	opts := []httpclient.Option{httpclient.Session(o.SessionTTL)}
+	if len(o.Proxies) == 0 {
+		return opts, nil
+	}
+
+	pool, err := httpclient.NewProxyPool(httpclient.ProxyConfig{
+		URLs:        o.Proxies,
+		Cooldown:    o.ProxyCooldown,
+		MaxFailures: o.ProxyMaxFailures,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("httpclient.NewProxyPool: %w", err)
+	}
+
+	return append(opts, httpclient.Proxies(pool)), nil
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/parser/internal/service"
+	"github.com/qsoulior/news/parser/pkg/httpclient"
+	"github.com/qsoulior/news/parser/pkg/shard"
+	"github.com/rs/zerolog"
+)
+
+// Parser parses news of one mode of source.
+type Parser interface {
+	Parse(ctx context.Context, query string, page string) ([]entity.News, string, error)
+}
+
+// Source contains constructors of parsers of source by mode.
+// Constructor returns new parser on every call, mode without constructor is not run.
+type Source struct {
+	Search  func() Parser
+	Feed    func() Parser
+	Archive func() Parser
+
+	// Backfill crawls one of BackfillShards, parser of Archive is used if it is nil.
+	Backfill       func() Parser
+	BackfillShards []shard.Shard
+
+	Options *Options
+
+	// Close releases resources of parsers after parser is stopped.
+	Close func() error
+}
+
+// Config returns config of parser app running all modes of source.
+func (s *Source) Config(id string, logger *zerolog.Logger) *Config {
+	cfg := &Config{
+		ID:             id,
+		Logger:         logger,
+		BackfillShards: s.BackfillShards,
+		Close:          s.Close,
+	}
+
+	if s.Search != nil {
+		cfg.SearchParser = s.Search()
+	}
+	if s.Feed != nil {
+		cfg.FeedParser = s.Feed()
+	}
+	if s.Archive != nil {
+		cfg.ArchiveParser = s.Archive()
+	}
+
+	backfill := s.Backfill
+	if backfill == nil {
+		backfill = s.Archive
+	}
+
+	if backfill != nil {
+		cfg.NewBackfillParser = func() service.Parser { return backfill() }
+	}
+
+	return cfg
+}
+
+// Factory returns parsers of source. Decode fills config of source
+// from its section of parser config, opts override options of HTTP clients
+// of config, so newsctl records or replays responses of any source.
+type Factory func(ctx context.Context, decode func(cfg any) error, opts []httpclient.Option, logger *zerolog.Logger) (*Source, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes source available to parser binary by name.
+// It panics if source is registered twice, so it is called by init of source package.
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if factory == nil {
+		panic("app: factory of source " + name + " is nil")
+	}
+
+	if _, ok := factories[name]; ok {
+		panic("app: source " + name + " is registered twice")
+	}
+
+	factories[name] = factory
+}
+
+// Sources returns sorted names of registered sources.
+func Sources() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// NewSource returns parsers of registered source.
+func NewSource(ctx context.Context, name string, decode func(cfg any) error, opts []httpclient.Option, logger *zerolog.Logger) (*Source, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("source %q is not registered", name)
+	}
+
+	source, err := factory(ctx, decode, opts, logger)
+	if err != nil {
+		return nil, fmt.Errorf("factory: %w", err)
+	}
+
+	return source, nil
+}
+
+// New builds parser of registered source.
+func New(ctx context.Context, name string, decode func(cfg any) error, logger *zerolog.Logger) (*Config, *Options, error) {
+	source, err := NewSource(ctx, name, decode, nil, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return source.Config(name, logger), source.Options, nil
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/parser/internal/service"
+	"github.com/qsoulior/news/parser/pkg/scheduler"
+)
+
+// scheduledParser parses each page as job of scheduler shared by source.
+type scheduledParser struct {
+	parser    service.Parser
+	scheduler *scheduler.Scheduler
+	priority  scheduler.Priority
+}
+
+func (p *scheduledParser) Parse(ctx context.Context, query string, page string) ([]entity.News, string, error) {
+	jobCtx, release, err := p.scheduler.Acquire(ctx, p.priority)
+	if err != nil {
+		return nil, "", fmt.Errorf("p.scheduler.Acquire: %w", err)
+	}
+	defer release()
+
+	news, nextPage, err := p.parser.Parse(jobCtx, query, page)
+	if err != nil && errors.Is(context.Cause(jobCtx), scheduler.ErrPreempted) {
+		return nil, "", scheduler.ErrPreempted
+	}
+
+	return news, nextPage, err
+}
@@ -0,0 +1,304 @@
+// Package cli implements newsctl, a tool parsing news of sources without
+// running parser service.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/parser/app"
+	"github.com/qsoulior/news/parser/pkg/fixture"
+	"github.com/qsoulior/news/parser/pkg/httpclient"
+	"github.com/rs/zerolog"
+)
+
+// OneParser parses single news by URL.
+// Parsers of source implement it if source supports it.
+type OneParser interface {
+	ParseOne(ctx context.Context, url string) (*entity.News, error)
+}
+
+var (
+	ErrUnknownCommand = errors.New("unknown command")
+	ErrUnknownSource  = errors.New("unknown source")
+	ErrUnsupported    = errors.New("source does not support mode")
+)
+
+const usage = `Usage: newsctl [-c config] <command> [flags]
+
+Commands:
+  sources   list registered sources
+  parse     parse page of search, feed or archive
+  one       parse single news by URL
+`
+
+// Run executes command given by args against sources registered in parser app.
+// Decoder returns function filling config of source by its name.
+func Run(decoder func(name string) func(cfg any) error, args []string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	c := &cli{sources: app.Sources(), decoder: decoder, stdout: os.Stdout, stderr: os.Stderr}
+
+	if len(args) == 0 {
+		fmt.Fprint(c.stderr, usage)
+		return nil
+	}
+
+	switch args[0] {
+	case "sources":
+		return c.list()
+	case "parse":
+		return c.parse(ctx, args[1:])
+	case "one":
+		return c.one(ctx, args[1:])
+	case "help", "-h", "-help":
+		fmt.Fprint(c.stderr, usage)
+		return nil
+	}
+
+	return fmt.Errorf("%w %q", ErrUnknownCommand, args[0])
+}
+
+type cli struct {
+	sources []string
+	decoder func(name string) func(cfg any) error
+	stdout  io.Writer
+	stderr  io.Writer
+}
+
+// options are flags shared by commands.
+type options struct {
+	source   string
+	format   string
+	publish  string
+	fixtures string
+	record   string
+	timeout  time.Duration
+	verbose  bool
+}
+
+func (c *cli) flags(name string, opts *options) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.StringVar(&opts.source, "source", "", "source id, required if several sources are registered")
+	fs.StringVar(&opts.format, "format", "json", "output format: json or table")
+	fs.StringVar(&opts.publish, "publish", "", "RabbitMQ URL to publish parsed news to aggregator")
+	fs.StringVar(&opts.fixtures, "fixtures", "", "directory of recorded responses to parse offline")
+	fs.StringVar(&opts.record, "record", "", "directory to record responses to")
+	fs.DurationVar(&opts.timeout, "timeout", 30*time.Second, "timeout of HTTP requests")
+	fs.BoolVar(&opts.verbose, "v", false, "log debug messages")
+	return fs
+}
+
+func (c *cli) list() error {
+	for _, name := range c.sources {
+		fmt.Fprintln(c.stdout, name)
+	}
+	return nil
+}
+
+func (c *cli) parse(ctx context.Context, args []string) error {
+	var (
+		opts        options
+		mode        string
+		query, page string
+	)
+
+	fs := c.flags("parse", &opts)
+	fs.StringVar(&mode, "mode", "feed", "parser mode: search, feed or archive")
+	fs.StringVar(&query, "query", "", "search query")
+	fs.StringVar(&page, "page", "", "page of search or archive")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	name, source, err := c.parsers(ctx, &opts)
+	if err != nil {
+		return err
+	}
+	defer closeSource(source)
+
+	var newParser func() app.Parser
+	switch mode {
+	case "search":
+		newParser = source.Search
+	case "feed":
+		newParser = source.Feed
+	case "archive":
+		newParser = source.Archive
+	default:
+		return fmt.Errorf("unknown mode %q", mode)
+	}
+
+	if newParser == nil {
+		return fmt.Errorf("%w %q", ErrUnsupported, mode)
+	}
+
+	parser := newParser()
+	news, nextPage, err := parser.Parse(ctx, query, page)
+	if err != nil {
+		return fmt.Errorf("parser.Parse: %w", err)
+	}
+
+	if err := c.print(news, opts.format); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stderr, "count: %d, next page: %q\n", len(news), nextPage)
+
+	return c.publish(ctx, name, news, opts.publish)
+}
+
+func (c *cli) one(ctx context.Context, args []string) error {
+	var (
+		opts options
+		url  string
+	)
+
+	fs := c.flags("one", &opts)
+	fs.StringVar(&url, "url", "", "URL of news")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if url == "" {
+		return errors.New("url is required")
+	}
+
+	name, source, err := c.parsers(ctx, &opts)
+	if err != nil {
+		return err
+	}
+	defer closeSource(source)
+
+	var parser OneParser
+	for _, newParser := range []func() app.Parser{source.Feed, source.Archive, source.Search} {
+		if newParser == nil {
+			continue
+		}
+
+		if one, ok := newParser().(OneParser); ok {
+			parser = one
+			break
+		}
+	}
+
+	if parser == nil {
+		return fmt.Errorf("%w %q", ErrUnsupported, "one")
+	}
+
+	news, err := parser.ParseOne(ctx, url)
+	if err != nil {
+		return fmt.Errorf("parser.ParseOne: %w", err)
+	}
+
+	if err := c.print([]entity.News{*news}, opts.format); err != nil {
+		return err
+	}
+
+	return c.publish(ctx, name, []entity.News{*news}, opts.publish)
+}
+
+// parsers builds parsers of source selected by options.
+func (c *cli) parsers(ctx context.Context, opts *options) (string, *app.Source, error) {
+	name, err := c.source(opts.source)
+	if err != nil {
+		return "", nil, err
+	}
+
+	level := zerolog.InfoLevel
+	if opts.verbose {
+		level = zerolog.DebugLevel
+	}
+
+	out := zerolog.NewConsoleWriter(func(w *zerolog.ConsoleWriter) {
+		w.Out = c.stderr
+		w.TimeFormat = time.RFC3339
+	})
+	logger := zerolog.New(out).Level(level).With().Timestamp().Str("source", name).Logger()
+
+	clientOpts := []httpclient.Option{httpclient.Timeout(opts.timeout)}
+	switch {
+	case opts.fixtures != "" && opts.record != "":
+		return "", nil, errors.New("fixtures and record are mutually exclusive")
+	case opts.fixtures != "":
+		clientOpts = append(clientOpts, httpclient.Transport(fixture.NewReplayer(opts.fixtures)))
+	case opts.record != "":
+		// responses are recorded through proxies of source
+		record, err := fixture.Recorder(opts.record)
+		if err != nil {
+			return "", nil, fmt.Errorf("fixture.Recorder: %w", err)
+		}
+		clientOpts = append(clientOpts, httpclient.WrapTransport(record))
+	}
+
+	source, err := app.NewSource(logger.WithContext(ctx), name, c.decoder(name), clientOpts, &logger)
+	if err != nil {
+		return "", nil, fmt.Errorf("app.NewSource: %w", err)
+	}
+
+	return name, source, nil
+}
+
+// source returns name of registered source, it may be omitted if single source is registered.
+func (c *cli) source(name string) (string, error) {
+	if name == "" && len(c.sources) == 1 {
+		return c.sources[0], nil
+	}
+
+	if name == "" {
+		return "", fmt.Errorf("source is required, registered: %s", strings.Join(c.sources, ", "))
+	}
+
+	if !slices.Contains(c.sources, name) {
+		return "", fmt.Errorf("%w %q", ErrUnknownSource, name)
+	}
+
+	return name, nil
+}
+
+func closeSource(source *app.Source) {
+	if source.Close != nil {
+		source.Close()
+	}
+}
+
+func (c *cli) print(news []entity.News, format string) error {
+	switch format {
+	case "json":
+		e := json.NewEncoder(c.stdout)
+		e.SetIndent("", "  ")
+		e.SetEscapeHTML(false)
+		return e.Encode(news)
+	case "table":
+		w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PUBLISHED\tSOURCE\tTITLE\tLINK")
+		for _, item := range news {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+				item.PublishedAt.Format(time.DateTime), item.Source, truncate(item.Title, 60), item.Link)
+		}
+		return w.Flush()
+	}
+
+	return fmt.Errorf("unknown format %q", format)
+}
+
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/aggregator/pkg/codec"
+	"github.com/qsoulior/news/aggregator/pkg/message"
+	"github.com/qsoulior/news/aggregator/pkg/rabbitmq"
+	"github.com/qsoulior/news/aggregator/pkg/rabbitmq/producer"
+)
+
+// PARSER_VERSION is parser version of news published by newsctl.
+const PARSER_VERSION = "newsctl"
+
+// publish produces news to queue of aggregator if url is set.
+func (c *cli) publish(ctx context.Context, source string, news []entity.News, url string) error {
+	if url == "" {
+		return nil
+	}
+
+	conn, err := rabbitmq.New(ctx, &rabbitmq.Config{
+		URL:          url,
+		AttemptCount: 1,
+		AttemptDelay: time.Second,
+	})
+	if err != nil {
+		return fmt.Errorf("rabbitmq.New: %w", err)
+	}
+	defer conn.Close()
+
+	p := producer.New(conn)
+
+	count := 0
+	for _, item := range news {
+		env := message.New(item, PARSER_VERSION)
+		body, err := message.Encode(env)
+		var validationErr *message.ValidationError
+		if errors.As(err, &validationErr) {
+			fmt.Fprintf(c.stderr, "invalid news %s: %s\n", item.Link, err)
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("message.Encode: %w", err)
+		}
+
+		err = p.Produce(ctx, "", "news", rabbitmq.Message{
+			AppId:        source,
+			MessageId:    uuid.NewString(),
+			ContentType:  codec.ContentTypeJSON,
+			DeliveryMode: 2,
+			Body:         body,
+		})
+		if err != nil {
+			return fmt.Errorf("p.Produce: %w", err)
+		}
+
+		count++
+	}
+
+	fmt.Fprintf(c.stderr, "published: %d\n", count)
+	return nil
+}
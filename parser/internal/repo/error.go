@@ -2,4 +2,8 @@ package repo
 
 import "errors"
 
-var ErrNotExist = errors.New("entity does not exist")
+var (
+	ErrNotExist = errors.New("entity does not exist")
+	ErrFenced   = errors.New("fencing token is stale")
+	ErrNotOwner = errors.New("shard is claimed by another owner")
+)
@@ -0,0 +1,78 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qsoulior/news/parser/pkg/redis"
+	rdb "github.com/redis/go-redis/v9"
+)
+
+// acquireScript renews lease of holder or takes free lease with incremented fencing token.
+var acquireScript = rdb.NewScript(`
+local holder = redis.call("HGET", KEYS[1], "holder")
+if holder == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return tonumber(redis.call("HGET", KEYS[1], "token"))
+end
+
+if holder then
+	return 0
+end
+
+local token = redis.call("INCR", KEYS[2])
+redis.call("HSET", KEYS[1], "holder", ARGV[1], "token", token)
+redis.call("PEXPIRE", KEYS[1], ARGV[2])
+return token
+`)
+
+var releaseScript = rdb.NewScript(`
+if redis.call("HGET", KEYS[1], "holder") == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+type leaseRedis struct {
+	*redis.Redis
+}
+
+func NewLeaseRedis(redis *redis.Redis) Lease {
+	return &leaseRedis{redis}
+}
+
+// Acquire takes or renews lease and returns its fencing token.
+// Zero token is returned if lease is held by another holder.
+func (l *leaseRedis) Acquire(ctx context.Context, name string, holder string, ttl time.Duration) (int64, error) {
+	keys := []string{"lease:" + name, "lease:" + name + ":token"}
+	token, err := acquireScript.Run(ctx, l.Client, keys, holder, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("acquireScript.Run: %w", err)
+	}
+
+	return token, nil
+}
+
+func (l *leaseRedis) Release(ctx context.Context, name string, holder string) error {
+	err := releaseScript.Run(ctx, l.Client, []string{"lease:" + name}, holder).Err()
+	if err != nil {
+		return fmt.Errorf("releaseScript.Run: %w", err)
+	}
+
+	return nil
+}
+
+// Token returns last fencing token of lease or zero if lease is never taken.
+func (l *leaseRedis) Token(ctx context.Context, name string) (int64, error) {
+	token, err := l.Client.Get(ctx, "lease:"+name+":token").Int64()
+	if err == rdb.Nil {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("l.Client.Get: %w", err)
+	}
+
+	return token, nil
+}
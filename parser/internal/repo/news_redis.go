@@ -54,6 +54,24 @@ func (n *newsRedis) GetAll(ctx context.Context) ([]string, error) {
 	return jsonStrs, nil
 }
 
+func (n *newsRedis) GetRange(ctx context.Context, start int, stop int) ([]string, error) {
+	jsonStrs, err := n.Client.LRange(ctx, "news", int64(start), int64(stop)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("n.Client.LRange: %w", err)
+	}
+
+	return jsonStrs, nil
+}
+
+func (n *newsRedis) Len(ctx context.Context) (int, error) {
+	length, err := n.Client.LLen(ctx, "news").Result()
+	if err != nil {
+		return 0, fmt.Errorf("n.Client.LLen: %w", err)
+	}
+
+	return int(length), nil
+}
+
 func (n *newsRedis) PopFirst(ctx context.Context) (string, error) {
 	jsonStr, err := n.Client.LPop(ctx, "news").Result()
 	if err != nil {
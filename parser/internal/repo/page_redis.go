@@ -8,6 +8,21 @@ import (
 	rdb "github.com/redis/go-redis/v9"
 )
 
+// updateScript sets page unless it is set with greater fencing token.
+var updateScript = rdb.NewScript(`
+local fence = tonumber(redis.call("GET", KEYS[2]) or "0")
+local token = tonumber(ARGV[2])
+if token > 0 then
+	if token < fence then
+		return 0
+	end
+	redis.call("SET", KEYS[2], token)
+end
+
+redis.call("SET", KEYS[1], ARGV[1])
+return 1
+`)
+
 type pageRedis struct {
 	*redis.Redis
 }
@@ -29,10 +44,15 @@ func (p *pageRedis) Get(ctx context.Context) (string, error) {
 	return page, nil
 }
 
-func (p *pageRedis) Update(ctx context.Context, value string) error {
-	err := p.Client.Set(ctx, "page", value, 0).Err()
+// Update sets page if fencing token is not stale. Zero token is not checked.
+func (p *pageRedis) Update(ctx context.Context, value string, token int64) error {
+	updated, err := updateScript.Run(ctx, p.Client, []string{"page", "page:fence"}, value, token).Int()
 	if err != nil {
-		return fmt.Errorf("p.Client.Set: %w", err)
+		return fmt.Errorf("updateScript.Run: %w", err)
+	}
+
+	if updated == 0 {
+		return ErrFenced
 	}
 
 	return nil
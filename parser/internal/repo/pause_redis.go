@@ -0,0 +1,41 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qsoulior/news/parser/pkg/redis"
+)
+
+type pauseRedis struct {
+	*redis.Redis
+}
+
+func NewPauseRedis(redis *redis.Redis) Pause {
+	return &pauseRedis{redis}
+}
+
+// Get reports whether worker is paused. Worker is not paused if key does not exist.
+func (p *pauseRedis) Get(ctx context.Context, name string) (bool, error) {
+	paused, err := p.Client.Exists(ctx, "paused:"+name).Result()
+	if err != nil {
+		return false, fmt.Errorf("p.Client.Exists: %w", err)
+	}
+
+	return paused > 0, nil
+}
+
+func (p *pauseRedis) Set(ctx context.Context, name string, paused bool) error {
+	if !paused {
+		if err := p.Client.Del(ctx, "paused:"+name).Err(); err != nil {
+			return fmt.Errorf("p.Client.Del: %w", err)
+		}
+		return nil
+	}
+
+	if err := p.Client.Set(ctx, "paused:"+name, 1, 0).Err(); err != nil {
+		return fmt.Errorf("p.Client.Set: %w", err)
+	}
+
+	return nil
+}
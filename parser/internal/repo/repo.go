@@ -2,6 +2,9 @@ package repo
 
 import (
 	"context"
+	"time"
+
+	"github.com/qsoulior/news/parser/pkg/shard"
 )
 
 type News interface {
@@ -11,6 +14,8 @@ type News interface {
 	GetFirst(ctx context.Context) (string, error)
 	GetLast(ctx context.Context) (string, error)
 	GetAll(ctx context.Context) ([]string, error)
+	GetRange(ctx context.Context, start int, stop int) ([]string, error)
+	Len(ctx context.Context) (int, error)
 
 	PopFirst(ctx context.Context) (string, error)
 	PopLast(ctx context.Context) (string, error)
@@ -23,5 +28,25 @@ type News interface {
 
 type Page interface {
 	Get(ctx context.Context) (string, error)
-	Update(ctx context.Context, value string) error
+	Update(ctx context.Context, value string, token int64) error
+}
+
+type Shard interface {
+	Register(ctx context.Context, shards []shard.Shard) (int, error)
+	Claim(ctx context.Context, owner string, ttl time.Duration, maxAttempts int) (*shard.Shard, error)
+	Progress(ctx context.Context, id string, owner string, page string, count int, ttl time.Duration) error
+	Complete(ctx context.Context, id string, owner string) error
+	Fail(ctx context.Context, id string, owner string, maxAttempts int, reason string) error
+	GetAll(ctx context.Context) ([]shard.Shard, error)
+}
+
+type Lease interface {
+	Acquire(ctx context.Context, name string, holder string, ttl time.Duration) (int64, error)
+	Release(ctx context.Context, name string, holder string) error
+	Token(ctx context.Context, name string) (int64, error)
+}
+
+type Pause interface {
+	Get(ctx context.Context, name string) (bool, error)
+	Set(ctx context.Context, name string, paused bool) error
 }
@@ -0,0 +1,231 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/qsoulior/news/parser/pkg/redis"
+	"github.com/qsoulior/news/parser/pkg/shard"
+	rdb "github.com/redis/go-redis/v9"
+)
+
+// Shards are stored in hashes "shard:<id>". Pending shards are queued in list,
+// claimed shards are sorted by claim deadline to be stolen after it.
+const (
+	shardsAll     = "shards:all"
+	shardsPending = "shards:pending"
+	shardsClaimed = "shards:claimed"
+	shardPrefix   = "shard:"
+)
+
+var registerScript = rdb.NewScript(`
+if redis.call("SADD", KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+
+redis.call("HSET", KEYS[3], "id", ARGV[1], "from", ARGV[2], "to", ARGV[3], "page", ARGV[2],
+	"state", "pending", "count", 0, "attempts", 0)
+redis.call("RPUSH", KEYS[2], ARGV[1])
+return 1
+`)
+
+// claimScript steals expired shard as next attempt, shard is failed
+// instead if it has no attempts left.
+var claimScript = rdb.NewScript(`
+local id = redis.call("LPOP", KEYS[1])
+while not id do
+	local expired = redis.call("ZRANGEBYSCORE", KEYS[2], "-inf", ARGV[2], "LIMIT", 0, 1)
+	if #expired == 0 then
+		return false
+	end
+
+	local key = ARGV[4] .. expired[1]
+	local attempts = redis.call("HINCRBY", key, "attempts", 1)
+	if attempts < tonumber(ARGV[5]) then
+		id = expired[1]
+	else
+		redis.call("ZREM", KEYS[2], expired[1])
+		redis.call("HDEL", key, "owner")
+		redis.call("HSET", key, "state", "failed", "error", "claim expired")
+	end
+end
+
+local key = ARGV[4] .. id
+redis.call("ZADD", KEYS[2], tonumber(ARGV[2]) + tonumber(ARGV[3]), id)
+redis.call("HSET", key, "owner", ARGV[1], "state", "running")
+return redis.call("HGETALL", key)
+`)
+
+var progressScript = rdb.NewScript(`
+if redis.call("HGET", KEYS[1], "owner") ~= ARGV[1] then
+	return 0
+end
+
+redis.call("HSET", KEYS[1], "page", ARGV[2])
+redis.call("HINCRBY", KEYS[1], "count", ARGV[3])
+redis.call("ZADD", KEYS[2], ARGV[4], ARGV[5])
+return 1
+`)
+
+var completeScript = rdb.NewScript(`
+if redis.call("HGET", KEYS[1], "owner") ~= ARGV[1] then
+	return 0
+end
+
+redis.call("HSET", KEYS[1], "state", "done")
+redis.call("HDEL", KEYS[1], "owner", "error")
+redis.call("ZREM", KEYS[2], ARGV[2])
+return 1
+`)
+
+var failScript = rdb.NewScript(`
+if redis.call("HGET", KEYS[1], "owner") ~= ARGV[1] then
+	return 0
+end
+
+redis.call("ZREM", KEYS[2], ARGV[2])
+redis.call("HDEL", KEYS[1], "owner")
+redis.call("HSET", KEYS[1], "error", ARGV[4])
+
+local attempts = redis.call("HINCRBY", KEYS[1], "attempts", 1)
+if attempts >= tonumber(ARGV[3]) then
+	redis.call("HSET", KEYS[1], "state", "failed")
+else
+	redis.call("HSET", KEYS[1], "state", "pending")
+	redis.call("RPUSH", KEYS[3], ARGV[2])
+end
+return 1
+`)
+
+type shardRedis struct {
+	*redis.Redis
+}
+
+func NewShardRedis(redis *redis.Redis) Shard {
+	return &shardRedis{redis}
+}
+
+// Register adds shards that are not added yet.
+func (s *shardRedis) Register(ctx context.Context, shards []shard.Shard) (int, error) {
+	count := 0
+	for _, item := range shards {
+		keys := []string{shardsAll, shardsPending, shardPrefix + item.ID}
+		added, err := registerScript.Run(ctx, s.Client, keys, item.ID, item.From, item.To).Int()
+		if err != nil {
+			return count, fmt.Errorf("registerScript.Run: %w", err)
+		}
+		count += added
+	}
+
+	return count, nil
+}
+
+// Claim takes pending shard or steals shard with expired claim.
+// Expired shard is failed after maximum number of attempts.
+func (s *shardRedis) Claim(ctx context.Context, owner string, ttl time.Duration, maxAttempts int) (*shard.Shard, error) {
+	now := time.Now().UnixMilli()
+	keys := []string{shardsPending, shardsClaimed}
+	values, err := claimScript.Run(ctx, s.Client, keys, owner, now, ttl.Milliseconds(), shardPrefix, maxAttempts).StringSlice()
+	if err == rdb.Nil {
+		return nil, ErrNotExist
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("claimScript.Run: %w", err)
+	}
+
+	fields := make(map[string]string, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		fields[values[i]] = values[i+1]
+	}
+
+	return shardFromFields(fields), nil
+}
+
+// Progress sets page of claimed shard and extends claim.
+func (s *shardRedis) Progress(ctx context.Context, id string, owner string, page string, count int, ttl time.Duration) error {
+	deadline := time.Now().Add(ttl).UnixMilli()
+	keys := []string{shardPrefix + id, shardsClaimed}
+	ok, err := progressScript.Run(ctx, s.Client, keys, owner, page, count, deadline, id).Int()
+	if err != nil {
+		return fmt.Errorf("progressScript.Run: %w", err)
+	}
+
+	if ok == 0 {
+		return ErrNotOwner
+	}
+
+	return nil
+}
+
+func (s *shardRedis) Complete(ctx context.Context, id string, owner string) error {
+	keys := []string{shardPrefix + id, shardsClaimed}
+	ok, err := completeScript.Run(ctx, s.Client, keys, owner, id).Int()
+	if err != nil {
+		return fmt.Errorf("completeScript.Run: %w", err)
+	}
+
+	if ok == 0 {
+		return ErrNotOwner
+	}
+
+	return nil
+}
+
+// Fail returns shard to queue or fails it after maximum number of attempts.
+func (s *shardRedis) Fail(ctx context.Context, id string, owner string, maxAttempts int, reason string) error {
+	keys := []string{shardPrefix + id, shardsClaimed, shardsPending}
+	ok, err := failScript.Run(ctx, s.Client, keys, owner, id, maxAttempts, reason).Int()
+	if err != nil {
+		return fmt.Errorf("failScript.Run: %w", err)
+	}
+
+	if ok == 0 {
+		return ErrNotOwner
+	}
+
+	return nil
+}
+
+func (s *shardRedis) GetAll(ctx context.Context) ([]shard.Shard, error) {
+	ids, err := s.Client.SMembers(ctx, shardsAll).Result()
+	if err != nil {
+		return nil, fmt.Errorf("s.Client.SMembers: %w", err)
+	}
+
+	pipe := s.Client.Pipeline()
+	cmds := make([]*rdb.MapStringStringCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.HGetAll(ctx, shardPrefix+id)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("pipe.Exec: %w", err)
+	}
+
+	shards := make([]shard.Shard, len(cmds))
+	for i, cmd := range cmds {
+		shards[i] = *shardFromFields(cmd.Val())
+	}
+
+	return shards, nil
+}
+
+func shardFromFields(fields map[string]string) *shard.Shard {
+	count, _ := strconv.Atoi(fields["count"])
+	attempts, _ := strconv.Atoi(fields["attempts"])
+
+	return &shard.Shard{
+		ID:       fields["id"],
+		From:     fields["from"],
+		To:       fields["to"],
+		Page:     fields["page"],
+		State:    shard.State(fields["state"]),
+		Count:    count,
+		Attempts: attempts,
+		Owner:    fields["owner"],
+		Error:    fields["error"],
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qsoulior/news/parser/internal/repo"
+)
+
+// buffer inspects news buffered while broker is unavailable.
+type buffer struct {
+	BufferConfig
+}
+
+type BufferConfig struct {
+	Repo repo.News
+}
+
+func NewBuffer(cfg BufferConfig) *buffer {
+	return &buffer{
+		BufferConfig: cfg,
+	}
+}
+
+// List returns first buffered news and length of buffer.
+func (b *buffer) List(ctx context.Context, limit int) ([]string, int, error) {
+	length, err := b.Repo.Len(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("b.Repo.Len: %w", err)
+	}
+
+	if limit <= 0 || length == 0 {
+		return []string{}, length, nil
+	}
+
+	items, err := b.Repo.GetRange(ctx, 0, limit-1)
+	if err != nil {
+		return nil, 0, fmt.Errorf("b.Repo.GetRange: %w", err)
+	}
+
+	return items, length, nil
+}
+
+// Flush deletes buffered news and returns their number.
+func (b *buffer) Flush(ctx context.Context) (int, error) {
+	items, err := b.Repo.PopAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("b.Repo.PopAll: %w", err)
+	}
+
+	return len(items), nil
+}
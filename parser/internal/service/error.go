@@ -4,4 +4,6 @@ import "github.com/qsoulior/news/parser/internal/repo"
 
 var (
 	ErrNotExist = repo.ErrNotExist
+	ErrFenced   = repo.ErrFenced
+	ErrNotOwner = repo.ErrNotOwner
 )
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qsoulior/news/parser/internal/repo"
+)
+
+type lease struct {
+	LeaseConfig
+}
+
+type LeaseConfig struct {
+	Repo repo.Lease
+	// Holder identifies replica.
+	Holder string
+	TTL    time.Duration
+}
+
+const DefaultLeaseTTL = 30 * time.Second
+
+func NewLease(cfg LeaseConfig) *lease {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultLeaseTTL
+	}
+
+	return &lease{
+		LeaseConfig: cfg,
+	}
+}
+
+// Acquire takes or renews lease of replica and returns its fencing token.
+func (l *lease) Acquire(ctx context.Context, name string) (int64, bool, error) {
+	token, err := l.Repo.Acquire(ctx, name, l.LeaseConfig.Holder, l.LeaseConfig.TTL)
+	if err != nil {
+		return 0, false, fmt.Errorf("l.Repo.Acquire: %w", err)
+	}
+
+	return token, token > 0, nil
+}
+
+func (l *lease) Release(ctx context.Context, name string) error {
+	err := l.Repo.Release(ctx, name, l.LeaseConfig.Holder)
+	if err != nil {
+		return fmt.Errorf("l.Repo.Release: %w", err)
+	}
+
+	return nil
+}
+
+// Token returns last fencing token of lease, it is stale once lease is taken again.
+func (l *lease) Token(ctx context.Context, name string) (int64, error) {
+	token, err := l.Repo.Token(ctx, name)
+	if err != nil {
+		return 0, fmt.Errorf("l.Repo.Token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (l *lease) TTL() time.Duration {
+	return l.LeaseConfig.TTL
+}
+
+func (l *lease) Holder() string {
+	return l.LeaseConfig.Holder
+}
@@ -2,14 +2,17 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/aggregator/pkg/codec"
+	"github.com/qsoulior/news/aggregator/pkg/message"
 	"github.com/qsoulior/news/aggregator/pkg/rabbitmq"
 	"github.com/qsoulior/news/parser/internal/repo"
+	"github.com/rs/zerolog"
 )
 
 type Parser interface {
@@ -28,40 +31,181 @@ type NewsConfig struct {
 	Exchange   string
 	RoutingKey string
 	AppID      string
+	Version    string
+
+	ContentType     string
+	ContentEncoding string
+
+	// SearchDepth is number of pages parsed if request has no depth.
+	SearchDepth int
+	// MaxSearchDepth limits depth of requests.
+	MaxSearchDepth int
 }
 
+const (
+	DefaultSearchDepth    = 1
+	DefaultMaxSearchDepth = 5
+)
+
 func NewNews(cfg NewsConfig) *news {
+	if cfg.ContentType == "" {
+		cfg.ContentType = codec.ContentTypeJSON
+	}
+
+	if cfg.SearchDepth <= 0 {
+		cfg.SearchDepth = DefaultSearchDepth
+	}
+
+	if cfg.MaxSearchDepth <= 0 {
+		cfg.MaxSearchDepth = DefaultMaxSearchDepth
+	}
+
 	return &news{
 		NewsConfig: cfg,
 	}
 }
 
 func (n *news) Parse(ctx context.Context, query string, page string) (int, string, error) {
-	count := 0
+	results, nextPage, err := n.Parser.Parse(ctx, query, page)
+	if err != nil {
+		return 0, "", fmt.Errorf("n.Parser.Parse: %w", err)
+	}
+
+	count, err := n.produceMany(ctx, results, "")
+	return count, nextPage, err
+}
+
+// Search parses pages of search results up to depth of request
+// and reports progress of request after each page. News outside date range
+// of request are skipped, parsing stops at page without news in range.
+func (n *news) Search(ctx context.Context, req *message.ParseRequest) (int, error) {
+	n.report(ctx, req.ID, entity.ParseJobRunning, 0, nil)
+
+	depth := req.Depth
+	if depth <= 0 {
+		depth = n.SearchDepth
+	}
+	depth = min(depth, n.MaxSearchDepth)
+
+	count, page := 0, req.Page
+	for i := 0; i < depth; i++ {
+		parsed, nextPage, err := n.search(ctx, req, page)
+		count += parsed
+		if err != nil {
+			n.report(ctx, req.ID, entity.ParseJobFailed, count, err)
+			return count, err
+		}
+
+		if nextPage == "" {
+			break
+		}
+		page = nextPage
+
+		n.report(ctx, req.ID, entity.ParseJobRunning, count, nil)
+	}
+
+	n.report(ctx, req.ID, entity.ParseJobDone, count, nil)
+	return count, nil
+}
+
+// search parses page of search results and produces news within date range of request.
+// Empty next page is returned if page has no news within range.
+func (n *news) search(ctx context.Context, req *message.ParseRequest, page string) (int, string, error) {
+	results, nextPage, err := n.Parser.Parse(ctx, req.Query, page)
+	if err != nil {
+		return 0, "", fmt.Errorf("n.Parser.Parse: %w", err)
+	}
+
+	// date to is inclusive
+	var dateTo time.Time
+	if req.DateTo != nil {
+		dateTo = req.DateTo.AddDate(0, 0, 1)
+	}
+
+	filtered := make([]entity.News, 0, len(results))
+	for _, result := range results {
+		if req.DateFrom != nil && result.PublishedAt.Before(*req.DateFrom) {
+			continue
+		}
+
+		if req.DateTo != nil && !result.PublishedAt.Before(dateTo) {
+			continue
+		}
+
+		filtered = append(filtered, result)
+	}
+
+	if len(filtered) == 0 && (req.DateFrom != nil || req.DateTo != nil) {
+		nextPage = ""
+	}
+
+	count, err := n.produceMany(ctx, filtered, req.ID)
+	return count, nextPage, err
+}
+
+// report publishes progress of request to aggregator. Errors are logged only.
+func (n *news) report(ctx context.Context, requestID string, state entity.ParseJobState, count int, reason error) {
+	if requestID == "" {
+		return
+	}
+
+	progress := &message.ParseProgress{
+		RequestID: requestID,
+		Source:    n.AppID,
+		State:     state,
+		Count:     count,
+		Timestamp: time.Now().UTC(),
+	}
+
+	if reason != nil {
+		progress.Error = reason.Error()
+	}
+
+	body, err := message.EncodeParseProgress(progress)
+	if err == nil {
+		err = n.Producer.Produce(ctx, "", message.ProgressQueue, rabbitmq.Message{
+			AppId:         n.AppID,
+			CorrelationId: requestID,
+			ContentType:   codec.ContentTypeJSON,
+			Body:          body,
+		})
+	}
 
-	results, page, err := n.Parser.Parse(ctx, query, page)
 	if err != nil {
-		return count, "", fmt.Errorf("n.Parser.Parse: %w", err)
+		zerolog.Ctx(ctx).Warn().Err(err).Str("request", requestID).Msg("progress is not reported")
 	}
+}
 
+// produceMany produces valid news or stores them in buffer if producing fails.
+func (n *news) produceMany(ctx context.Context, results []entity.News, requestID string) (int, error) {
+	count := 0
 	for _, result := range results {
-		body, err := json.Marshal(result)
+		env := message.New(result, n.Version)
+		env.RequestID = requestID
+		body, err := message.Encode(env)
+		var validationErr *message.ValidationError
+		if errors.As(err, &validationErr) {
+			zerolog.Ctx(ctx).Warn().Err(err).Str("link", result.Link).Msg("invalid news")
+			continue
+		}
+
 		if err != nil {
-			return count, "", fmt.Errorf("json.Marshal: %w", err)
+			return count, fmt.Errorf("message.Encode: %w", err)
 		}
 
-		if err := n.produce(ctx, body); err == nil {
+		if err := n.produce(ctx, env); err == nil {
 			count++
 			continue
 		}
 
+		// buffer stores JSON regardless of message encoding
 		if err := n.Repo.Create(ctx, string(body)); err != nil {
-			return count, "", fmt.Errorf("n.Repo.Create: %w", err)
+			return count, fmt.Errorf("n.Repo.Create: %w", err)
 		}
 		count++
 	}
 
-	return count, page, nil
+	return count, nil
 }
 
 func (n *news) Release(ctx context.Context) (int, error) {
@@ -76,12 +220,18 @@ func (n *news) Release(ctx context.Context) (int, error) {
 			return count, nil
 		}
 
-		err = n.produce(ctx, []byte(jsonStr))
-		if err != nil {
-			return count, err
-		}
+		// buffered messages may be stored by previous versions
+		if env, err := message.Decode([]byte(jsonStr)); err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Msg("invalid buffered news")
+		} else {
+			env.SchemaVersion = message.CurrentVersion
+			err = n.produce(ctx, env)
+			if err != nil {
+				return count, err
+			}
 
-		count++
+			count++
+		}
 
 		err = n.Repo.DeleteFirst(ctx)
 		if err != nil {
@@ -90,13 +240,20 @@ func (n *news) Release(ctx context.Context) (int, error) {
 	}
 }
 
-func (n *news) produce(ctx context.Context, body []byte) error {
-	err := n.Producer.Produce(ctx, n.Exchange, n.RoutingKey, rabbitmq.Message{
-		AppId:        n.AppID,
-		MessageId:    uuid.NewString(),
-		ContentType:  "application/json",
-		DeliveryMode: 2,
-		Body:         body,
+func (n *news) produce(ctx context.Context, env *message.Envelope) error {
+	body, err := message.Marshal(env, n.ContentType, n.ContentEncoding)
+	if err != nil {
+		return fmt.Errorf("message.Marshal: %w", err)
+	}
+
+	err = n.Producer.Produce(ctx, n.Exchange, n.RoutingKey, rabbitmq.Message{
+		AppId:           n.AppID,
+		MessageId:       uuid.NewString(),
+		CorrelationId:   env.RequestID,
+		ContentType:     n.ContentType,
+		ContentEncoding: n.ContentEncoding,
+		DeliveryMode:    2,
+		Body:            body,
 	})
 
 	if err != nil {
@@ -31,8 +31,9 @@ func (p *page) Get(ctx context.Context) (string, error) {
 
 }
 
-func (p *page) Set(ctx context.Context, page string) error {
-	err := p.Repo.Update(ctx, page)
+// Set updates page unless it is updated by holder of newer lease.
+func (p *page) Set(ctx context.Context, page string, token int64) error {
+	err := p.Repo.Update(ctx, page, token)
 	if err != nil {
 		return fmt.Errorf("p.Repo.Page.Update: %w", err)
 	}
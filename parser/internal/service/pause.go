@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qsoulior/news/parser/internal/repo"
+)
+
+type pause struct {
+	PauseConfig
+}
+
+type PauseConfig struct {
+	Repo repo.Pause
+}
+
+// NewPause returns pause state of workers shared by replicas,
+// so worker paused on one replica is paused on replica holding its lease.
+func NewPause(cfg PauseConfig) *pause {
+	return &pause{
+		PauseConfig: cfg,
+	}
+}
+
+func (p *pause) Get(ctx context.Context, name string) (bool, error) {
+	paused, err := p.Repo.Get(ctx, name)
+	if err != nil {
+		return false, fmt.Errorf("p.Repo.Get: %w", err)
+	}
+
+	return paused, nil
+}
+
+func (p *pause) Set(ctx context.Context, name string, paused bool) error {
+	err := p.Repo.Set(ctx, name, paused)
+	if err != nil {
+		return fmt.Errorf("p.Repo.Set: %w", err)
+	}
+
+	return nil
+}
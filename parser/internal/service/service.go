@@ -1,13 +1,47 @@
 package service
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/qsoulior/news/aggregator/pkg/message"
+	"github.com/qsoulior/news/parser/pkg/shard"
+)
 
 type News interface {
 	Parse(ctx context.Context, query string, page string) (int, string, error)
+	Search(ctx context.Context, req *message.ParseRequest) (int, error)
 	Release(ctx context.Context) (int, error)
 }
 
+type Buffer interface {
+	List(ctx context.Context, limit int) ([]string, int, error)
+	Flush(ctx context.Context) (int, error)
+}
+
 type Page interface {
 	Get(ctx context.Context) (string, error)
-	Set(ctx context.Context, page string) error
+	Set(ctx context.Context, page string, token int64) error
+}
+
+type Shard interface {
+	Register(ctx context.Context, shards []shard.Shard) (int, error)
+	Claim(ctx context.Context) (*shard.Shard, error)
+	Progress(ctx context.Context, id string, page string, count int) error
+	Complete(ctx context.Context, id string) error
+	Fail(ctx context.Context, id string, reason error) error
+	List(ctx context.Context) ([]shard.Shard, error)
+}
+
+type Lease interface {
+	Acquire(ctx context.Context, name string) (int64, bool, error)
+	Release(ctx context.Context, name string) error
+	Token(ctx context.Context, name string) (int64, error)
+	TTL() time.Duration
+	Holder() string
+}
+
+type Pause interface {
+	Get(ctx context.Context, name string) (bool, error)
+	Set(ctx context.Context, name string, paused bool) error
 }
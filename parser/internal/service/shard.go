@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/qsoulior/news/parser/internal/repo"
+	"github.com/qsoulior/news/parser/pkg/shard"
+)
+
+type shardService struct {
+	ShardConfig
+}
+
+type ShardConfig struct {
+	Repo repo.Shard
+	// Owner identifies replica.
+	Owner string
+	// TTL is time after which shard without progress is stolen.
+	TTL time.Duration
+	// MaxAttempts is number of attempts before shard is failed.
+	MaxAttempts int
+}
+
+const (
+	DefaultShardTTL         = 5 * time.Minute
+	DefaultShardMaxAttempts = 5
+)
+
+func NewShard(cfg ShardConfig) *shardService {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultShardTTL
+	}
+
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultShardMaxAttempts
+	}
+
+	return &shardService{
+		ShardConfig: cfg,
+	}
+}
+
+func (s *shardService) Register(ctx context.Context, shards []shard.Shard) (int, error) {
+	count, err := s.Repo.Register(ctx, shards)
+	if err != nil {
+		return count, fmt.Errorf("s.Repo.Register: %w", err)
+	}
+
+	return count, nil
+}
+
+// Claim returns shard claimed by replica or nil if there are no shards to claim.
+func (s *shardService) Claim(ctx context.Context) (*shard.Shard, error) {
+	item, err := s.Repo.Claim(ctx, s.Owner, s.TTL, s.MaxAttempts)
+	if errors.Is(err, repo.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("s.Repo.Claim: %w", err)
+	}
+
+	return item, nil
+}
+
+func (s *shardService) Progress(ctx context.Context, id string, page string, count int) error {
+	err := s.Repo.Progress(ctx, id, s.Owner, page, count, s.TTL)
+	if err != nil {
+		return fmt.Errorf("s.Repo.Progress: %w", err)
+	}
+
+	return nil
+}
+
+func (s *shardService) Complete(ctx context.Context, id string) error {
+	err := s.Repo.Complete(ctx, id, s.Owner)
+	if err != nil {
+		return fmt.Errorf("s.Repo.Complete: %w", err)
+	}
+
+	return nil
+}
+
+func (s *shardService) Fail(ctx context.Context, id string, reason error) error {
+	err := s.Repo.Fail(ctx, id, s.Owner, s.MaxAttempts, reason.Error())
+	if err != nil {
+		return fmt.Errorf("s.Repo.Fail: %w", err)
+	}
+
+	return nil
+}
+
+func (s *shardService) List(ctx context.Context) ([]shard.Shard, error) {
+	shards, err := s.Repo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("s.Repo.GetAll: %w", err)
+	}
+
+	slices.SortFunc(shards, func(a, b shard.Shard) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return shards, nil
+}
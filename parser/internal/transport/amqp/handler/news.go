@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 
+	"github.com/qsoulior/news/aggregator/pkg/message"
 	"github.com/qsoulior/news/aggregator/pkg/rabbitmq"
 	"github.com/qsoulior/news/parser/internal/service"
 	"github.com/rs/zerolog"
@@ -11,6 +12,7 @@ import (
 type NewsConfig struct {
 	Logger  *zerolog.Logger
 	Service service.News
+	AppID   string
 }
 
 type news struct {
@@ -22,10 +24,26 @@ func NewNews(cfg NewsConfig) *news {
 }
 
 func (n *news) Handle(ctx context.Context, msg *rabbitmq.Delivery) {
-	count, _, err := n.Service.Parse(ctx, string(msg.Body), "")
+	// current aggregators send same request to topic exchange,
+	// only plain queries of previous release are parsed from legacy one
+	if msg.Exchange == message.LegacyParseExchange && msg.MessageId != "" {
+		return
+	}
+
+	req, err := message.DecodeParseRequest(msg.Body, msg.ContentType, msg.MessageId)
+	if err != nil {
+		n.Logger.Warn().Err(err).Str("id", msg.MessageId).Msg("invalid request")
+		return
+	}
+
+	if !req.IsTarget(n.AppID) {
+		return
+	}
+
+	count, err := n.Service.Search(ctx, req)
 	if err != nil {
-		n.Logger.Error().Err(err).Int("count", count).Send()
+		n.Logger.Error().Err(err).Str("request", req.ID).Int("count", count).Send()
 		return
 	}
-	n.Logger.Info().Int("count", count).Msg("parsed")
+	n.Logger.Info().Str("request", req.ID).Int("count", count).Msg("parsed")
 }
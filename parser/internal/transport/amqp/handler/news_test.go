@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qsoulior/news/aggregator/pkg/message"
+	"github.com/qsoulior/news/aggregator/pkg/rabbitmq"
+	"github.com/rs/zerolog"
+)
+
+// searches records requests passed to service.
+type searches struct {
+	requests []*message.ParseRequest
+}
+
+func (s *searches) Parse(ctx context.Context, query string, page string) (int, string, error) {
+	return 0, "", nil
+}
+
+func (s *searches) Search(ctx context.Context, req *message.ParseRequest) (int, error) {
+	s.requests = append(s.requests, req)
+	return 0, nil
+}
+
+func (s *searches) Release(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func TestNewsHandleLegacy(t *testing.T) {
+	tests := []struct {
+		name  string
+		msg   rabbitmq.Delivery
+		query string
+	}{
+		{
+			name: "topic",
+			msg: rabbitmq.Delivery{
+				Exchange:    message.ParseExchange,
+				ContentType: "application/json",
+				MessageId:   "1",
+				Body:        []byte(`{"id":"1","query":"выборы","created_at":"2024-01-02T00:00:00Z"}`),
+			},
+			query: "выборы",
+		},
+		{
+			name: "legacy of previous aggregator",
+			msg: rabbitmq.Delivery{
+				Exchange:    message.LegacyParseExchange,
+				ContentType: "text/plain",
+				Body:        []byte("выборы"),
+			},
+			query: "выборы",
+		},
+		{
+			// same request is delivered by topic exchange
+			name: "legacy of current aggregator",
+			msg: rabbitmq.Delivery{
+				Exchange:    message.LegacyParseExchange,
+				ContentType: "text/plain",
+				MessageId:   "1",
+				Body:        []byte("выборы"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zerolog.Nop()
+			service := &searches{}
+			handler := NewNews(NewsConfig{Logger: &logger, Service: service, AppID: "ria"})
+
+			handler.Handle(context.Background(), &tt.msg)
+
+			if tt.query == "" {
+				if len(service.requests) != 0 {
+					t.Errorf("requests = %d, want 0", len(service.requests))
+				}
+				return
+			}
+
+			if len(service.requests) != 1 || service.requests[0].Query != tt.query {
+				t.Errorf("requests = %+v, want query %q", service.requests, tt.query)
+			}
+		})
+	}
+}
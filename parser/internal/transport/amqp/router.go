@@ -14,10 +14,11 @@ type Config struct {
 	NewsService service.News
 }
 
-func NewRouter(logger *zerolog.Logger, service service.News) rabbitmq.Handler {
+func NewRouter(logger *zerolog.Logger, service service.News, appID string) rabbitmq.Handler {
 	news := handler.NewNews(handler.NewsConfig{
 		Logger:  logger,
 		Service: service,
+		AppID:   appID,
 	})
 
 	return func(ctx context.Context, msg *rabbitmq.Delivery) {
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/qsoulior/news/aggregator/pkg/httpserver"
+	"github.com/qsoulior/news/parser/internal/service"
+	"github.com/rs/zerolog"
+)
+
+type bufferHandler struct {
+	service service.Buffer
+	news    service.News
+}
+
+func NewBuffer(service service.Buffer, news service.News) *bufferHandler {
+	return &bufferHandler{service, news}
+}
+
+const (
+	BUFFER_LIMIT     = 20
+	BUFFER_MAX_LIMIT = 1000
+)
+
+type BufferResponse struct {
+	Results []json.RawMessage `json:"results"`
+	Count   int               `json:"count"`
+	Length  int               `json:"length"`
+}
+
+type BufferCountResponse struct {
+	Count int `json:"count"`
+}
+
+func (h *bufferHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit := BUFFER_LIMIT
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			httpserver.ErrorJSON(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = min(limit, BUFFER_MAX_LIMIT)
+	}
+
+	items, length, err := h.service.List(r.Context(), limit)
+	if err != nil {
+		httpserver.ErrorJSON(w, "unexpected error while receiving data", http.StatusInternalServerError)
+		zerolog.Ctx(r.Context()).Error().Err(err).Send()
+		return
+	}
+
+	results := make([]json.RawMessage, len(items))
+	for i, item := range items {
+		// buffered news is encoded with configured content type
+		if json.Valid([]byte(item)) {
+			results[i] = json.RawMessage(item)
+		} else {
+			results[i], _ = json.Marshal(item)
+		}
+	}
+
+	httpserver.EncodeJSON(w, &BufferResponse{
+		Results: results,
+		Count:   len(results),
+		Length:  length,
+	}, http.StatusOK)
+}
+
+func (h *bufferHandler) Flush(w http.ResponseWriter, r *http.Request) {
+	count, err := h.service.Flush(r.Context())
+	if err != nil {
+		httpserver.ErrorJSON(w, "unexpected error while deleting data", http.StatusInternalServerError)
+		zerolog.Ctx(r.Context()).Error().Err(err).Send()
+		return
+	}
+
+	httpserver.EncodeJSON(w, &BufferCountResponse{count}, http.StatusOK)
+}
+
+// Replay releases buffered news to broker.
+func (h *bufferHandler) Replay(w http.ResponseWriter, r *http.Request) {
+	count, err := h.news.Release(r.Context())
+	if err != nil {
+		httpserver.ErrorJSON(w, "unexpected error while releasing data", http.StatusInternalServerError)
+		zerolog.Ctx(r.Context()).Error().Err(err).Int("count", count).Send()
+		return
+	}
+
+	httpserver.EncodeJSON(w, &BufferCountResponse{count}, http.StatusOK)
+}
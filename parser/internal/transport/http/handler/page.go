@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/qsoulior/news/aggregator/pkg/httpserver"
+	"github.com/qsoulior/news/parser/internal/service"
+	"github.com/qsoulior/news/parser/internal/worker"
+	"github.com/rs/zerolog"
+)
+
+type pageHandler struct {
+	service service.Page
+	lease   service.Lease
+}
+
+func NewPage(service service.Page, lease service.Lease) *pageHandler {
+	return &pageHandler{service, lease}
+}
+
+type Page struct {
+	Page string `json:"page"`
+}
+
+func (h *pageHandler) Get(w http.ResponseWriter, r *http.Request) {
+	page, err := h.service.Get(r.Context())
+	if errors.Is(err, service.ErrNotExist) {
+		httpserver.ErrorJSON(w, "page does not exist", http.StatusNotFound)
+		return
+	}
+
+	if err != nil {
+		httpserver.ErrorJSON(w, "unexpected error while receiving data", http.StatusInternalServerError)
+		zerolog.Ctx(r.Context()).Error().Err(err).Send()
+		return
+	}
+
+	httpserver.EncodeJSON(w, &Page{page}, http.StatusOK)
+}
+
+// Set moves archive cursor, archive worker picks it up before next run.
+// Cursor is set with fencing token of current archive lease, so it is not
+// overwritten by stale holder and is rejected if lease is taken meanwhile.
+func (h *pageHandler) Set(w http.ResponseWriter, r *http.Request) {
+	body, err := httpserver.DecodeJSON[Page](r)
+	if err != nil {
+		httpserver.ErrorJSON(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.lease.Token(r.Context(), worker.ArchiveLease)
+	if err != nil {
+		httpserver.ErrorJSON(w, "unexpected error while receiving data", http.StatusInternalServerError)
+		zerolog.Ctx(r.Context()).Error().Err(err).Send()
+		return
+	}
+
+	err = h.service.Set(r.Context(), body.Page, token)
+	if errors.Is(err, service.ErrFenced) {
+		httpserver.ErrorJSON(w, "archive lease is taken, try again", http.StatusConflict)
+		return
+	}
+
+	if err != nil {
+		httpserver.ErrorJSON(w, "unexpected error while updating data", http.StatusInternalServerError)
+		zerolog.Ctx(r.Context()).Error().Err(err).Send()
+		return
+	}
+
+	httpserver.EncodeJSON(w, body, http.StatusOK)
+}
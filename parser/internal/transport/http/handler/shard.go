@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/qsoulior/news/aggregator/pkg/httpserver"
+	"github.com/qsoulior/news/parser/internal/service"
+	"github.com/qsoulior/news/parser/pkg/shard"
+	"github.com/rs/zerolog"
+)
+
+type shardHandler struct {
+	service service.Shard
+}
+
+func NewShard(service service.Shard) *shardHandler {
+	return &shardHandler{service}
+}
+
+type ShardsResponse struct {
+	Results []shard.Shard       `json:"results"`
+	Count   int                 `json:"count"`
+	States  map[shard.State]int `json:"states"`
+}
+
+func (h *shardHandler) List(w http.ResponseWriter, r *http.Request) {
+	shards, err := h.service.List(r.Context())
+	if err != nil {
+		httpserver.ErrorJSON(w, "unexpected error while receiving data", http.StatusInternalServerError)
+		zerolog.Ctx(r.Context()).Error().Err(err).Send()
+		return
+	}
+
+	states := make(map[shard.State]int)
+	for _, item := range shards {
+		states[item.State]++
+	}
+
+	httpserver.EncodeJSON(w, &ShardsResponse{
+		Results: shards,
+		Count:   len(shards),
+		States:  states,
+	}, http.StatusOK)
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/qsoulior/news/aggregator/pkg/httpserver"
+	"github.com/qsoulior/news/parser/internal/worker"
+	"github.com/rs/zerolog"
+)
+
+type workerHandler struct {
+	workers []worker.Worker
+}
+
+func NewWorker(workers []worker.Worker) *workerHandler {
+	return &workerHandler{workers}
+}
+
+type WorkersResponse struct {
+	Results []worker.State `json:"results"`
+	Count   int            `json:"count"`
+}
+
+func (h *workerHandler) List(w http.ResponseWriter, r *http.Request) {
+	states := make([]worker.State, len(h.workers))
+	for i, item := range h.workers {
+		states[i] = item.State()
+	}
+
+	httpserver.EncodeJSON(w, &WorkersResponse{
+		Results: states,
+		Count:   len(states),
+	}, http.StatusOK)
+}
+
+func (h *workerHandler) find(name string) worker.Worker {
+	for _, item := range h.workers {
+		if item.State().Name == name {
+			return item
+		}
+	}
+
+	return nil
+}
+
+// control applies action to worker named in URL and responds with its state.
+func (h *workerHandler) control(w http.ResponseWriter, r *http.Request, action func(worker.Worker) error) {
+	item := h.find(chi.URLParam(r, "name"))
+	if item == nil {
+		httpserver.ErrorJSON(w, "worker does not exist", http.StatusNotFound)
+		return
+	}
+
+	if err := action(item); err != nil {
+		httpserver.ErrorJSON(w, "unexpected error while updating data", http.StatusInternalServerError)
+		zerolog.Ctx(r.Context()).Error().Err(err).Send()
+		return
+	}
+
+	httpserver.EncodeJSON(w, item.State(), http.StatusOK)
+}
+
+func (h *workerHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	h.control(w, r, func(item worker.Worker) error { return item.Pause(r.Context()) })
+}
+
+func (h *workerHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	h.control(w, r, func(item worker.Worker) error { return item.Resume(r.Context()) })
+}
+
+func (h *workerHandler) Trigger(w http.ResponseWriter, r *http.Request) {
+	h.control(w, r, func(item worker.Worker) error {
+		item.Trigger()
+		return nil
+	})
+}
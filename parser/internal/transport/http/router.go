@@ -0,0 +1,41 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/qsoulior/news/parser/internal/service"
+	"github.com/qsoulior/news/parser/internal/transport/http/handler"
+	"github.com/qsoulior/news/parser/internal/worker"
+)
+
+// NewRouter returns handler of admin API. Requests are authorized by bearer token
+// unless token is empty.
+func NewRouter(workers []worker.Worker, shards service.Shard, buffer service.Buffer, news service.News, page service.Page, lease service.Lease, token string) http.Handler {
+	mux := chi.NewMux()
+	mux.Use(middleware.Recoverer)
+	if token != "" {
+		mux.Use(handler.Auth(token))
+	}
+
+	workerHandler := handler.NewWorker(workers)
+	mux.Get("/workers", workerHandler.List)
+	mux.Post("/workers/{name}/pause", workerHandler.Pause)
+	mux.Post("/workers/{name}/resume", workerHandler.Resume)
+	mux.Post("/workers/{name}/trigger", workerHandler.Trigger)
+
+	shardHandler := handler.NewShard(shards)
+	mux.Get("/shards", shardHandler.List)
+
+	bufferHandler := handler.NewBuffer(buffer, news)
+	mux.Get("/buffer", bufferHandler.List)
+	mux.Delete("/buffer", bufferHandler.Flush)
+	mux.Post("/buffer/replay", bufferHandler.Replay)
+
+	pageHandler := handler.NewPage(page, lease)
+	mux.Get("/page", pageHandler.Get)
+	mux.Put("/page", pageHandler.Set)
+
+	return mux
+}
@@ -7,21 +7,21 @@ import (
 	"time"
 
 	"github.com/qsoulior/news/parser/internal/service"
+	"github.com/qsoulior/news/parser/pkg/scheduler"
 	"github.com/rs/zerolog"
 )
 
+// ArchiveLease is name of lease of archive worker, its fencing token guards archive cursor.
+const ArchiveLease = "archive"
+
 type archive struct {
 	*worker
 	news service.News
 	page service.Page
 }
 
-func NewArchive(delay time.Duration, logger *zerolog.Logger, news service.News, page service.Page) *archive {
-	worker := &worker{
-		delay:  delay,
-		logger: logger,
-	}
-
+func NewArchive(schedule *Schedule, pauses service.Pause, logger *zerolog.Logger, news service.News, page service.Page) *archive {
+	worker := newWorker("archive", schedule, pauses, logger)
 	return &archive{worker: worker, news: news, page: page}
 }
 
@@ -37,34 +37,41 @@ func (a *archive) Run(ctx context.Context) error {
 }
 
 func (a *archive) work(ctx context.Context, page string) {
-	var delay time.Duration = 0
-	timer := time.NewTimer(delay)
-	for {
-		select {
-		case <-ctx.Done():
-			timer.Stop()
-			return
-		case <-timer.C:
-			count, nextPage, err := a.news.Parse(ctx, "", page)
-			if err == nil {
-				err = a.page.Set(ctx, nextPage)
-				if err != nil {
-					a.logger.Error().Str("next_page", nextPage).Err(err).Send()
-				}
+	timer := time.NewTimer(a.first())
+	for a.wait(ctx, timer) {
+		// cursor may be moved through admin API
+		if current, err := a.page.Get(ctx); err == nil && current != page {
+			a.logger.Info().Str("page", current).Msg("page moved")
+			page = current
+		}
 
-				delay = a.delay
-				a.logger.Info().Int("count", count).Str("page", page).Str("next_page", nextPage).Dur("delay", delay).Msg("parsed")
-				page = nextPage
-			} else {
-				if delay > 0 {
-					delay *= 2
-				} else {
-					delay = a.delay
-				}
-				a.logger.Error().Int("count", count).Str("page", page).Err(err).Dur("delay", delay).Send()
+		count, nextPage, err := a.news.Parse(ctx, "", page)
+		if errors.Is(err, scheduler.ErrPreempted) {
+			// page is parsed again after search
+			a.logger.Debug().Str("page", page).Msg("preempted")
+			timer.Reset(0)
+			continue
+		}
+
+		delay := a.done(count, err)
+		if err == nil {
+			err = a.page.Set(ctx, nextPage, tokenFrom(ctx))
+			if errors.Is(err, service.ErrFenced) {
+				// archive is run by another replica
+				a.logger.Warn().Str("next_page", nextPage).Err(err).Send()
+				return
+			}
+
+			if err != nil {
+				a.logger.Error().Str("next_page", nextPage).Err(err).Send()
 			}
 
-			timer.Reset(delay)
+			a.logger.Info().Int("count", count).Str("page", page).Str("next_page", nextPage).Dur("delay", delay).Msg("parsed")
+			page = nextPage
+		} else {
+			a.logger.Error().Int("count", count).Str("page", page).Err(err).Dur("delay", delay).Send()
 		}
+
+		timer.Reset(delay)
 	}
 }
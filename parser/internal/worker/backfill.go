@@ -0,0 +1,119 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/qsoulior/news/parser/internal/service"
+	"github.com/qsoulior/news/parser/pkg/scheduler"
+	"github.com/qsoulior/news/parser/pkg/shard"
+	"github.com/rs/zerolog"
+)
+
+// backfill crawls archive shards claimed from queue shared by replicas.
+// News service is built for each claimed shard, so state of its parser
+// is not carried from one shard to another.
+type backfill struct {
+	*worker
+	newNews func() service.News
+	shards  service.Shard
+
+	news    service.News
+	current *shard.Shard
+}
+
+func NewBackfill(schedule *Schedule, pauses service.Pause, logger *zerolog.Logger, newNews func() service.News, shards service.Shard) *backfill {
+	worker := newWorker("backfill", schedule, pauses, logger)
+	return &backfill{worker: worker, newNews: newNews, shards: shards}
+}
+
+func (b *backfill) Run(ctx context.Context) error {
+	timer := time.NewTimer(b.first())
+	for b.wait(ctx, timer) {
+		count, err := b.step(ctx)
+		if errors.Is(err, scheduler.ErrPreempted) {
+			// page is parsed again after search
+			timer.Reset(0)
+			continue
+		}
+
+		timer.Reset(b.done(count, err))
+	}
+
+	return nil
+}
+
+// step parses next page of current shard claiming new shard if needed.
+func (b *backfill) step(ctx context.Context) (int, error) {
+	if b.current == nil {
+		current, err := b.shards.Claim(ctx)
+		if err != nil {
+			b.logger.Error().Err(err).Send()
+			return 0, err
+		}
+
+		b.setCurrent(current)
+		if current == nil {
+			return 0, nil
+		}
+		b.news = b.newNews()
+		b.logger.Info().Str("shard", current.ID).Str("page", current.Page).Int("attempts", current.Attempts).Msg("shard claimed")
+	}
+
+	current := b.current
+	count, nextPage, err := b.news.Parse(ctx, "", current.Page)
+	if errors.Is(err, scheduler.ErrPreempted) || ctx.Err() != nil {
+		return count, err
+	}
+
+	if err != nil {
+		b.logger.Error().Err(err).Str("shard", current.ID).Str("page", current.Page).Send()
+		if err := b.shards.Fail(ctx, current.ID, err); err != nil {
+			b.logger.Error().Err(err).Str("shard", current.ID).Send()
+		}
+		b.setCurrent(nil)
+		return count, err
+	}
+
+	finished := nextPage == "" || nextPage == current.To
+	err = b.shards.Progress(ctx, current.ID, nextPage, count)
+	if err == nil && finished {
+		err = b.shards.Complete(ctx, current.ID)
+	}
+
+	if errors.Is(err, service.ErrNotOwner) {
+		// shard is stolen by another replica
+		b.logger.Warn().Str("shard", current.ID).Msg("shard lost")
+		b.setCurrent(nil)
+		return count, nil
+	}
+
+	if err != nil {
+		b.logger.Error().Err(err).Str("shard", current.ID).Send()
+		return count, err
+	}
+
+	b.logger.Info().Int("count", count).Str("shard", current.ID).Str("page", current.Page).Str("next_page", nextPage).Msg("parsed")
+
+	if finished {
+		b.logger.Info().Str("shard", current.ID).Msg("shard completed")
+		b.setCurrent(nil)
+		return count, nil
+	}
+
+	current.Page = nextPage
+	return count, nil
+}
+
+func (b *backfill) setCurrent(current *shard.Shard) {
+	b.current = current
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state.Shard = ""
+	if current != nil {
+		b.state.Shard = current.ID
+	}
+}
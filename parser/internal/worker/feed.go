@@ -13,37 +13,24 @@ type feed struct {
 	news service.News
 }
 
-func NewFeed(delay time.Duration, logger *zerolog.Logger, news service.News) *feed {
-	worker := &worker{
-		delay:  delay,
-		logger: logger,
-	}
+func NewFeed(schedule *Schedule, pauses service.Pause, logger *zerolog.Logger, news service.News) *feed {
+	worker := newWorker("feed", schedule, pauses, logger)
 	return &feed{worker: worker, news: news}
 }
 
 func (f *feed) Run(ctx context.Context) error {
-	var delay time.Duration = 0
-	timer := time.NewTimer(delay)
-	for {
-		select {
-		case <-ctx.Done():
-			timer.Stop()
-			return nil
-		case <-timer.C:
-			count, _, err := f.news.Parse(ctx, "", "")
-			if err == nil {
-				delay = f.delay
-				f.logger.Info().Int("count", count).Dur("delay", delay).Msg("parsed")
-			} else {
-				if delay > 0 {
-					delay *= 2
-				} else {
-					delay = f.delay
-				}
-				f.logger.Error().Err(err).Int("count", count).Dur("delay", delay).Send()
-			}
-
-			timer.Reset(delay)
+	timer := time.NewTimer(f.first())
+	for f.wait(ctx, timer) {
+		count, _, err := f.news.Parse(ctx, "", "")
+		delay := f.done(count, err)
+		if err == nil {
+			f.logger.Info().Int("count", count).Dur("delay", delay).Msg("parsed")
+		} else {
+			f.logger.Error().Err(err).Int("count", count).Dur("delay", delay).Send()
 		}
+
+		timer.Reset(delay)
 	}
+
+	return nil
 }
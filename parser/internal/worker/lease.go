@@ -0,0 +1,141 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/qsoulior/news/parser/internal/service"
+	"github.com/rs/zerolog"
+)
+
+type tokenKey struct{}
+
+// tokenFrom returns fencing token of lease held by worker or zero.
+func tokenFrom(ctx context.Context) int64 {
+	token, _ := ctx.Value(tokenKey{}).(int64)
+	return token
+}
+
+// leased runs worker only while replica holds its lease.
+// Worker is stopped if lease is lost and started again on failover.
+type leased struct {
+	Worker
+	name   string
+	lease  service.Lease
+	logger *zerolog.Logger
+
+	mu     sync.RWMutex
+	leader bool
+	token  int64
+}
+
+func NewLeased(worker Worker, name string, lease service.Lease, logger *zerolog.Logger) *leased {
+	return &leased{
+		Worker: worker,
+		name:   name,
+		lease:  lease,
+		logger: logger,
+	}
+}
+
+func (l *leased) State() State {
+	state := l.Worker.State()
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	leader := l.leader
+	state.Leader = &leader
+	state.Token = l.token
+	return state
+}
+
+func (l *leased) Run(ctx context.Context) error {
+	interval := l.lease.TTL() / 3
+
+	timer := time.NewTimer(0)
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+			token, ok, err := l.lease.Acquire(ctx, l.name)
+			if err != nil {
+				l.logger.Error().Err(err).Str("lease", l.name).Send()
+			}
+
+			if ok {
+				if err := l.lead(ctx, token, interval); err != nil {
+					return err
+				}
+			}
+
+			timer.Reset(interval)
+		}
+	}
+}
+
+// lead runs worker and renews lease until lease is lost or context is done.
+func (l *leased) lead(ctx context.Context, token int64, interval time.Duration) error {
+	l.setLeader(true, token)
+	defer l.setLeader(false, 0)
+	l.logger.Info().Str("lease", l.name).Int64("token", token).Msg("lease acquired")
+
+	runCtx, cancel := context.WithCancel(context.WithValue(ctx, tokenKey{}, token))
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- l.Worker.Run(runCtx)
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// lease expires unless it is renewed in time
+	renewed := time.Now()
+	for {
+		select {
+		case err := <-done:
+			l.release()
+			return err
+		case <-ctx.Done():
+			err := <-done
+			l.release()
+			return err
+		case <-ticker.C:
+			renewedToken, ok, err := l.lease.Acquire(ctx, l.name)
+			if err != nil {
+				l.logger.Error().Err(err).Str("lease", l.name).Send()
+				if time.Since(renewed) < l.lease.TTL()-interval {
+					continue
+				}
+			} else if ok && renewedToken == token {
+				renewed = time.Now()
+				continue
+			}
+
+			l.logger.Warn().Str("lease", l.name).Int64("token", token).Msg("lease lost")
+			cancel()
+			return <-done
+		}
+	}
+}
+
+func (l *leased) release() {
+	// lease is released after term signal too
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := l.lease.Release(ctx, l.name); err != nil {
+		l.logger.Error().Err(err).Str("lease", l.name).Msg("lease is not released")
+	}
+}
+
+func (l *leased) setLeader(leader bool, token int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.leader, l.token = leader, token
+}
@@ -13,28 +13,22 @@ type release struct {
 	news service.News
 }
 
-func NewRelease(delay time.Duration, logger *zerolog.Logger, news service.News) *release {
-	worker := &worker{
-		delay:  delay,
-		logger: logger,
-	}
+func NewRelease(schedule *Schedule, pauses service.Pause, logger *zerolog.Logger, news service.News) *release {
+	worker := newWorker("release", schedule, pauses, logger)
 	return &release{worker: worker, news: news}
 }
 
 func (r *release) Run(ctx context.Context) error {
-	timer := time.NewTimer(0)
-	for {
-		select {
-		case <-ctx.Done():
-			timer.Stop()
-			return nil
-		case <-timer.C:
-			count, err := r.news.Release(ctx)
-			if err != nil {
-				r.logger.Error().Err(err).Int("count", count).Send()
-			}
-			r.logger.Info().Dur("delay", r.delay).Int("count", count).Msg("released")
-			timer.Reset(r.delay)
+	timer := time.NewTimer(r.first())
+	for r.wait(ctx, timer) {
+		count, err := r.news.Release(ctx)
+		delay := r.done(count, err)
+		if err != nil {
+			r.logger.Error().Err(err).Int("count", count).Send()
 		}
+		r.logger.Info().Dur("delay", delay).Int("count", count).Msg("released")
+		timer.Reset(delay)
 	}
+
+	return nil
 }
@@ -0,0 +1,188 @@
+package worker
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+type ScheduleConfig struct {
+	// Delay is time between runs.
+	Delay time.Duration
+	// MinDelay and MaxDelay bound delay adapted to number of parsed news.
+	// Delay is not adapted if they are equal.
+	MinDelay time.Duration
+	MaxDelay time.Duration
+	// MaxBackoff is ceiling of delay after failed runs.
+	MaxBackoff time.Duration
+	// Cron is standard cron expression, runs follow it instead of delay if set.
+	Cron string
+	// QuietHours are ranges of time "15:04-15:04" without runs.
+	QuietHours []string
+	// Location is time zone of cron expression and quiet hours, UTC if nil.
+	Location *time.Location
+}
+
+const DefaultMaxBackoff = 30 * time.Minute
+
+type quietRange struct {
+	from, to time.Duration
+}
+
+// contains reports whether time of day is within range. Range may cross midnight.
+func (q quietRange) contains(t time.Duration) bool {
+	if q.from <= q.to {
+		return t >= q.from && t < q.to
+	}
+	return t >= q.from || t < q.to
+}
+
+// Schedule computes time of next run from results of previous runs.
+type Schedule struct {
+	ScheduleConfig
+	cron     cron.Schedule
+	quiet    []quietRange
+	delay    time.Duration
+	failures int
+}
+
+func NewSchedule(cfg ScheduleConfig) (*Schedule, error) {
+	if cfg.MinDelay <= 0 || cfg.MinDelay > cfg.Delay {
+		cfg.MinDelay = cfg.Delay
+	}
+
+	if cfg.MaxDelay < cfg.Delay {
+		cfg.MaxDelay = cfg.Delay
+	}
+
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = max(DefaultMaxBackoff, cfg.MaxDelay)
+	}
+
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+
+	s := &Schedule{ScheduleConfig: cfg, delay: cfg.Delay}
+
+	if cfg.Cron != "" {
+		schedule, err := cron.ParseStandard(cfg.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("cron.ParseStandard: %w", err)
+		}
+		s.cron = schedule
+	}
+
+	for _, value := range cfg.QuietHours {
+		from, to, ok := strings.Cut(value, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid quiet hours %q", value)
+		}
+
+		fromTime, err := time.Parse("15:04", strings.TrimSpace(from))
+		if err != nil {
+			return nil, fmt.Errorf("time.Parse: %w", err)
+		}
+
+		toTime, err := time.Parse("15:04", strings.TrimSpace(to))
+		if err != nil {
+			return nil, fmt.Errorf("time.Parse: %w", err)
+		}
+
+		s.quiet = append(s.quiet, quietRange{timeOfDay(fromTime), timeOfDay(toTime)})
+	}
+
+	return s, nil
+}
+
+func timeOfDay(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+}
+
+// First returns time of first run.
+func (s *Schedule) First(now time.Time) time.Time {
+	if s.cron != nil {
+		return s.skipQuiet(s.cron.Next(now.In(s.Location)))
+	}
+	return s.skipQuiet(now)
+}
+
+// Next returns time of next run after run finished at given time.
+// Delay grows exponentially with jitter after failed runs, it is adapted
+// to number of parsed news after successful runs.
+func (s *Schedule) Next(now time.Time, count int, err error) time.Time {
+	if err != nil {
+		s.failures++
+		backoff := min(s.delay<<min(s.failures-1, 16), s.MaxBackoff)
+
+		// jitter within ±10% keeps failed workers apart at ceiling too
+		backoff += time.Duration((rand.Float64()*0.2 - 0.1) * float64(backoff))
+		return s.skipQuiet(now.Add(backoff))
+	}
+	s.failures = 0
+
+	if s.cron != nil {
+		return s.skipQuiet(s.cron.Next(now.In(s.Location)))
+	}
+
+	// new news shorten delay, idle runs lengthen it
+	if count > 0 {
+		s.delay = max(s.delay/2, s.MinDelay)
+	} else {
+		s.delay = min(s.delay*3/2, s.MaxDelay)
+	}
+
+	return s.skipQuiet(now.Add(s.delay))
+}
+
+// Delay returns current delay between runs.
+func (s *Schedule) Delay() time.Duration {
+	return s.delay
+}
+
+// Failures returns number of consecutive failed runs.
+func (s *Schedule) Failures() int {
+	return s.failures
+}
+
+// Quiet reports whether time is within quiet hours.
+func (s *Schedule) Quiet(t time.Time) bool {
+	day := timeOfDay(t.In(s.Location))
+	for _, q := range s.quiet {
+		if q.contains(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// skipQuiet moves time to end of quiet hours.
+func (s *Schedule) skipQuiet(t time.Time) time.Time {
+	// ranges may adjoin each other
+	for i := 0; i <= len(s.quiet); i++ {
+		moved := false
+		day := timeOfDay(t.In(s.Location))
+		for _, q := range s.quiet {
+			if !q.contains(day) {
+				continue
+			}
+
+			wait := q.to - day
+			if wait <= 0 {
+				wait += 24 * time.Hour
+			}
+			t = t.Add(wait).Truncate(time.Minute)
+			moved = true
+			break
+		}
+
+		if !moved {
+			break
+		}
+	}
+
+	return t
+}
@@ -0,0 +1,147 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/qsoulior/news/parser/pkg/dateparse"
+)
+
+func TestScheduleBackoff(t *testing.T) {
+	schedule, err := NewSchedule(ScheduleConfig{Delay: time.Minute, MaxBackoff: 10 * time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	failed := errors.New("failed")
+
+	tests := []struct {
+		base time.Duration
+	}{
+		{time.Minute},
+		{2 * time.Minute},
+		{4 * time.Minute},
+		{8 * time.Minute},
+		// ceiling is jittered too
+		{10 * time.Minute},
+		{10 * time.Minute},
+	}
+
+	for i, tt := range tests {
+		delay := schedule.Next(now, 0, failed).Sub(now)
+		low, high := tt.base*9/10, tt.base*11/10
+		if delay < low || delay > high {
+			t.Errorf("delay after %d failures = %s, want in [%s, %s]", i+1, delay, low, high)
+		}
+	}
+
+	if schedule.Failures() != len(tests) {
+		t.Errorf("Failures = %d, want %d", schedule.Failures(), len(tests))
+	}
+}
+
+func TestScheduleBackoffJitter(t *testing.T) {
+	schedule, err := NewSchedule(ScheduleConfig{Delay: time.Hour, MaxBackoff: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	delays := make(map[time.Duration]struct{})
+	for range 20 {
+		delays[schedule.Next(now, 0, errors.New("failed")).Sub(now)] = struct{}{}
+	}
+
+	// workers failing together do not retry at the same time
+	if len(delays) == 1 {
+		t.Error("delays at ceiling are not jittered")
+	}
+}
+
+func TestScheduleQuietLocation(t *testing.T) {
+	moscow, err := dateparse.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		loc   *time.Location
+		now   time.Time
+		quiet bool
+		first time.Time
+	}{
+		{
+			name:  "utc",
+			now:   time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC),
+			quiet: false,
+			first: time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "utc quiet",
+			now:   time.Date(2024, 1, 2, 1, 0, 0, 0, time.UTC),
+			quiet: true,
+			first: time.Date(2024, 1, 2, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			// 02:00 in Moscow
+			name:  "moscow quiet",
+			loc:   moscow,
+			now:   time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC),
+			quiet: true,
+			first: time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			// 07:00 in Moscow
+			name:  "moscow",
+			loc:   moscow,
+			now:   time.Date(2024, 1, 2, 4, 0, 0, 0, time.UTC),
+			quiet: false,
+			first: time.Date(2024, 1, 2, 4, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := NewSchedule(ScheduleConfig{
+				Delay:      time.Minute,
+				QuietHours: []string{"00:00-06:00"},
+				Location:   tt.loc,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// zone of given time does not matter
+			for _, now := range []time.Time{tt.now, tt.now.In(moscow)} {
+				if quiet := schedule.Quiet(now); quiet != tt.quiet {
+					t.Errorf("Quiet(%s) = %v, want %v", now, quiet, tt.quiet)
+				}
+
+				if first := schedule.First(now); !first.Equal(tt.first) {
+					t.Errorf("First(%s) = %s, want %s", now, first, tt.first)
+				}
+			}
+		})
+	}
+}
+
+func TestScheduleCronLocation(t *testing.T) {
+	moscow, err := dateparse.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schedule, err := NewSchedule(ScheduleConfig{Delay: time.Minute, Cron: "0 9 * * *", Location: moscow})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 1, 1, 5, 0, 0, 0, time.UTC)
+	want := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)
+	if first := schedule.First(now); !first.Equal(want) {
+		t.Errorf("First = %s, want %s", first, want)
+	}
+}
@@ -2,16 +2,210 @@ package worker
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
+	"github.com/qsoulior/news/parser/internal/service"
 	"github.com/rs/zerolog"
 )
 
 type Worker interface {
 	Run(ctx context.Context) error
+	State() State
+	Pause(ctx context.Context) error
+	Resume(ctx context.Context) error
+	Trigger()
+}
+
+// PausePollInterval is interval of checking whether paused worker is resumed
+// on another replica.
+const PausePollInterval = 10 * time.Second
+
+// State is schedule state of worker.
+type State struct {
+	Name      string     `json:"name"`
+	Delay     string     `json:"delay"`
+	Cron      string     `json:"cron,omitempty"`
+	Quiet     bool       `json:"quiet"`
+	NextRun   time.Time  `json:"next_run"`
+	LastRun   *time.Time `json:"last_run,omitempty"`
+	LastCount int        `json:"last_count"`
+	LastError string     `json:"last_error,omitempty"`
+	Failures  int        `json:"failures"`
+	Leader    *bool      `json:"leader,omitempty"`
+	Token     int64      `json:"token,omitempty"`
+	Shard     string     `json:"shard,omitempty"`
+	Paused    bool       `json:"paused"`
 }
 
 type worker struct {
-	delay  time.Duration
-	logger *zerolog.Logger
+	name     string
+	schedule *Schedule
+	pauses   service.Pause
+	logger   *zerolog.Logger
+
+	trigger chan struct{}
+	resume  chan struct{}
+
+	mu    sync.RWMutex
+	state State
+}
+
+func newWorker(name string, schedule *Schedule, pauses service.Pause, logger *zerolog.Logger) *worker {
+	return &worker{
+		name:     name,
+		schedule: schedule,
+		pauses:   pauses,
+		logger:   logger,
+		trigger:  make(chan struct{}, 1),
+		resume:   make(chan struct{}, 1),
+		state:    State{Name: name, Cron: schedule.Cron},
+	}
+}
+
+// Pause skips scheduled runs until worker is resumed. Triggered runs are not skipped.
+// Pause state is shared by replicas.
+func (w *worker) Pause(ctx context.Context) error {
+	if err := w.pauses.Set(ctx, w.name, true); err != nil {
+		return fmt.Errorf("w.pauses.Set: %w", err)
+	}
+
+	w.mu.Lock()
+	w.state.Paused = true
+	w.mu.Unlock()
+
+	// resume sent before pause does not cancel it
+	select {
+	case <-w.resume:
+	default:
+	}
+
+	return nil
+}
+
+func (w *worker) Resume(ctx context.Context) error {
+	if err := w.pauses.Set(ctx, w.name, false); err != nil {
+		return fmt.Errorf("w.pauses.Set: %w", err)
+	}
+
+	w.mu.Lock()
+	paused := w.state.Paused
+	w.state.Paused = false
+	w.mu.Unlock()
+
+	if !paused {
+		return nil
+	}
+
+	select {
+	case w.resume <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// Trigger runs worker immediately.
+func (w *worker) Trigger() {
+	select {
+	case w.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// paused loads pause state shared by replicas. Last known state is used
+// if it is not loaded.
+func (w *worker) paused(ctx context.Context) bool {
+	paused, err := w.pauses.Get(ctx, w.name)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err != nil {
+		w.logger.Error().Err(err).Msg("pause state is not loaded")
+		return w.state.Paused
+	}
+
+	w.state.Paused = paused
+	return paused
+}
+
+// wait blocks until scheduled run of unpaused worker or triggered run.
+// It reports false if context is done.
+func (w *worker) wait(ctx context.Context, timer *time.Timer) bool {
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return false
+	case <-w.trigger:
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		return true
+	case <-timer.C:
+	}
+
+	poll := time.NewTicker(PausePollInterval)
+	defer poll.Stop()
+
+	for w.paused(ctx) {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-w.trigger:
+			return true
+		case <-w.resume:
+		case <-poll.C:
+		}
+	}
+
+	return ctx.Err() == nil
+}
+
+func (w *worker) State() State {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	state := w.state
+	state.Quiet = w.schedule.Quiet(time.Now())
+	return state
+}
+
+// first returns delay before first run.
+func (w *worker) first() time.Duration {
+	now := time.Now()
+	next := w.schedule.First(now)
+
+	w.mu.Lock()
+	w.state.Delay = w.schedule.Delay().String()
+	w.state.NextRun = next
+	w.mu.Unlock()
+
+	return next.Sub(now)
+}
+
+// done records result of run and returns delay before next run.
+func (w *worker) done(count int, err error) time.Duration {
+	now := time.Now()
+	next := w.schedule.Next(now, count, err)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.state.LastRun = &now
+	w.state.LastCount = count
+	w.state.LastError = ""
+	if err != nil {
+		w.state.LastError = err.Error()
+	}
+
+	w.state.Delay = w.schedule.Delay().String()
+	w.state.NextRun = next
+	w.state.Failures = w.schedule.Failures()
+
+	return next.Sub(now)
 }
@@ -0,0 +1,139 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// pauses is pause state shared by replicas kept in memory.
+type pauses struct {
+	mu     sync.Mutex
+	paused map[string]bool
+}
+
+func (p *pauses) Get(ctx context.Context, name string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.paused[name], nil
+}
+
+func (p *pauses) Set(ctx context.Context, name string, paused bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused[name] = paused
+	return nil
+}
+
+func newTestWorker(t *testing.T, store *pauses) *worker {
+	t.Helper()
+	schedule, err := NewSchedule(ScheduleConfig{Delay: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := zerolog.Nop()
+	return newWorker("test", schedule, store, &logger)
+}
+
+// waitAsync runs wait with expired timer and returns channel of its result.
+func waitAsync(ctx context.Context, w *worker) <-chan bool {
+	ch := make(chan bool, 1)
+	go func() {
+		ch <- w.wait(ctx, time.NewTimer(0))
+	}()
+	return ch
+}
+
+func TestWaitPaused(t *testing.T) {
+	store := &pauses{paused: make(map[string]bool)}
+	w := newTestWorker(t, store)
+	ctx := context.Background()
+
+	if err := w.Pause(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	done := waitAsync(ctx, w)
+	select {
+	case <-done:
+		t.Fatal("paused worker runs")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if err := w.Resume(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case ok := <-done:
+		if !ok {
+			t.Fatal("resumed worker is stopped")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("resumed worker does not run")
+	}
+}
+
+func TestResumeBeforePause(t *testing.T) {
+	store := &pauses{paused: make(map[string]bool)}
+	w := newTestWorker(t, store)
+	ctx := context.Background()
+
+	// resume of running worker does not cancel next pause
+	if err := w.Resume(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Pause(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := waitAsync(ctx, w)
+	select {
+	case <-done:
+		t.Fatal("paused worker runs")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	if ok := <-done; ok {
+		t.Fatal("worker runs after context is done")
+	}
+}
+
+func TestPauseShared(t *testing.T) {
+	store := &pauses{paused: make(map[string]bool)}
+	w := newTestWorker(t, store)
+	other := newTestWorker(t, store)
+	ctx := context.Background()
+
+	// worker paused on another replica is paused after restart
+	if err := other.Pause(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	done := waitAsync(ctx, w)
+	select {
+	case <-done:
+		t.Fatal("worker paused on another replica runs")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if !w.State().Paused {
+		t.Error("state of worker paused on another replica is not paused")
+	}
+
+	w.Trigger()
+	select {
+	case ok := <-done:
+		if !ok {
+			t.Fatal("triggered worker is stopped")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("triggered worker does not run")
+	}
+}
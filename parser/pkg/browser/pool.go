@@ -0,0 +1,477 @@
+// Package browser manages browser shared by pages of parser.
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/stealth"
+	"github.com/rs/zerolog"
+)
+
+type Config struct {
+	// ControlURL is websocket URL of remote browser.
+	// Local browser is launched if it is empty.
+	ControlURL string
+	// Bin is path to executable of local browser, launcher looks it up if it is empty.
+	Bin string
+	// Headful shows window of local browser, browser is headless by default.
+	Headful bool
+	// MaxPages is maximum number of pages opened at once.
+	MaxPages int
+	// PageTimeout limits time of page usage.
+	PageTimeout time.Duration
+	// HealthInterval is time between health checks of browser.
+	HealthInterval time.Duration
+	// Block enables blocking of images, fonts, media and requests to BlockHosts.
+	Block bool
+	// BlockHosts are hosts of ads and trackers, their subdomains are blocked too.
+	BlockHosts []string
+}
+
+const (
+	DefaultMaxPages       = 4
+	DefaultPageTimeout    = 1 * time.Minute
+	DefaultHealthInterval = 30 * time.Second
+
+	healthTimeout = 5 * time.Second
+)
+
+var DefaultBlockHosts = []string{
+	"doubleclick.net",
+	"googlesyndication.com",
+	"googletagmanager.com",
+	"google-analytics.com",
+	"mc.yandex.ru",
+	"an.yandex.ru",
+	"adfox.ru",
+	"top-fwz1.mail.ru",
+	"counter.yadro.ru",
+	"smi2.ru",
+	"relap.io",
+}
+
+// blockedTypes are resources which are not needed to parse page.
+var blockedTypes = map[proto.NetworkResourceType]struct{}{
+	proto.NetworkResourceTypeImage: {},
+	proto.NetworkResourceTypeFont:  {},
+	proto.NetworkResourceTypeMedia: {},
+}
+
+var ErrClosed = errors.New("browser pool is closed")
+
+// Pool opens pages of browser. Browser is launched on demand
+// and relaunched if it fails health check. Remote browser is shared
+// with other clients, so pool only connects to it and disconnects.
+type Pool struct {
+	Config
+	logger *zerolog.Logger
+
+	pages chan struct{}
+	done  chan struct{}
+
+	mu       sync.Mutex
+	browser  *rod.Browser
+	conn     *cdp.WebSocket
+	launcher *launcher.Launcher
+	closed   bool
+}
+
+func New(cfg Config, logger *zerolog.Logger) *Pool {
+	if cfg.MaxPages <= 0 {
+		cfg.MaxPages = DefaultMaxPages
+	}
+
+	if cfg.PageTimeout <= 0 {
+		cfg.PageTimeout = DefaultPageTimeout
+	}
+
+	if cfg.HealthInterval <= 0 {
+		cfg.HealthInterval = DefaultHealthInterval
+	}
+
+	if cfg.BlockHosts == nil {
+		cfg.BlockHosts = DefaultBlockHosts
+	}
+
+	log := logger.With().Str("module", "browser").Logger()
+	p := &Pool{
+		Config: cfg,
+		logger: &log,
+		pages:  make(chan struct{}, cfg.MaxPages),
+		done:   make(chan struct{}),
+	}
+
+	go p.watch()
+	return p
+}
+
+// PageOptions make page look like HTTP client of parser.
+type PageOptions struct {
+	// Headers are identity headers of client. User-Agent and Accept-Language
+	// override ones of browser, Accept is not sent as it depends on resource.
+	Headers map[string]string
+	// Proxy is URL of proxy of page, page is opened in separate browser context
+	// if it is set. Credentials of proxy are sent if it requests authentication.
+	Proxy *url.URL
+}
+
+// Page opens stealth page bound to context and page timeout.
+// Release closes page and must be called when page is not needed.
+func (p *Pool) Page(ctx context.Context, opts PageOptions) (*rod.Page, func(), error) {
+	select {
+	case p.pages <- struct{}{}:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case <-p.done:
+		return nil, nil, ErrClosed
+	}
+
+	page, release, err := p.open(ctx, opts)
+	if err != nil {
+		<-p.pages
+		return nil, nil, err
+	}
+
+	return page, func() {
+		release()
+		<-p.pages
+	}, nil
+}
+
+func (p *Pool) open(ctx context.Context, opts PageOptions) (*rod.Page, func(), error) {
+	browser, err := p.get()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	target := browser
+	if opts.Proxy != nil {
+		target, err = incognito(browser, opts.Proxy)
+		if err != nil {
+			p.check(browser)
+			return nil, nil, err
+		}
+	}
+
+	// dispose closes browser context of proxy with its pages
+	dispose := func() {
+		if target == browser {
+			return
+		}
+
+		err := proto.TargetDisposeBrowserContext{BrowserContextID: target.BrowserContextID}.Call(browser)
+		if err != nil {
+			p.logger.Debug().Err(err).Msg("browser context is not disposed")
+		}
+	}
+
+	page, err := stealth.Page(target)
+	if err != nil {
+		dispose()
+		// page is not opened by crashed browser
+		p.check(browser)
+		return nil, nil, fmt.Errorf("stealth.Page: %w", err)
+	}
+
+	if err := identify(page, opts.Headers); err != nil {
+		page.Close()
+		dispose()
+		return nil, nil, err
+	}
+
+	stop := func() {}
+	if p.Block || opts.Proxy != nil && opts.Proxy.User != nil {
+		stop, err = p.intercept(page, opts.Proxy)
+		if err != nil {
+			page.Close()
+			dispose()
+			return nil, nil, err
+		}
+	}
+
+	pageCtx, cancel := context.WithTimeout(ctx, p.PageTimeout)
+	release := func() {
+		cancel()
+		stop()
+
+		if err := page.Close(); err != nil {
+			p.logger.Debug().Err(err).Msg("page is not closed")
+		}
+		dispose()
+	}
+
+	return page.Context(pageCtx), release, nil
+}
+
+// incognito returns browser context sending requests through proxy.
+func incognito(browser *rod.Browser, proxy *url.URL) (*rod.Browser, error) {
+	server := &url.URL{Scheme: proxy.Scheme, Host: proxy.Host}
+	res, err := proto.TargetCreateBrowserContext{ProxyServer: server.String()}.Call(browser)
+	if err != nil {
+		return nil, fmt.Errorf("proto.TargetCreateBrowserContext: %w", err)
+	}
+
+	target := *browser
+	target.BrowserContextID = res.BrowserContextID
+	return &target, nil
+}
+
+// identify sets identity headers of client to page.
+func identify(page *rod.Page, headers map[string]string) error {
+	if ua := headers["User-Agent"]; ua != "" {
+		err := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{
+			UserAgent:      ua,
+			AcceptLanguage: headers["Accept-Language"],
+		})
+		if err != nil {
+			return fmt.Errorf("page.SetUserAgent: %w", err)
+		}
+	}
+
+	dict := make([]string, 0, 2*len(headers))
+	for key, value := range headers {
+		switch key {
+		case "User-Agent", "Accept-Language", "Accept":
+		default:
+			dict = append(dict, key, value)
+		}
+	}
+
+	if len(dict) == 0 {
+		return nil
+	}
+
+	if _, err := page.SetExtraHeaders(dict); err != nil {
+		return fmt.Errorf("page.SetExtraHeaders: %w", err)
+	}
+
+	return nil
+}
+
+// intercept blocks requests if it is enabled and answers authentication
+// requests of proxy. It returns function stopping interception.
+func (p *Pool) intercept(page *rod.Page, proxy *url.URL) (func(), error) {
+	var auth *proto.FetchAuthChallengeResponse
+	if proxy != nil && proxy.User != nil {
+		password, _ := proxy.User.Password()
+		auth = &proto.FetchAuthChallengeResponse{
+			Response: proto.FetchAuthChallengeResponseResponseProvideCredentials,
+			Username: proxy.User.Username(),
+			Password: password,
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wait := page.Context(ctx).EachEvent(func(e *proto.FetchRequestPaused) {
+		go p.paused(page, e)
+	}, func(e *proto.FetchAuthRequired) {
+		go answer(page, e, auth)
+	})
+
+	err := proto.FetchEnable{HandleAuthRequests: auth != nil}.Call(page)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("proto.FetchEnable: %w", err)
+	}
+
+	go wait()
+	return cancel, nil
+}
+
+// paused fails blocked request and continues other ones.
+func (p *Pool) paused(page *rod.Page, e *proto.FetchRequestPaused) {
+	var err error
+	if p.blocked(e) {
+		err = proto.FetchFailRequest{RequestID: e.RequestID, ErrorReason: proto.NetworkErrorReasonBlockedByClient}.Call(page)
+	} else {
+		err = proto.FetchContinueRequest{RequestID: e.RequestID}.Call(page)
+	}
+
+	if err != nil {
+		p.logger.Debug().Err(err).Msg("request is not continued")
+	}
+}
+
+func (p *Pool) blocked(e *proto.FetchRequestPaused) bool {
+	if !p.Block {
+		return false
+	}
+
+	if _, ok := blockedTypes[e.ResourceType]; ok {
+		return true
+	}
+
+	u, err := url.Parse(e.Request.URL)
+	return err == nil && p.blockedHost(u.Hostname())
+}
+
+// answer sends credentials to proxy, authentication of sites is not answered.
+func answer(page *rod.Page, e *proto.FetchAuthRequired, auth *proto.FetchAuthChallengeResponse) {
+	response := &proto.FetchAuthChallengeResponse{Response: proto.FetchAuthChallengeResponseResponseDefault}
+	if auth != nil && e.AuthChallenge.Source == proto.FetchAuthChallengeSourceProxy {
+		response = auth
+	}
+
+	proto.FetchContinueWithAuth{RequestID: e.RequestID, AuthChallengeResponse: response}.Call(page)
+}
+
+func (p *Pool) blockedHost(hostname string) bool {
+	for _, host := range p.BlockHosts {
+		if hostname == host || strings.HasSuffix(hostname, "."+host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// get returns connected browser launching it if needed.
+func (p *Pool) get() (*rod.Browser, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrClosed
+	}
+
+	if p.browser != nil {
+		return p.browser, nil
+	}
+
+	controlURL := p.ControlURL
+	var l *launcher.Launcher
+	if controlURL == "" {
+		l = launcher.New().Headless(!p.Headful)
+		if p.Bin != "" {
+			l = l.Bin(p.Bin)
+		}
+
+		var err error
+		controlURL, err = l.Launch()
+		if err != nil {
+			l.Kill()
+			return nil, fmt.Errorf("l.Launch: %w", err)
+		}
+	}
+
+	// connection is kept to disconnect from remote browser without closing it
+	conn := &cdp.WebSocket{}
+	if err := conn.Connect(context.Background(), controlURL, nil); err != nil {
+		p.kill(l)
+		return nil, fmt.Errorf("conn.Connect: %w", err)
+	}
+
+	browser := rod.New().Client(cdp.New().Start(conn))
+	if err := browser.Connect(); err != nil {
+		conn.Close()
+		p.kill(l)
+		return nil, fmt.Errorf("browser.Connect: %w", err)
+	}
+
+	p.browser, p.conn, p.launcher = browser, conn, l
+	p.logger.Info().Str("url", controlURL).Msg("browser connected")
+	return browser, nil
+}
+
+// kill kills process of local browser, remote browser has no launcher.
+func (p *Pool) kill(l *launcher.Launcher) {
+	if l != nil {
+		l.Kill()
+		l.Cleanup()
+	}
+}
+
+// watch checks health of browser until pool is closed.
+func (p *Pool) watch() {
+	ticker := time.NewTicker(p.HealthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			browser := p.browser
+			p.mu.Unlock()
+
+			if browser != nil {
+				p.check(browser)
+			}
+		}
+	}
+}
+
+// check relaunches browser if it does not respond.
+func (p *Pool) check(browser *rod.Browser) {
+	ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+	defer cancel()
+
+	_, err := browser.Context(ctx).Version()
+	if err == nil {
+		return
+	}
+	p.logger.Warn().Err(err).Msg("browser is unhealthy")
+
+	p.mu.Lock()
+	if p.browser == browser {
+		p.release()
+	}
+	p.mu.Unlock()
+
+	_, err = p.get()
+	if errors.Is(err, ErrClosed) {
+		return
+	}
+
+	if err != nil {
+		p.logger.Error().Err(err).Msg("browser is not relaunched")
+		return
+	}
+	p.logger.Info().Msg("browser relaunched")
+}
+
+// release closes local browser and kills its process. Pool is disconnected
+// from remote browser, it is not closed.
+func (p *Pool) release() {
+	if p.browser != nil && p.launcher != nil {
+		// unresponsive browser does not block release
+		ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+		p.browser.Context(ctx).Close()
+		cancel()
+	}
+	p.browser = nil
+
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+	}
+
+	p.kill(p.launcher)
+	p.launcher = nil
+}
+
+// Close closes browser, pages are not opened after it.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+
+	p.closed = true
+	close(p.done)
+	p.release()
+	return nil
+}
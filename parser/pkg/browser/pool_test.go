@@ -0,0 +1,134 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/rs/zerolog"
+)
+
+// fixture returns server of page with title and path to browser executable.
+// Page of path /ua shows user agent of request.
+// Test is skipped if browser is not installed.
+func fixture(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	bin, ok := launcher.LookPath()
+	if !ok {
+		t.Skip("browser is not installed")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if r.URL.Path == "/ua" {
+			fmt.Fprintf(w, `<html><body><h1>%s</h1></body></html>`, html.EscapeString(r.UserAgent()))
+			return
+		}
+		fmt.Fprint(w, `<html><body><h1>Новости</h1></body></html>`)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, bin
+}
+
+// visit opens page of pool and returns its heading.
+func visit(t *testing.T, pool *Pool, url string, opts PageOptions) string {
+	t.Helper()
+	page, release, err := pool.Page(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	if err := page.Navigate(url); err != nil {
+		t.Fatal(err)
+	}
+
+	el, err := page.Element("h1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := el.Text()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return text
+}
+
+func TestPoolLocal(t *testing.T) {
+	server, bin := fixture(t)
+	logger := zerolog.Nop()
+
+	pool := New(Config{Bin: bin}, &logger)
+	if text := visit(t, pool, server.URL, PageOptions{}); text != "Новости" {
+		t.Errorf("heading = %q, want Новости", text)
+	}
+
+	if err := pool.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := pool.Page(context.Background(), PageOptions{}); err != ErrClosed {
+		t.Errorf("Page of closed pool error = %v, want ErrClosed", err)
+	}
+}
+
+func TestPoolRemote(t *testing.T) {
+	server, bin := fixture(t)
+	logger := zerolog.Nop()
+
+	l := launcher.New().Bin(bin).Headless(true)
+	controlURL, err := l.Launch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		l.Kill()
+		l.Cleanup()
+	})
+
+	pool := New(Config{ControlURL: controlURL}, &logger)
+	if text := visit(t, pool, server.URL, PageOptions{}); text != "Новости" {
+		t.Errorf("heading = %q, want Новости", text)
+	}
+
+	if err := pool.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// remote browser is not closed by pool
+	browser := rod.New().ControlURL(controlURL)
+	if err := browser.Connect(); err != nil {
+		t.Fatalf("remote browser is closed: %v", err)
+	}
+	defer browser.Close()
+
+	if _, err := browser.Version(); err != nil {
+		t.Errorf("remote browser does not respond: %v", err)
+	}
+}
+
+func TestPoolIdentity(t *testing.T) {
+	server, bin := fixture(t)
+	logger := zerolog.Nop()
+
+	pool := New(Config{Bin: bin, Block: true}, &logger)
+	defer pool.Close()
+
+	ua := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	text := visit(t, pool, server.URL+"/ua", PageOptions{Headers: map[string]string{
+		"User-Agent":      ua,
+		"Accept-Language": "ru-RU,ru;q=0.9",
+	}})
+
+	if text != ua {
+		t.Errorf("user agent = %q, want %q", text, ua)
+	}
+}
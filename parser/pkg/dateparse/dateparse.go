@@ -0,0 +1,235 @@
+// Package dateparse parses dates of news pages written in several layouts,
+// with Russian month names or relative to current time.
+package dateparse
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	// zones of sources are available without tzdata of system
+	_ "time/tzdata"
+)
+
+// DefaultLayouts are tried after layouts of source. Russian month names
+// are replaced with English ones before parsing, so "2 Jan 2006" matches "2 января 2006".
+var DefaultLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	time.DateTime,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	time.DateOnly,
+	"15:04 02.01.2006",
+	"02.01.2006 15:04",
+	"02.01.2006",
+	"2 Jan 2006 15:04",
+	"2 Jan 2006, 15:04",
+	"15:04, 2 Jan 2006",
+	"2 Jan 2006",
+	"2 Jan, 15:04",
+	"2 Jan 15:04",
+}
+
+var ErrFormat = errors.New("unknown date format")
+
+// Parser parses dates in zone of source. Dates without zone
+// are in location of parser, parsed dates are in UTC.
+type Parser struct {
+	loc     *time.Location
+	layouts []string
+}
+
+// New returns parser of dates in given location, UTC is used if it is nil.
+// Layouts are tried before DefaultLayouts.
+func New(loc *time.Location, layouts ...string) *Parser {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	return &Parser{
+		loc:     loc,
+		layouts: slices.Concat(layouts, DefaultLayouts),
+	}
+}
+
+// LoadLocation returns location by IANA name, UTC is returned for empty name.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	return time.LoadLocation(name)
+}
+
+// Parse parses absolute or relative date and returns it in UTC.
+func (p *Parser) Parse(s string) (time.Time, error) {
+	return p.parse(s, time.Now())
+}
+
+func (p *Parser) parse(s string, now time.Time) (time.Time, error) {
+	s = normalize(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("%w: empty date", ErrFormat)
+	}
+
+	if t, ok := p.relative(s, now.In(p.loc)); ok {
+		return t.UTC(), nil
+	}
+
+	for _, layout := range p.layouts {
+		t, err := time.ParseInLocation(layout, s, p.loc)
+		if err != nil {
+			continue
+		}
+
+		// layouts without year mean latest year in which date is not in future
+		if t.Year() == 0 {
+			var ok bool
+			if t, ok = p.year(t, now); !ok {
+				continue
+			}
+		}
+
+		return t.UTC(), nil
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %q", ErrFormat, s)
+}
+
+// yearLookback is number of years searched for date without year,
+// 29 February is found within it.
+const yearLookback = 8
+
+// year returns date without year in latest year in which date exists
+// and is not in future. Year is resolved before date is built, so
+// 29 February is not normalized to 1 March of non-leap year.
+func (p *Parser) year(t time.Time, now time.Time) (time.Time, bool) {
+	now = now.In(p.loc)
+	_, month, day := t.Date()
+	hour, min, sec := t.Clock()
+
+	for year := now.Year(); year >= now.Year()-yearLookback; year-- {
+		date := time.Date(year, month, day, hour, min, sec, t.Nanosecond(), p.loc)
+		if date.Day() == day && !date.After(now) {
+			return date, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+var (
+	// "5 минут назад", "час назад"
+	agoRegexp = regexp.MustCompile(`^(?:(\d+) )?(\pL+) назад$`)
+	// "вчера", "сегодня в 15:04", "вчера, 15:04"
+	dayRegexp = regexp.MustCompile(`^(сегодня|вчера|позавчера)(?:,? (?:в )?(\d{1,2}):(\d{2}))?$`)
+)
+
+var days = map[string]int{
+	"сегодня":   0,
+	"вчера":     1,
+	"позавчера": 2,
+}
+
+func (p *Parser) relative(s string, now time.Time) (time.Time, bool) {
+	s = strings.ToLower(s)
+
+	if s == "только что" || s == "сейчас" {
+		return now, true
+	}
+
+	if m := agoRegexp.FindStringSubmatch(s); m != nil {
+		n := 1
+		if m[1] != "" {
+			n, _ = strconv.Atoi(m[1])
+		}
+
+		return ago(now, n, m[2])
+	}
+
+	if m := dayRegexp.FindStringSubmatch(s); m != nil {
+		year, month, day := now.Date()
+		day -= days[m[1]]
+
+		hour, min := 0, 0
+		if m[2] != "" {
+			hour, _ = strconv.Atoi(m[2])
+			min, _ = strconv.Atoi(m[3])
+		}
+
+		return time.Date(year, month, day, hour, min, 0, 0, p.loc), true
+	}
+
+	return time.Time{}, false
+}
+
+// ago subtracts n units given by Russian word from now.
+func ago(now time.Time, n int, unit string) (time.Time, bool) {
+	switch {
+	case strings.HasPrefix(unit, "сек"):
+		return now.Add(-time.Duration(n) * time.Second), true
+	case strings.HasPrefix(unit, "мин"):
+		return now.Add(-time.Duration(n) * time.Minute), true
+	case strings.HasPrefix(unit, "час"):
+		return now.Add(-time.Duration(n) * time.Hour), true
+	case unit == "день" || unit == "дня" || unit == "дней":
+		return now.AddDate(0, 0, -n), true
+	case strings.HasPrefix(unit, "недел"):
+		return now.AddDate(0, 0, -7*n), true
+	case strings.HasPrefix(unit, "месяц"):
+		return now.AddDate(0, -n, 0), true
+	case unit == "год" || unit == "года" || unit == "лет":
+		return now.AddDate(-n, 0, 0), true
+	}
+
+	return time.Time{}, false
+}
+
+// months maps Russian month names and their abbreviations to English ones.
+var months = map[string]string{
+	"январь": "Jan", "января": "Jan", "янв": "Jan",
+	"февраль": "Feb", "февраля": "Feb", "фев": "Feb", "февр": "Feb",
+	"март": "Mar", "марта": "Mar", "мар": "Mar",
+	"апрель": "Apr", "апреля": "Apr", "апр": "Apr",
+	"май": "May", "мая": "May",
+	"июнь": "Jun", "июня": "Jun", "июн": "Jun",
+	"июль": "Jul", "июля": "Jul", "июл": "Jul",
+	"август": "Aug", "августа": "Aug", "авг": "Aug",
+	"сентябрь": "Sep", "сентября": "Sep", "сен": "Sep", "сент": "Sep",
+	"октябрь": "Oct", "октября": "Oct", "окт": "Oct",
+	"ноябрь": "Nov", "ноября": "Nov", "ноя": "Nov", "нояб": "Nov",
+	"декабрь": "Dec", "декабря": "Dec", "дек": "Dec",
+}
+
+// normalize collapses spaces, drops year suffix
+// and replaces Russian month names with English ones.
+func normalize(s string) string {
+	fields := strings.Fields(s)
+	result := fields[:0]
+	for _, field := range fields {
+		word := strings.TrimRight(field, ".,")
+		lower := strings.ToLower(word)
+
+		if lower == "г" {
+			// "2 января 2024 г., 15:04"
+			if strings.HasSuffix(field, ",") && len(result) > 0 {
+				result[len(result)-1] += ","
+			}
+			continue
+		}
+
+		if month, ok := months[lower]; ok {
+			field = month + strings.TrimLeft(field[len(word):], ".")
+		}
+
+		result = append(result, field)
+	}
+
+	return strings.Join(result, " ")
+}
@@ -0,0 +1,125 @@
+package dateparse
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	moscow, err := LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// now is 19 October 2026, 12:00 in Moscow
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	utc := func(year int, month time.Month, day, hour, min, sec int) time.Time {
+		return time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		loc  *time.Location
+		in   string
+		want time.Time
+	}{
+		// ria: page and modification dates in Moscow, RSS with offset
+		{"ria page", moscow, "15:04 02.01.2024", utc(2024, 1, 2, 12, 4, 0)},
+		{"ria modified", moscow, " 15:04  02.01.2024 ", utc(2024, 1, 2, 12, 4, 0)},
+		{"ria rss", moscow, "Tue, 02 Jan 2024 15:04:05 +0300", utc(2024, 1, 2, 12, 4, 5)},
+
+		// iz: page date in UTC, meta and RSS with offset
+		{"iz page", moscow, "2024-01-02T15:04:05Z", utc(2024, 1, 2, 15, 4, 5)},
+		{"iz meta", moscow, "2024-01-02T15:04:05+03:00", utc(2024, 1, 2, 12, 4, 5)},
+		{"iz rss", moscow, "Tue, 02 Jan 2024 15:04:05 +0300", utc(2024, 1, 2, 12, 4, 5)},
+
+		// lenta: meta with offset, RSS with zone name
+		{"lenta meta", moscow, "2024-01-02T15:04:05+03:00", utc(2024, 1, 2, 12, 4, 5)},
+		{"lenta rss", moscow, "Tue, 02 Jan 2024 15:04:05 MSK", utc(2024, 1, 2, 12, 4, 5)},
+
+		// newsdata: date without zone in UTC
+		{"newsdata", time.UTC, "2024-01-02 15:04:05", utc(2024, 1, 2, 15, 4, 5)},
+
+		// Russian month names
+		{"month", moscow, "2 января 2024, 15:04", utc(2024, 1, 2, 12, 4, 0)},
+		{"month year suffix", moscow, "2 января 2024 г., 15:04", utc(2024, 1, 2, 12, 4, 0)},
+		{"month abbreviation", moscow, "2 янв. 2024", utc(2024, 1, 1, 21, 0, 0)},
+		{"time before month", moscow, "15:04, 2 января 2024", utc(2024, 1, 2, 12, 4, 0)},
+
+		// dates without year are not in future
+		{"without year", moscow, "2 октября, 15:04", utc(2026, 10, 2, 12, 4, 0)},
+		{"without year future", moscow, "20 октября, 15:04", utc(2025, 10, 20, 12, 4, 0)},
+		{"without year leap", moscow, "29 февраля, 10:00", utc(2024, 2, 29, 7, 0, 0)},
+
+		// relative dates
+		{"now", moscow, "только что", now},
+		{"minutes ago", moscow, "5 минут назад", now.Add(-5 * time.Minute)},
+		{"hour ago", moscow, "час назад", now.Add(-time.Hour)},
+		{"days ago", moscow, "2 дня назад", now.AddDate(0, 0, -2)},
+		{"today", moscow, "сегодня в 10:00", utc(2026, 10, 19, 7, 0, 0)},
+		{"yesterday", moscow, "Вчера, 23:30", utc(2026, 10, 18, 20, 30, 0)},
+		{"yesterday date", moscow, "вчера", utc(2026, 10, 17, 21, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.loc).parse(tt.in, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !got.Equal(tt.want) || got.Location() != time.UTC {
+				t.Errorf("parse(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLeapYear(t *testing.T) {
+	p := New(time.UTC)
+
+	// 29 February of leap year is in current year
+	now := time.Date(2028, 3, 1, 0, 0, 0, 0, time.UTC)
+	got, err := p.parse("29 февраля, 10:00", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := time.Date(2028, 2, 29, 10, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("parse = %s, want %s", got, want)
+	}
+
+	// future 29 February of leap year is in previous leap year
+	now = time.Date(2028, 2, 28, 0, 0, 0, 0, time.UTC)
+	got, err = p.parse("29 февраля, 10:00", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("parse = %s, want %s", got, want)
+	}
+}
+
+func TestParseLayouts(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	// layouts of source are tried before default ones
+	got, err := New(time.UTC, "01/02/2006").parse("10/02/2024", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("parse = %s, want %s", got, want)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, in := range []string{"", "  ", "завтра", "2024/13/45"} {
+		if _, err := New(nil).parse(in, time.Now()); !errors.Is(err, ErrFormat) {
+			t.Errorf("parse(%q) error = %v, want ErrFormat", in, err)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package dateparse
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Meta returns date from content of meta tag with given property,
+// e.g. "article:published_time" of Open Graph.
+func (p *Parser) Meta(s *goquery.Selection, property string) (time.Time, bool) {
+	content, ok := s.Find(fmt.Sprintf("meta[property=%q]", property)).Attr("content")
+	if !ok {
+		return time.Time{}, false
+	}
+
+	t, err := p.Parse(content)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
@@ -0,0 +1,44 @@
+package dateparse
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestMeta(t *testing.T) {
+	moscow, err := LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<html><head>
+<meta property="article:published_time" content="2024-01-02T15:04:05+03:00">
+<meta property="article:modified_time" content="16:00 02.01.2024">
+<meta property="article:section" content="Политика">
+</head></html>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		property string
+		want     time.Time
+		ok       bool
+	}{
+		{"article:published_time", time.Date(2024, 1, 2, 12, 4, 5, 0, time.UTC), true},
+		{"article:modified_time", time.Date(2024, 1, 2, 13, 0, 0, 0, time.UTC), true},
+		{"article:section", time.Time{}, false},
+		{"article:expiration_time", time.Time{}, false},
+	}
+
+	p := New(moscow)
+	for _, tt := range tests {
+		got, ok := p.Meta(doc.Selection, tt.property)
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("Meta(%q) = %v, %v, want %v, %v", tt.property, got, ok, tt.want, tt.ok)
+		}
+	}
+}
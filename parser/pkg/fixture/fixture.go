@@ -0,0 +1,96 @@
+// Package fixture records HTTP responses of sources to directory and replays them offline.
+package fixture
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+)
+
+// ErrNotRecorded is returned by replayer for request without recorded response.
+var ErrNotRecorded = errors.New("response is not recorded")
+
+// Name returns file name of response to request.
+func Name(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Method + " " + req.URL.String()))
+	return hex.EncodeToString(sum[:8]) + ".http"
+}
+
+type recorder struct {
+	dir       string
+	transport http.RoundTripper
+}
+
+// Recorder returns wrapper of transport saving its responses to dir,
+// so responses are recorded through transport configured by client.
+func Recorder(dir string) (func(http.RoundTripper) http.RoundTripper, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("os.MkdirAll: %w", err)
+	}
+
+	return func(transport http.RoundTripper) http.RoundTripper {
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		return &recorder{dir, transport}
+	}, nil
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	dump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("httputil.DumpResponse: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(r.dir, Name(req)), dump, 0o644)
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("os.WriteFile: %w", err)
+	}
+
+	return resp, nil
+}
+
+type replayer struct {
+	dir string
+}
+
+// NewReplayer returns transport that serves responses recorded to dir.
+func NewReplayer(dir string) http.RoundTripper {
+	return &replayer{dir}
+}
+
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		defer req.Body.Close()
+	}
+
+	dump, err := os.ReadFile(filepath.Join(r.dir, Name(req)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, req.Method, req.URL)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(dump)), req)
+	if err != nil {
+		return nil, fmt.Errorf("http.ReadResponse: %w", err)
+	}
+
+	return resp, nil
+}
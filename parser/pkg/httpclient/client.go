@@ -5,12 +5,19 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/qsoulior/news/parser/pkg/scheduler"
 )
 
 type Client struct {
 	client  *http.Client
 	baseURL string
 	headers map[string]string
+	session *session
+	proxies *ProxyPool
 }
 
 func New(opts ...Option) *Client {
@@ -27,17 +34,41 @@ func New(opts ...Option) *Client {
 	return client
 }
 
+// BaseURL returns URL which paths of requests are resolved against.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
 func (c *Client) Send(ctx context.Context, method string, url string, body io.Reader, headers map[string]string) (*http.Response, error) {
 	resultURL := c.baseURL + url
 	req, err := http.NewRequestWithContext(ctx, method, resultURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("http.NewRequest: %w", err)
+	}
 	for key, value := range c.headers {
 		req.Header.Set(key, value)
 	}
 	for key, value := range headers {
 		req.Header.Add(key, value)
 	}
-	if err != nil {
-		return nil, fmt.Errorf("http.NewRequest: %w", err)
+
+	if c.session != nil {
+		identity, err := c.session.get()
+		if err != nil {
+			return nil, fmt.Errorf("c.session.get: %w", err)
+		}
+
+		// headers of request override headers of identity
+		for key, value := range identity {
+			if req.Header.Get(key) == "" {
+				req.Header.Set(key, value)
+			}
+		}
+	}
+
+	// request of scheduled job spends rate budget of source
+	if err := scheduler.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("scheduler.Wait: %w", err)
 	}
 
 	resp, err := c.client.Do(req)
@@ -71,3 +102,52 @@ func (c *Client) Put(ctx context.Context, url string, body io.Reader, headers ma
 func (c *Client) Patch(ctx context.Context, url string, body io.Reader, headers map[string]string) (*http.Response, error) {
 	return c.Send(ctx, http.MethodPatch, url, body, headers)
 }
+
+// Identity returns headers of session, they are nil if client has no session.
+func (c *Client) Identity() (Identity, error) {
+	if c.session == nil {
+		return nil, nil
+	}
+
+	identity, err := c.session.get()
+	if err != nil {
+		return nil, fmt.Errorf("c.session.get: %w", err)
+	}
+
+	return identity, nil
+}
+
+// Proxy returns URL of proxy bound to host, it is nil if client has no proxies.
+func (c *Client) Proxy(host string) (*url.URL, error) {
+	if c.proxies == nil {
+		return nil, nil
+	}
+
+	return c.proxies.Proxy(host)
+}
+
+// session keeps identity of client until it expires.
+type session struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	identity  Identity
+	createdAt time.Time
+}
+
+func (s *session) get() (Identity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.identity != nil && (s.ttl <= 0 || time.Since(s.createdAt) < s.ttl) {
+		return s.identity, nil
+	}
+
+	identity, err := NewIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	s.identity, s.createdAt = identity, time.Now()
+	return identity, nil
+}
@@ -0,0 +1,56 @@
+package httpclient
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/DataHenHQ/useragent"
+)
+
+// Identity is consistent set of headers sent by one desktop browser.
+type Identity map[string]string
+
+const acceptLanguage = "ru-RU,ru;q=0.9,en-US;q=0.8,en;q=0.7"
+
+var chromeRegexp = regexp.MustCompile(`Chrome/(\d+)`)
+
+// NewIdentity returns headers of random desktop browser. Client hints
+// are sent only by Chromium browsers, so they are set for Chrome user agent.
+func NewIdentity() (Identity, error) {
+	ua, err := useragent.Desktop()
+	if err != nil {
+		return nil, fmt.Errorf("useragent.Desktop: %w", err)
+	}
+
+	return newIdentity(ua), nil
+}
+
+func newIdentity(ua string) Identity {
+	identity := Identity{
+		"User-Agent":      ua,
+		"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		"Accept-Language": acceptLanguage,
+	}
+
+	if m := chromeRegexp.FindStringSubmatch(ua); m != nil && !strings.Contains(ua, "Edg/") && !strings.Contains(ua, "OPR/") {
+		identity["Sec-Ch-Ua"] = fmt.Sprintf(`"Chromium";v="%[1]s", "Google Chrome";v="%[1]s", "Not-A.Brand";v="99"`, m[1])
+		identity["Sec-Ch-Ua-Mobile"] = "?0"
+		identity["Sec-Ch-Ua-Platform"] = fmt.Sprintf("%q", platform(ua))
+	}
+
+	return identity
+}
+
+func platform(ua string) string {
+	switch {
+	case strings.Contains(ua, "Windows"):
+		return "Windows"
+	case strings.Contains(ua, "Mac OS X"):
+		return "macOS"
+	case strings.Contains(ua, "CrOS"):
+		return "Chrome OS"
+	}
+
+	return "Linux"
+}
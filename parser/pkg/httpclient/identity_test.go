@@ -0,0 +1,101 @@
+package httpclient
+
+import "testing"
+
+func TestNewIdentity(t *testing.T) {
+	tests := []struct {
+		name     string
+		ua       string
+		hints    string
+		platform string
+	}{
+		{
+			name:     "chrome windows",
+			ua:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			hints:    `"Chromium";v="120", "Google Chrome";v="120", "Not-A.Brand";v="99"`,
+			platform: `"Windows"`,
+		},
+		{
+			name:     "chrome mac",
+			ua:       "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0.0.0 Safari/537.36",
+			hints:    `"Chromium";v="119", "Google Chrome";v="119", "Not-A.Brand";v="99"`,
+			platform: `"macOS"`,
+		},
+		{
+			name:     "chrome linux",
+			ua:       "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/118.0.0.0 Safari/537.36",
+			hints:    `"Chromium";v="118", "Google Chrome";v="118", "Not-A.Brand";v="99"`,
+			platform: `"Linux"`,
+		},
+		{
+			name: "edge",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+		},
+		{
+			name: "firefox",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity := newIdentity(tt.ua)
+
+			if identity["User-Agent"] != tt.ua {
+				t.Errorf("User-Agent = %q, want %q", identity["User-Agent"], tt.ua)
+			}
+
+			if identity["Accept-Language"] != acceptLanguage {
+				t.Errorf("Accept-Language = %q, want %q", identity["Accept-Language"], acceptLanguage)
+			}
+
+			if identity["Sec-Ch-Ua"] != tt.hints {
+				t.Errorf("Sec-Ch-Ua = %q, want %q", identity["Sec-Ch-Ua"], tt.hints)
+			}
+
+			if identity["Sec-Ch-Ua-Platform"] != tt.platform {
+				t.Errorf("Sec-Ch-Ua-Platform = %q, want %q", identity["Sec-Ch-Ua-Platform"], tt.platform)
+			}
+		})
+	}
+}
+
+func TestSessionTTL(t *testing.T) {
+	s := &session{}
+	first, err := s.get()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// identity is not renewed if ttl is zero
+	second, _ := s.get()
+	if first["User-Agent"] != second["User-Agent"] || len(first) != len(second) {
+		t.Errorf("identity is renewed: %v, %v", first, second)
+	}
+
+	// expired identity is renewed
+	s.ttl = 1
+	s.identity["User-Agent"] = "expired"
+	third, _ := s.get()
+	if third["User-Agent"] == "expired" {
+		t.Error("expired identity is not renewed")
+	}
+}
+
+func TestClientIdentity(t *testing.T) {
+	identity, err := New().Identity()
+	if err != nil || identity != nil {
+		t.Errorf("Identity of client without session = %v, %v, want nil", identity, err)
+	}
+
+	client := New(Session(0))
+	first, err := client.Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, _ := client.Identity()
+	if first["User-Agent"] == "" || first["User-Agent"] != second["User-Agent"] {
+		t.Errorf("Identity = %q, %q, want same user agent", first["User-Agent"], second["User-Agent"])
+	}
+}
@@ -1,6 +1,7 @@
 package httpclient
 
 import (
+	"net/http"
 	"net/http/cookiejar"
 	"time"
 )
@@ -32,3 +33,38 @@ func CookieJar(jar *cookiejar.Jar) Option {
 		c.client.Jar = jar
 	}
 }
+
+// Transport replaces transport of client, proxies set before it are not used.
+func Transport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.client.Transport = transport
+		c.proxies = nil
+	}
+}
+
+// WrapTransport wraps transport set by previous options or default transport.
+func WrapTransport(wrap func(http.RoundTripper) http.RoundTripper) Option {
+	return func(c *Client) {
+		transport := c.client.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		c.client.Transport = wrap(transport)
+	}
+}
+
+// Session sends same identity headers in requests, identity is renewed after ttl.
+// It is not renewed if ttl is zero.
+func Session(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.session = &session{ttl: ttl}
+	}
+}
+
+// Proxies sends requests through proxies of pool, it replaces transport of client.
+func Proxies(pool *ProxyPool) Option {
+	return func(c *Client) {
+		c.client.Transport = pool
+		c.proxies = pool
+	}
+}
@@ -0,0 +1,209 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type ProxyConfig struct {
+	// URLs of proxies, http, https and socks5 schemes are supported.
+	URLs []string
+	// Cooldown is time proxy is not used after 403 or 429 response
+	// or MaxFailures failed requests in a row.
+	Cooldown time.Duration
+	// MaxFailures is number of failed requests in a row after which proxy cools down.
+	MaxFailures int
+}
+
+const (
+	DefaultProxyCooldown    = 5 * time.Minute
+	DefaultProxyMaxFailures = 3
+)
+
+var ErrNoProxy = errors.New("all proxies are cooling down")
+
+// ProxyPool sends requests through proxies. Requests to same host use same proxy
+// while it is healthy, so site sees consistent address of session.
+type ProxyPool struct {
+	ProxyConfig
+
+	mu      sync.Mutex
+	proxies []*proxy
+	hosts   map[string]*proxy
+	next    int
+}
+
+type proxy struct {
+	url       *url.URL
+	transport *http.Transport
+	failures  int
+	until     time.Time
+}
+
+func NewProxyPool(cfg ProxyConfig) (*ProxyPool, error) {
+	if len(cfg.URLs) == 0 {
+		return nil, errors.New("proxy urls are empty")
+	}
+
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = DefaultProxyCooldown
+	}
+
+	if cfg.MaxFailures <= 0 {
+		cfg.MaxFailures = DefaultProxyMaxFailures
+	}
+
+	proxies := make([]*proxy, len(cfg.URLs))
+	for i, rawURL := range cfg.URLs {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("url.Parse: %w", err)
+		}
+
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+		}
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = http.ProxyURL(u)
+		proxies[i] = &proxy{url: u, transport: transport}
+	}
+
+	pool := &ProxyPool{
+		ProxyConfig: cfg,
+		proxies:     proxies,
+		hosts:       make(map[string]*proxy),
+	}
+
+	return pool, nil
+}
+
+// RoundTrip sends request through proxy of its host.
+func (p *ProxyPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	proxy, err := p.get(req.URL.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := proxy.transport.RoundTrip(req)
+	if err != nil {
+		// canceled request is not failure of proxy, timed out one is
+		if !errors.Is(req.Context().Err(), context.Canceled) {
+			p.fail(proxy)
+		}
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		p.cool(proxy, retryAfter(resp))
+	default:
+		p.succeed(proxy)
+	}
+
+	return resp, nil
+}
+
+// Proxy returns URL of proxy bound to host, so other clients
+// such as browser reach host from same address.
+func (p *ProxyPool) Proxy(host string) (*url.URL, error) {
+	proxy, err := p.get(host)
+	if err != nil {
+		return nil, err
+	}
+
+	return proxy.url, nil
+}
+
+// get returns proxy bound to host or binds next available one.
+func (p *ProxyPool) get(host string) (*proxy, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if proxy, ok := p.hosts[host]; ok && now.After(proxy.until) {
+		return proxy, nil
+	}
+
+	for range p.proxies {
+		proxy := p.proxies[p.next]
+		p.next = (p.next + 1) % len(p.proxies)
+
+		if now.After(proxy.until) {
+			p.hosts[host] = proxy
+			return proxy, nil
+		}
+	}
+
+	return nil, ErrNoProxy
+}
+
+func (p *ProxyPool) succeed(proxy *proxy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	proxy.failures = 0
+}
+
+func (p *ProxyPool) fail(proxy *proxy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	proxy.failures++
+	if proxy.failures >= p.MaxFailures {
+		p.release(proxy, p.Cooldown)
+	}
+}
+
+// cool stops using proxy for cooldown or for time requested by site if it is longer.
+func (p *ProxyPool) cool(proxy *proxy, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.release(proxy, max(d, p.Cooldown))
+}
+
+// release unbinds hosts of proxy, they get other proxies until it cools down.
+func (p *ProxyPool) release(proxy *proxy, d time.Duration) {
+	proxy.failures = 0
+	proxy.until = time.Now().Add(d)
+
+	for host, item := range p.hosts {
+		if item == proxy {
+			delete(p.hosts, host)
+		}
+	}
+}
+
+// retryAfter returns delay given by Retry-After header in seconds or as HTTP date.
+func retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+
+	return 0
+}
+
+// CloseIdleConnections closes idle connections to proxies.
+func (p *ProxyPool) CloseIdleConnections() {
+	for _, proxy := range p.proxies {
+		proxy.transport.CloseIdleConnections()
+	}
+}
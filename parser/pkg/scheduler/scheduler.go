@@ -0,0 +1,213 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type Priority int
+
+const (
+	PriorityArchive Priority = iota
+	PriorityFeed
+	PrioritySearch
+
+	priorityCount = iota
+)
+
+// ErrPreempted is cause of job context canceled in favor of search job.
+var ErrPreempted = errors.New("job is preempted")
+
+type Config struct {
+	// Concurrency is maximum number of jobs running at once.
+	Concurrency int
+	// Interval is minimum time between requests of jobs.
+	Interval time.Duration
+}
+
+const DefaultConcurrency = 2
+
+type slot struct {
+	priority  Priority
+	cancel    context.CancelCauseFunc
+	preempted bool
+}
+
+type waiter struct {
+	priority Priority
+	ready    chan *slot
+}
+
+// Scheduler shares concurrency and rate budget of source between jobs.
+// Jobs of higher priority run first, archive jobs are preempted by search jobs.
+// Rate budget is spent by requests of jobs, see Wait.
+type Scheduler struct {
+	Config
+
+	mu      sync.Mutex
+	waiting [priorityCount][]*waiter
+	running map[*slot]struct{}
+	next    time.Time
+}
+
+func New(cfg Config) *Scheduler {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = DefaultConcurrency
+	}
+
+	return &Scheduler{
+		Config:  cfg,
+		running: make(map[*slot]struct{}, cfg.Concurrency),
+	}
+}
+
+// Acquire blocks until job of given priority can run. Returned context is canceled
+// with ErrPreempted cause if job is preempted, Wait spends rate budget of scheduler
+// with it. Release must be called when job is done.
+func (s *Scheduler) Acquire(ctx context.Context, priority Priority) (context.Context, func(), error) {
+	w := &waiter{priority: priority, ready: make(chan *slot, 1)}
+
+	s.mu.Lock()
+	s.waiting[priority] = append(s.waiting[priority], w)
+	s.dispatch()
+	s.preempt()
+	s.mu.Unlock()
+
+	var sl *slot
+	select {
+	case sl = <-w.ready:
+	case <-ctx.Done():
+		s.mu.Lock()
+		if !s.remove(w) {
+			// slot is granted concurrently
+			sl = <-w.ready
+			s.release(sl)
+		}
+		s.mu.Unlock()
+		return nil, nil, ctx.Err()
+	}
+
+	jobCtx, cancel := context.WithCancelCause(context.WithValue(ctx, schedulerKey{}, s))
+	s.mu.Lock()
+	sl.cancel = cancel
+	preempted := sl.preempted
+	s.mu.Unlock()
+
+	release := func() {
+		cancel(nil)
+		s.mu.Lock()
+		s.release(sl)
+		s.mu.Unlock()
+	}
+
+	if preempted {
+		cancel(ErrPreempted)
+	}
+
+	return jobCtx, release, nil
+}
+
+type schedulerKey struct{}
+
+// Wait blocks until request of job running with ctx is within rate budget
+// of its scheduler. It returns immediately if ctx is not context of job.
+// Cause of ctx is returned if ctx is done while waiting.
+func Wait(ctx context.Context) error {
+	s, ok := ctx.Value(schedulerKey{}).(*Scheduler)
+	if !ok || s.Interval <= 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	start := s.reserve()
+	s.mu.Unlock()
+
+	delay := time.Until(start)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// dispatch grants free slots to waiters of highest priority.
+func (s *Scheduler) dispatch() {
+	for len(s.running) < s.Concurrency {
+		w := s.pop()
+		if w == nil {
+			return
+		}
+
+		sl := &slot{priority: w.priority}
+		s.running[sl] = struct{}{}
+		w.ready <- sl
+	}
+}
+
+// preempt cancels archive jobs while search jobs are waiting.
+func (s *Scheduler) preempt() {
+	waiting := len(s.waiting[PrioritySearch])
+	for sl := range s.running {
+		if waiting == 0 {
+			return
+		}
+
+		if sl.priority == PriorityArchive && !sl.preempted {
+			sl.preempted = true
+			if sl.cancel != nil {
+				sl.cancel(ErrPreempted)
+			}
+			waiting--
+		}
+	}
+}
+
+func (s *Scheduler) pop() *waiter {
+	for priority := priorityCount - 1; priority >= 0; priority-- {
+		if queue := s.waiting[priority]; len(queue) > 0 {
+			s.waiting[priority] = queue[1:]
+			return queue[0]
+		}
+	}
+
+	return nil
+}
+
+func (s *Scheduler) remove(w *waiter) bool {
+	queue := s.waiting[w.priority]
+	for i, item := range queue {
+		if item == w {
+			s.waiting[w.priority] = append(queue[:i], queue[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Scheduler) release(sl *slot) {
+	delete(s.running, sl)
+	s.dispatch()
+}
+
+// reserve returns start time of next request within rate budget.
+func (s *Scheduler) reserve() time.Time {
+	now := time.Now()
+	start := now
+	if s.next.After(now) {
+		start = s.next
+	}
+
+	s.next = start.Add(s.Interval)
+	return start
+}
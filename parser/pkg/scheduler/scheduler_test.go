@@ -0,0 +1,240 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// acquire runs Acquire in goroutine and returns channel of its result.
+func acquire(ctx context.Context, s *Scheduler, priority Priority) <-chan func() {
+	ch := make(chan func(), 1)
+	go func() {
+		_, release, err := s.Acquire(ctx, priority)
+		if err != nil {
+			close(ch)
+			return
+		}
+		ch <- release
+	}()
+	return ch
+}
+
+// waiting blocks until scheduler has n waiters of priority.
+func waiting(t *testing.T, s *Scheduler, priority Priority, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		count := len(s.waiting[priority])
+		s.mu.Unlock()
+
+		if count == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("%d waiters of priority %d are not queued", n, priority)
+}
+
+func TestAcquireConcurrency(t *testing.T) {
+	s := New(Config{Concurrency: 1})
+	ctx := context.Background()
+
+	_, release, err := s.Acquire(ctx, PriorityFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second := acquire(ctx, s, PriorityFeed)
+	waiting(t, s, PriorityFeed, 1)
+
+	select {
+	case <-second:
+		t.Fatal("second job runs while first job is running")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	release()
+	select {
+	case release := <-second:
+		release()
+	case <-time.After(time.Second):
+		t.Fatal("second job does not run after first job is released")
+	}
+}
+
+func TestAcquirePriority(t *testing.T) {
+	s := New(Config{Concurrency: 1})
+	ctx := context.Background()
+
+	_, release, err := s.Acquire(ctx, PriorityFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archive := acquire(ctx, s, PriorityArchive)
+	waiting(t, s, PriorityArchive, 1)
+	feed := acquire(ctx, s, PriorityFeed)
+	waiting(t, s, PriorityFeed, 1)
+
+	// feed job waiting later runs first
+	release()
+	select {
+	case release := <-feed:
+		release()
+	case <-archive:
+		t.Fatal("archive job runs before feed job")
+	case <-time.After(time.Second):
+		t.Fatal("feed job does not run")
+	}
+
+	select {
+	case release := <-archive:
+		release()
+	case <-time.After(time.Second):
+		t.Fatal("archive job does not run")
+	}
+}
+
+func TestAcquirePreempt(t *testing.T) {
+	s := New(Config{Concurrency: 1})
+	ctx := context.Background()
+
+	archiveCtx, releaseArchive, err := s.Acquire(ctx, PriorityArchive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	search := acquire(ctx, s, PrioritySearch)
+
+	select {
+	case <-archiveCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("archive job is not preempted")
+	}
+
+	if cause := context.Cause(archiveCtx); !errors.Is(cause, ErrPreempted) {
+		t.Fatalf("cause of archive job = %v, want ErrPreempted", cause)
+	}
+
+	// search job runs after preempted job is released
+	releaseArchive()
+	select {
+	case release := <-search:
+		release()
+	case <-time.After(time.Second):
+		t.Fatal("search job does not run")
+	}
+}
+
+func TestAcquireFeedIsNotPreempted(t *testing.T) {
+	s := New(Config{Concurrency: 1})
+	ctx := context.Background()
+
+	feedCtx, release, err := s.Acquire(ctx, PriorityFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	acquire(ctx, s, PrioritySearch)
+	waiting(t, s, PrioritySearch, 1)
+
+	if err := feedCtx.Err(); err != nil {
+		t.Fatalf("feed job is canceled: %v", err)
+	}
+}
+
+func TestAcquireCanceled(t *testing.T) {
+	s := New(Config{Concurrency: 1})
+
+	_, release, err := s.Acquire(context.Background(), PriorityFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := acquire(ctx, s, PriorityFeed)
+	waiting(t, s, PriorityFeed, 1)
+	cancel()
+
+	if _, ok := <-canceled; ok {
+		t.Fatal("canceled job runs")
+	}
+	waiting(t, s, PriorityFeed, 0)
+
+	// slot of canceled job is not leaked
+	release()
+	_, release, err = s.Acquire(context.Background(), PriorityFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+}
+
+func TestWait(t *testing.T) {
+	const interval = 20 * time.Millisecond
+	s := New(Config{Concurrency: 2, Interval: interval})
+
+	// requests outside of jobs are not limited
+	start := time.Now()
+	for range 3 {
+		if err := Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed >= interval {
+		t.Fatalf("requests outside of jobs wait %s", elapsed)
+	}
+
+	ctx, release, err := s.Acquire(context.Background(), PriorityFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	// each request of job spends budget
+	start = time.Now()
+	for range 4 {
+		if err := Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 3*interval {
+		t.Fatalf("4 requests take %s, want at least %s", elapsed, 3*interval)
+	}
+}
+
+func TestWaitPreempted(t *testing.T) {
+	s := New(Config{Concurrency: 1, Interval: time.Hour})
+
+	ctx, release, err := s.Acquire(context.Background(), PriorityArchive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// first request is not delayed, second one waits for interval
+	if err := Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := make(chan error, 1)
+	go func() { errs <- Wait(ctx) }()
+
+	search := acquire(context.Background(), s, PrioritySearch)
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrPreempted) {
+			t.Fatalf("Wait error = %v, want ErrPreempted", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait is not interrupted by preemption")
+	}
+
+	release()
+	if release, ok := <-search; ok {
+		release()
+	}
+}
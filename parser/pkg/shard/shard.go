@@ -0,0 +1,66 @@
+package shard
+
+import (
+	"strconv"
+	"time"
+)
+
+type State string
+
+const (
+	StatePending State = "pending"
+	StateRunning State = "running"
+	StateDone    State = "done"
+	StateFailed  State = "failed"
+)
+
+// Shard is range of archive pages from first page to page where crawling stops.
+type Shard struct {
+	ID       string `json:"id"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Page     string `json:"page"`
+	State    State  `json:"state"`
+	Count    int    `json:"count"`
+	Attempts int    `json:"attempts"`
+	Owner    string `json:"owner,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func New(from string, to string) Shard {
+	return Shard{
+		ID:    from + "-" + to,
+		From:  from,
+		To:    to,
+		Page:  from,
+		State: StatePending,
+	}
+}
+
+// Pages splits ascending numeric pages [from, to) into shards of given size.
+func Pages(from int, to int, size int) []Shard {
+	size = max(size, 1)
+	shards := make([]Shard, 0, (to-from+size-1)/size)
+	for start := from; start < to; start += size {
+		end := min(start+size, to)
+		shards = append(shards, New(strconv.Itoa(start), strconv.Itoa(end)))
+	}
+
+	return shards
+}
+
+// Days splits descending days from newest day to oldest day exclusive
+// into shards of given number of days. Pages are formatted with layout.
+func Days(from time.Time, to time.Time, size int, layout string) []Shard {
+	size = max(size, 1)
+	shards := make([]Shard, 0)
+	for start := from; start.After(to); start = start.AddDate(0, 0, -size) {
+		end := start.AddDate(0, 0, -size)
+		if end.Before(to) {
+			end = to
+		}
+		shards = append(shards, New(start.Format(layout), end.Format(layout)))
+	}
+
+	return shards
+}
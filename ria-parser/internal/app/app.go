@@ -2,18 +2,21 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"time"
 
-	"github.com/go-rod/rod"
 	"github.com/qsoulior/news/parser/app"
+	"github.com/qsoulior/news/parser/pkg/browser"
+	"github.com/qsoulior/news/parser/pkg/dateparse"
 	"github.com/qsoulior/news/parser/pkg/httpclient"
+	"github.com/qsoulior/news/parser/pkg/shard"
 	"github.com/qsoulior/news/ria-parser/internal/service"
 	"github.com/rs/zerolog"
 )
 
-func Run(cfg *Config) {
-	appID := "ria"
+const APP_ID = "ria"
 
+func Run(cfg *Config) {
 	out := zerolog.NewConsoleWriter(func(w *zerolog.ConsoleWriter) {
 		w.TimeFormat = time.RFC3339
 	})
@@ -22,36 +25,111 @@ func Run(cfg *Config) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	browser := rod.New().Context(ctx)
-	if err := browser.Connect(); err != nil {
-		logger.Fatal().Err(err).Msg("failed to init browser")
+	source, err := New(ctx, cfg, nil, &logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to init parser")
 	}
 
-	defer func() {
-		if err := browser.Close(); err != nil {
-			logger.Fatal().Err(err).Msg("failed to close browser")
+	app.Run(source.Config(APP_ID, &logger), source.Options)
+}
+
+// New returns parsers of source built from cfg.
+// Options of HTTP client override options of config.
+// Browser is launched on first view and closed after app is stopped.
+func New(ctx context.Context, cfg *Config, opts []httpclient.Option, logger *zerolog.Logger) (*app.Source, error) {
+	appID := APP_ID
+
+	var backfillShards []shard.Shard
+	if backfill := cfg.Service.Backfill; backfill.From != "" && backfill.To != "" {
+		from, err := time.Parse(time.DateOnly, backfill.From)
+		if err != nil {
+			return nil, fmt.Errorf("invalid backfill range: %w", err)
+		}
+
+		to, err := time.Parse(time.DateOnly, backfill.To)
+		if err != nil {
+			return nil, fmt.Errorf("invalid backfill range: %w", err)
 		}
-	}()
 
-	client := httpclient.New()
+		backfillShards = shard.Days(from, to, backfill.Days, service.PAGE_LAYOUT)
+	}
+
+	dates, err := cfg.Service.dates()
+	if err != nil {
+		return nil, err
+	}
+
+	pool := browser.New(cfg.Browser.config(), logger)
+	httpOpts, err := cfg.HTTP.ClientOptions()
+	if err != nil {
+		return nil, fmt.Errorf("invalid http options: %w", err)
+	}
 
-	searchParser := service.NewNewsSearch(appID, client, cfg.Service.URL, browser, &logger)
-	archiveParser := service.NewNewsArchive(appID, client, cfg.Service.URL, browser, &logger)
-	feedParser := service.NewNewsFeed(appID, client, cfg.Service.URL, &logger)
+	client := httpclient.New(append(httpOpts, opts...)...)
 
-	app.Run(
-		&app.Config{
-			ID:            appID,
-			SearchParser:  searchParser,
-			ArchiveParser: archiveParser,
-			FeedParser:    feedParser,
-			Logger:        &logger,
+	source := &app.Source{
+		Search: func() app.Parser {
+			return service.NewNewsSearch(appID, client, cfg.Service.URL, pool, dates, logger)
 		},
-		&app.Options{
+		Archive: func() app.Parser {
+			return service.NewNewsArchive(appID, client, cfg.Service.URL, pool, dates, logger)
+		},
+		Feed: func() app.Parser {
+			return service.NewNewsFeed(appID, client, cfg.Service.URL, dates, logger)
+		},
+
+		BackfillShards: backfillShards,
+
+		Close: pool.Close,
+
+		Options: &app.Options{
 			RabbitURL:    cfg.RabbitMQ.URL,
 			RedisURL:     cfg.Redis.URL,
 			FeedDelay:    &cfg.Service.FeedDelay,
 			ArchiveDelay: &cfg.Service.ArchiveDelay,
+
+			SearchDepth:    cfg.Service.SearchDepth,
+			MaxSearchDepth: cfg.Service.MaxSearchDepth,
+
+			FeedSchedule:    cfg.Service.FeedSchedule,
+			ArchiveSchedule: cfg.Service.ArchiveSchedule,
+
+			AdminHost:  cfg.Admin.Host,
+			AdminPort:  cfg.Admin.Port,
+			AdminToken: cfg.Admin.Token,
+
+			ContentType:     cfg.RabbitMQ.ContentType,
+			ContentEncoding: cfg.RabbitMQ.ContentEncoding,
 		},
-	)
+	}
+
+	return source, nil
+}
+
+// dates returns parser of dates in timezone of config or site.
+func (c ConfigService) dates() (*dateparse.Parser, error) {
+	timezone := c.Timezone
+	if timezone == "" {
+		timezone = service.TIMEZONE
+	}
+
+	loc, err := dateparse.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone: %w", err)
+	}
+
+	return dateparse.New(loc), nil
+}
+
+func (c ConfigBrowser) config() browser.Config {
+	return browser.Config{
+		ControlURL:     c.ControlURL,
+		Bin:            c.Bin,
+		Headful:        c.Headful,
+		MaxPages:       c.MaxPages,
+		PageTimeout:    c.PageTimeout,
+		HealthInterval: c.HealthInterval,
+		Block:          c.Block,
+		BlockHosts:     c.BlockHosts,
+	}
 }
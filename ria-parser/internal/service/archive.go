@@ -5,26 +5,31 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/go-rod/rod"
 	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/parser/pkg/browser"
+	"github.com/qsoulior/news/parser/pkg/dateparse"
 	"github.com/qsoulior/news/parser/pkg/httpclient"
-	"github.com/qsoulior/news/parser/pkg/queue"
 	"github.com/rs/zerolog"
 )
 
 const PAGE_LAYOUT = "20060102"
 
+// newsArchive parses archive page by parts, URLs of page are listed once
+// and removed only after they are parsed.
 type newsArchive struct {
 	*news
-	*newsView
-	urls queue.Queue[string]
+	view newsView
+
+	page string
+	urls []string
 }
 
 func NewNewsArchive(
 	appID string,
 	client *httpclient.Client,
 	url string,
-	browser *rod.Browser,
+	pool *browser.Pool,
+	dates *dateparse.Parser,
 	logger *zerolog.Logger,
 ) *newsArchive {
 	log := logger.With().Str("service", "archive").Logger()
@@ -32,18 +37,15 @@ func NewNewsArchive(
 	news := &news{
 		appID:  appID,
 		client: client,
+		dates:  dates,
 		logger: &log,
 	}
 
-	newsView := &newsView{
-		URL:     url,
-		browser: browser,
-	}
+	view := newNewsView(url, client, pool, &log)
 
 	archive := &newsArchive{
-		news:     news,
-		newsView: newsView,
-		urls:     queue.New[string](100),
+		news: news,
+		view: view,
 	}
 
 	return archive
@@ -65,40 +67,29 @@ func (n *newsArchive) Parse(ctx context.Context, query string, page string) ([]e
 		}
 	}
 
-	const limit = 20
-	urls := make([]string, 0, limit)
-	nextPage := page
-
-	if n.urls.Len() > 0 {
-		for i := 0; i < limit; i++ {
-			url, ok := n.urls.Pop()
-			if !ok {
-				break
-			}
-
-			urls = append(urls, url)
-		}
-
-		if n.urls.Len() == 0 {
-			nextPage = pageObj.AddDate(0, 0, -1).Format(PAGE_LAYOUT)
-		}
-	} else {
-		parsedUrls, err := n.parseURLs(ctx, "/"+page)
+	// URLs of another page are left if page is moved
+	if n.page != page {
+		urls, err := n.view.listURLs(ctx, "/"+page)
 		if err != nil {
 			return nil, "", err
 		}
 
-		parsedLen := len(parsedUrls)
-		urls = append(urls, parsedUrls[:min(parsedLen, limit)]...)
-		if parsedLen > limit {
-			n.urls.Push(parsedUrls[limit:]...)
-		}
+		n.page, n.urls = page, urls
 	}
 
+	const limit = 20
+	urls := n.urls[:min(len(n.urls), limit)]
+
 	news, err := n.parseMany(ctx, urls)
 	if err != nil {
 		return nil, "", fmt.Errorf("n.parseMany: %w", err)
 	}
 
-	return news, nextPage, nil
+	n.urls = n.urls[len(urls):]
+	if len(n.urls) > 0 {
+		return news, page, nil
+	}
+
+	n.page = ""
+	return news, pageObj.AddDate(0, 0, -1).Format(PAGE_LAYOUT), nil
 }
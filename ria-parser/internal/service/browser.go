@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/qsoulior/news/parser/pkg/browser"
+	"github.com/qsoulior/news/parser/pkg/httpclient"
+	"github.com/qsoulior/news/parser/pkg/scheduler"
+)
+
+// browserView lists URLs by clicking "more" button of page in browser.
+// Page is opened with identity and proxy of HTTP client, so site sees
+// same session whichever view requests it.
+type browserView struct {
+	URL    string
+	client *httpclient.Client
+	pool   *browser.Pool
+}
+
+func (n *browserView) listURLs(ctx context.Context, path string) ([]string, error) {
+	opts, err := n.pageOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	page, release, err := n.pool.Page(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("n.pool.Page: %w", err)
+	}
+	defer release()
+
+	// page and its "more" requests spend rate budget as HTTP requests do
+	if err := scheduler.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("scheduler.Wait: %w", err)
+	}
+
+	err = page.Navigate(n.URL + path)
+	if err != nil {
+		return nil, fmt.Errorf("n.page.Navigate: %w", err)
+	}
+
+	urls, err := n.parseView(ctx, page)
+	if err != nil {
+		return nil, fmt.Errorf("n.parseView: %w", err)
+	}
+
+	return urls, nil
+}
+
+func (n *browserView) pageOptions() (browser.PageOptions, error) {
+	headers, err := n.client.Identity()
+	if err != nil {
+		return browser.PageOptions{}, fmt.Errorf("n.client.Identity: %w", err)
+	}
+
+	u, err := url.Parse(n.URL)
+	if err != nil {
+		return browser.PageOptions{}, fmt.Errorf("url.Parse: %w", err)
+	}
+
+	proxy, err := n.client.Proxy(u.Host)
+	if err != nil {
+		return browser.PageOptions{}, fmt.Errorf("n.client.Proxy: %w", err)
+	}
+
+	return browser.PageOptions{Headers: headers, Proxy: proxy}, nil
+}
+
+func (n *browserView) parseView(ctx context.Context, page *rod.Page) ([]string, error) {
+	err := n.loadView(ctx, page)
+	if err != nil {
+		return nil, fmt.Errorf("n.loadView: %w", err)
+	}
+
+	list, err := page.Element(".list")
+	if err != nil {
+		return nil, fmt.Errorf("page.Element: %w", err)
+	}
+
+	err = list.WaitStable(1 * time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("list.WaitStable: %w", err)
+	}
+
+	listHTML, err := list.HTML()
+	if err != nil {
+		return nil, fmt.Errorf("list.HTML: %w", err)
+	}
+
+	listDocument, err := goquery.NewDocumentFromReader(strings.NewReader(listHTML))
+	if err != nil {
+		return nil, fmt.Errorf("goquery.NewDocumentFromReader: %w", err)
+	}
+
+	urls := listDocument.Find(".list-item__title[href]").Map(func(i int, s *goquery.Selection) string {
+		href, _ := s.Attr("href")
+		return href
+	})
+
+	return urls, nil
+}
+
+func (n *browserView) loadView(ctx context.Context, page *rod.Page) error {
+	err := page.WaitLoad()
+	if err != nil {
+		return fmt.Errorf("page.WaitLoad: %w", err)
+	}
+
+	listMore, err := page.Element(".list-more")
+	if err != nil {
+		return fmt.Errorf("page.Element: %w", err)
+	}
+
+	err = listMore.Timeout(5 * time.Second).WaitStableRAF()
+	if err != nil {
+		return fmt.Errorf("listMore.WaitStable: %w", err)
+	}
+
+	if err := scheduler.Wait(ctx); err != nil {
+		return fmt.Errorf("scheduler.Wait: %w", err)
+	}
+
+	err = listMore.Click(proto.InputMouseButtonLeft, 1)
+	if err != nil {
+		return fmt.Errorf("listMore.Click: %w", err)
+	}
+
+	for {
+		err = listMore.Timeout(5 * time.Second).WaitStableRAF()
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				break
+			}
+			return fmt.Errorf("listMore.WaitStableRAF: %w", err)
+		}
+
+		visible, err := listMore.Visible()
+		if err != nil {
+			return fmt.Errorf("listMore.Visible: %w", err)
+		}
+
+		if !visible {
+			break
+		}
+
+		loading, err := listMore.Matches(".loading")
+		if err != nil {
+			return fmt.Errorf("listMore.Matches: %w", err)
+		}
+
+		if loading {
+			continue
+		}
+
+		if err := scheduler.Wait(ctx); err != nil {
+			return fmt.Errorf("scheduler.Wait: %w", err)
+		}
+
+		err = listMore.Timeout(5 * time.Second).ScrollIntoView()
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				break
+			}
+			return fmt.Errorf("listMore.ScrollIntoView: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -6,8 +6,8 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/DataHenHQ/useragent"
 	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/parser/pkg/dateparse"
 	"github.com/qsoulior/news/parser/pkg/httpclient"
 	"github.com/qsoulior/news/parser/pkg/rssclient"
 	"github.com/rs/zerolog"
@@ -30,12 +30,13 @@ type newsFeed struct {
 	urlCache  map[string]time.Time
 }
 
-func NewNewsFeed(appID string, client *httpclient.Client, url string, logger *zerolog.Logger) *newsFeed {
+func NewNewsFeed(appID string, client *httpclient.Client, url string, dates *dateparse.Parser, logger *zerolog.Logger) *newsFeed {
 	log := logger.With().Str("service", "feed").Logger()
 
 	news := &news{
 		appID:  appID,
 		client: client,
+		dates:  dates,
 		logger: &log,
 	}
 
@@ -66,14 +67,7 @@ func (n *newsFeed) Parse(ctx context.Context, query string, page string) ([]enti
 func (n *newsFeed) parseURLs(ctx context.Context) ([]string, error) {
 	u := n.url + "/export/rss2/archive/index.xml"
 
-	ua, err := useragent.Desktop()
-	if err != nil {
-		return nil, fmt.Errorf("useragent.Desktop: %w", err)
-	}
-
-	items, err := n.rssclient.Get(ctx, "item", u, map[string]string{
-		"User-Agent": ua,
-	})
+	items, err := n.rssclient.Get(ctx, "item", u, nil)
 	if err != nil {
 		return nil, fmt.Errorf("n.rssclient.Get: %w", err)
 	}
@@ -90,9 +84,9 @@ func (n *newsFeed) parseURLs(ctx context.Context) ([]string, error) {
 		link := item.Link
 		links[link] = struct{}{}
 
-		pubDate, err := time.Parse(time.RFC1123Z, item.PubDate)
+		pubDate, err := n.dates.Parse(item.PubDate)
 		if err != nil {
-			return nil, fmt.Errorf("time.Parse: %w", err)
+			return nil, fmt.Errorf("n.dates.Parse: %w", err)
 		}
 
 		if pd, ok := n.urlCache[link]; !ok || pubDate.After(pd) {
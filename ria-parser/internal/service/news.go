@@ -7,27 +7,31 @@ import (
 	"strings"
 	"time"
 
-	"github.com/DataHenHQ/useragent"
 	"github.com/PuerkitoBio/goquery"
 	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/aggregator/pkg/urlcanon"
+	"github.com/qsoulior/news/parser/pkg/dateparse"
 	"github.com/qsoulior/news/parser/pkg/httpclient"
 	"github.com/rs/zerolog"
 )
 
+// TIMEZONE is zone of dates on pages of site.
+const TIMEZONE = "Europe/Moscow"
+
 type news struct {
 	appID  string
 	client *httpclient.Client
+	dates  *dateparse.Parser
 	logger *zerolog.Logger
 }
 
+// ParseOne parses single news by URL.
+func (n *news) ParseOne(ctx context.Context, url string) (*entity.News, error) {
+	return n.parseOne(ctx, url)
+}
+
 func (n *news) parseOne(ctx context.Context, url string) (*entity.News, error) {
-	ua, err := useragent.Desktop()
-	if err != nil {
-		return nil, fmt.Errorf("useragent.Desktop: %w", err)
-	}
-	resp, err := n.client.Get(ctx, url, map[string]string{
-		"User-Agent": ua,
-	})
+	resp, err := n.client.Get(ctx, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("n.client.Get: %w", err)
 	}
@@ -36,17 +40,26 @@ func (n *news) parseOne(ctx context.Context, url string) (*entity.News, error) {
 		return nil, newStatusError(resp.StatusCode)
 	}
 
+	fetchedAt := time.Now().UTC()
+
 	defer resp.Body.Close()
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("goquery.NewDocumentFromReader: %w", err)
 	}
 
+	href, _ := doc.Find("link[rel=canonical]").Attr("href")
+	link, err := urlcanon.Resolve(resp.Request.URL.String(), href)
+	if err != nil {
+		return nil, fmt.Errorf("urlcanon.Resolve: %w", err)
+	}
+
 	news := &entity.News{
 		NewsHead: entity.NewsHead{
 			Source: n.appID,
 		},
-		Link: resp.Request.URL.String(),
+		Link:      link,
+		FetchedAt: fetchedAt,
 	}
 
 	article := doc.Find(".article")
@@ -55,26 +68,24 @@ func (n *news) parseOne(ctx context.Context, url string) (*entity.News, error) {
 		Find(".article__supertag-header .article__supertag-header-title").
 		Map(func(i int, s *goquery.Selection) string { return s.Text() })
 
-	datetimeLayout := "15:04 02.01.2006"
-	datetimeStr := strings.TrimSpace(
+	datetimeStr := article.Find(".article__info-date a").First().Text()
+	news.PublishedAt, err = n.dates.Parse(datetimeStr)
+	if err != nil {
+		return nil, fmt.Errorf("n.dates.Parse: %w", err)
+	}
+
+	news.ModifiedAt = news.PublishedAt
+	modifiedStr := strings.TrimSpace(
 		article.Find(".article__info-date .article__info-date-modified").Children().Remove().End().Text(),
 	)
 
-	if datetimeStr == "" {
-		datetimeStr = article.Find(".article__info-date a").Text()
-	} else {
-		parts := strings.SplitN(datetimeStr, " ", 2)
-		if len(parts) > 1 {
-			datetimeStr = strings.Trim(parts[1], "()")
+	if parts := strings.SplitN(modifiedStr, " ", 2); len(parts) > 1 {
+		modifiedAt, err := n.dates.Parse(strings.Trim(parts[1], "()"))
+		if err == nil && modifiedAt.After(news.PublishedAt) {
+			news.ModifiedAt = modifiedAt
 		}
 	}
 
-	loc, _ := time.LoadLocation("Europe/Moscow")
-	news.PublishedAt, err = time.ParseInLocation(datetimeLayout, datetimeStr, loc)
-	if err != nil {
-		return nil, fmt.Errorf("time.Parse: %w", err)
-	}
-
 	news.Title = article.Find(".article__title").Text()
 	news.Description = article.Find(".article__second-title").Text()
 
@@ -4,23 +4,37 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strconv"
+	"sync"
+	"time"
 
-	"github.com/go-rod/rod"
 	"github.com/qsoulior/news/aggregator/entity"
+	"github.com/qsoulior/news/parser/pkg/browser"
+	"github.com/qsoulior/news/parser/pkg/dateparse"
 	"github.com/qsoulior/news/parser/pkg/httpclient"
 	"github.com/rs/zerolog"
 )
 
 type newsSearch struct {
 	*news
-	*newsView
+	view newsView
+
+	// urls of recent queries loaded by view
+	mu      sync.Mutex
+	results map[string]*searchResult
+}
+
+type searchResult struct {
+	urls     []string
+	loadedAt time.Time
 }
 
 func NewNewsSearch(
 	appID string,
 	client *httpclient.Client,
 	url string,
-	browser *rod.Browser,
+	pool *browser.Pool,
+	dates *dateparse.Parser,
 	logger *zerolog.Logger,
 ) *newsSearch {
 	log := logger.With().Str("service", "search").Logger()
@@ -28,37 +42,105 @@ func NewNewsSearch(
 	news := &news{
 		appID:  appID,
 		client: client,
+		dates:  dates,
 		logger: &log,
 	}
 
-	newsView := &newsView{
-		URL:     url,
-		browser: browser,
-	}
+	view := newNewsView(url, client, pool, &log)
 
 	search := &newsSearch{
-		news:     news,
-		newsView: newsView,
+		news:    news,
+		view:    view,
+		results: make(map[string]*searchResult),
 	}
 
 	return search
 }
 
+const (
+	SEARCH_SIZE = 20
+	// SEARCH_CACHE_SIZE and SEARCH_CACHE_TTL bound results of queries kept for next pages.
+	SEARCH_CACHE_SIZE = 32
+	SEARCH_CACHE_TTL  = 30 * time.Minute
+)
+
+// Parse parses page of search results. Page is offset of results.
+// View loads all results at once, so they are reused for next pages of same query.
 func (n *newsSearch) Parse(ctx context.Context, query string, page string) ([]entity.News, string, error) {
+	from := 0
+	if page != "" {
+		var err error
+		from, err = strconv.Atoi(page)
+		if err != nil {
+			return nil, "", fmt.Errorf("strconv.Atoi: %w", err)
+		}
+	}
+
+	urls, err := n.searchURLs(ctx, query, from == 0)
+	if err != nil {
+		return nil, "", err
+	}
+
+	from = min(from, len(urls))
+	to := min(from+SEARCH_SIZE, len(urls))
+
+	news, err := n.parseMany(ctx, urls[from:to])
+	if err != nil {
+		return nil, "", fmt.Errorf("n.parseMany: %w", err)
+	}
+
+	nextPage := ""
+	if to < len(urls) {
+		nextPage = strconv.Itoa(to)
+	}
+
+	return news, nextPage, nil
+}
+
+// searchURLs returns urls of query results loaded by view or cached ones.
+func (n *newsSearch) searchURLs(ctx context.Context, query string, reload bool) ([]string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := time.Now()
+	n.evict(now)
+
+	if result, ok := n.results[query]; ok && !reload {
+		return result.urls, nil
+	}
+
 	u, _ := url.Parse("/search")
 	values := u.Query()
 	values.Set("query", query)
 	u.RawQuery = values.Encode()
 
-	urls, err := n.parseURLs(ctx, u.String())
+	urls, err := n.view.listURLs(ctx, u.String())
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
-	news, err := n.parseMany(ctx, urls)
-	if err != nil {
-		return nil, "", fmt.Errorf("n.parseMany: %w", err)
+	n.results[query] = &searchResult{urls: urls, loadedAt: now}
+	return urls, nil
+}
+
+// evict removes expired results and oldest ones beyond cache size.
+func (n *newsSearch) evict(now time.Time) {
+	for query, result := range n.results {
+		if now.Sub(result.loadedAt) > SEARCH_CACHE_TTL {
+			delete(n.results, query)
+		}
 	}
 
-	return news, "", nil
+	for len(n.results) >= SEARCH_CACHE_SIZE {
+		var (
+			oldest   string
+			loadedAt time.Time
+		)
+		for query, result := range n.results {
+			if loadedAt.IsZero() || result.loadedAt.Before(loadedAt) {
+				oldest, loadedAt = query, result.loadedAt
+			}
+		}
+		delete(n.results, oldest)
+	}
 }
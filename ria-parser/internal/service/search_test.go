@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// countingView counts loads of each path.
+type countingView map[string]int
+
+func (v countingView) listURLs(ctx context.Context, path string) ([]string, error) {
+	v[path]++
+	return nil, nil
+}
+
+func TestSearchCacheQueries(t *testing.T) {
+	logger := zerolog.Nop()
+	view := countingView{}
+	search := &newsSearch{
+		news:    &news{logger: &logger},
+		view:    view,
+		results: make(map[string]*searchResult),
+	}
+
+	ctx := context.Background()
+	steps := []struct {
+		query, page string
+	}{
+		{"выборы", ""},
+		{"погода", ""},
+		// next pages of alternating queries reuse their results
+		{"выборы", "20"},
+		{"погода", "20"},
+		{"выборы", "40"},
+		// first page reloads results
+		{"выборы", ""},
+	}
+
+	for _, step := range steps {
+		if _, _, err := search.Parse(ctx, step.query, step.page); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := map[string]int{
+		"/search?query=%D0%B2%D1%8B%D0%B1%D0%BE%D1%80%D1%8B": 2,
+		"/search?query=%D0%BF%D0%BE%D0%B3%D0%BE%D0%B4%D0%B0": 1,
+	}
+	for path, count := range want {
+		if view[path] != count {
+			t.Errorf("loads of %s = %d, want %d", path, view[path], count)
+		}
+	}
+}
+
+func TestSearchCacheSize(t *testing.T) {
+	search := &newsSearch{view: countingView{}, results: make(map[string]*searchResult)}
+
+	ctx := context.Background()
+	for i := range SEARCH_CACHE_SIZE * 2 {
+		if _, err := search.searchURLs(ctx, string(rune('a'+i)), true); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(search.results) > SEARCH_CACHE_SIZE {
+		t.Errorf("cached results = %d, want at most %d", len(search.results), SEARCH_CACHE_SIZE)
+	}
+}
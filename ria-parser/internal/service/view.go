@@ -4,143 +4,168 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
-	"time"
+	"net/http"
+	"net/url"
 
-	"github.com/DataHenHQ/useragent"
 	"github.com/PuerkitoBio/goquery"
-	"github.com/go-rod/rod"
-	"github.com/go-rod/rod/lib/proto"
-	"github.com/go-rod/stealth"
+	"github.com/qsoulior/news/parser/pkg/browser"
+	"github.com/qsoulior/news/parser/pkg/httpclient"
+	"github.com/rs/zerolog"
 )
 
-type newsView struct {
-	URL     string
-	browser *rod.Browser
+// newsView lists URLs of news shown by page of site.
+type newsView interface {
+	listURLs(ctx context.Context, path string) ([]string, error)
 }
 
-func (n *newsView) parseURLs(ctx context.Context, path string) ([]string, error) {
-	page, err := stealth.Page(n.browser)
-	if err != nil {
-		return nil, fmt.Errorf("stealth.Page: %w", err)
+// newNewsView returns view requesting pages over HTTP.
+// Browser view is used only if HTTP view fails and pool is not nil.
+func newNewsView(url string, client *httpclient.Client, pool *browser.Pool, logger *zerolog.Logger) newsView {
+	view := &fallbackView{
+		view:   &httpView{URL: url, client: client},
+		logger: logger,
 	}
-	defer page.Close()
-
-	page = page.Context(ctx)
 
-	ua, err := useragent.Desktop()
-	if err != nil {
-		return nil, fmt.Errorf("useragent.Desktop: %w", err)
+	if pool != nil {
+		view.fallback = &browserView{URL: url, client: client, pool: pool}
 	}
 
-	err = page.SetUserAgent(&proto.NetworkSetUserAgentOverride{UserAgent: ua})
-	if err != nil {
-		return nil, fmt.Errorf("n.page.SetUserAgent: %w", err)
-	}
+	return view
+}
 
-	err = page.Navigate(n.URL + path)
-	if err != nil {
-		return nil, fmt.Errorf("n.page.Navigate: %w", err)
-	}
+type fallbackView struct {
+	view     newsView
+	fallback newsView
+	logger   *zerolog.Logger
+}
 
-	urls, err := n.parseView(page)
-	if err != nil {
-		return nil, fmt.Errorf("n.parseView: %w", err)
+func (v *fallbackView) listURLs(ctx context.Context, path string) ([]string, error) {
+	urls, err := v.view.listURLs(ctx, path)
+	if err == nil || v.fallback == nil || ctx.Err() != nil {
+		return urls, err
 	}
 
-	return urls, nil
+	v.logger.Warn().Err(err).Str("path", path).Msg("view falls back to browser")
+	return v.fallback.listURLs(ctx, path)
 }
 
-func (n *newsView) parseView(page *rod.Page) ([]string, error) {
-	err := n.loadView(page)
-	if err != nil {
-		return nil, fmt.Errorf("n.loadView: %w", err)
-	}
+// VIEW_MAX_MORE limits number of "more" requests of single view.
+const VIEW_MAX_MORE = 100
+
+var (
+	errNoList = errors.New("list of news is not found")
+	// errNoPagination is returned if page has "more" button but items do not
+	// point to next ones, so only browser can load them.
+	errNoPagination = errors.New("pagination of list is not found")
+)
 
-	list, err := page.Element(".list")
+// httpView lists URLs by requesting page and its "more" endpoints
+// given by data-next-url attribute of loaded items, as page script does.
+type httpView struct {
+	URL    string
+	client *httpclient.Client
+}
+
+func (n *httpView) listURLs(ctx context.Context, path string) ([]string, error) {
+	doc, err := n.get(ctx, n.URL+path, nil)
 	if err != nil {
-		return nil, fmt.Errorf("page.Element: %w", err)
+		return nil, err
 	}
 
-	err = list.WaitStable(1 * time.Second)
-	if err != nil {
-		return nil, fmt.Errorf("list.WaitStable: %w", err)
+	list := doc.Find(".list")
+	if list.Length() == 0 {
+		return nil, errNoList
 	}
 
-	listHTML, err := list.HTML()
-	if err != nil {
-		return nil, fmt.Errorf("list.HTML: %w", err)
+	var (
+		urls = make([]string, 0)
+		seen = make(map[string]struct{})
+	)
+
+	// add appends new URLs of items and returns their count
+	add := func(s *goquery.Selection) int {
+		count := 0
+		s.Find(".list-item__title[href]").Each(func(i int, s *goquery.Selection) {
+			href, _ := s.Attr("href")
+			if href = n.resolve(href); href == "" {
+				return
+			}
+
+			if _, ok := seen[href]; ok {
+				return
+			}
+
+			seen[href] = struct{}{}
+			urls = append(urls, href)
+			count++
+		})
+		return count
 	}
 
-	listDocument, err := goquery.NewDocumentFromReader(strings.NewReader(listHTML))
-	if err != nil {
-		return nil, fmt.Errorf("goquery.NewDocumentFromReader: %w", err)
+	add(list)
+	next := n.next(list)
+	if next == "" && doc.Find(".list-more").Length() > 0 {
+		return nil, errNoPagination
 	}
 
-	urls := listDocument.Find(".list-item__title[href]").Map(func(i int, s *goquery.Selection) string {
-		href, _ := s.Attr("href")
-		return href
-	})
+	for i := 0; next != "" && i < VIEW_MAX_MORE; i++ {
+		more, err := n.get(ctx, next, map[string]string{
+			"X-Requested-With": "XMLHttpRequest",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("more: %w", err)
+		}
 
-	return urls, nil
-}
+		if add(more.Selection) == 0 {
+			break
+		}
 
-func (n *newsView) loadView(page *rod.Page) error {
-	err := page.WaitLoad()
-	if err != nil {
-		return fmt.Errorf("page.WaitLoad: %w", err)
+		next = n.next(more.Selection)
 	}
 
-	listMore, err := page.Element(".list-more")
+	return urls, nil
+}
+
+func (n *httpView) get(ctx context.Context, url string, headers map[string]string) (*goquery.Document, error) {
+	resp, err := n.client.Get(ctx, url, headers)
 	if err != nil {
-		return fmt.Errorf("page.Element: %w", err)
+		return nil, fmt.Errorf("n.client.Get: %w", err)
 	}
+	defer resp.Body.Close()
 
-	err = listMore.Timeout(5 * time.Second).WaitStableRAF()
-	if err != nil {
-		return fmt.Errorf("listMore.WaitStable: %w", err)
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp.StatusCode)
 	}
 
-	err = listMore.Click(proto.InputMouseButtonLeft, 1)
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
-		return fmt.Errorf("listMore.Click: %w", err)
+		return nil, fmt.Errorf("goquery.NewDocumentFromReader: %w", err)
 	}
 
-	for {
-		err = listMore.Timeout(5 * time.Second).WaitStableRAF()
-		if err != nil {
-			if errors.Is(err, context.DeadlineExceeded) {
-				break
-			}
-			return fmt.Errorf("listMore.WaitStableRAF: %w", err)
-		}
-
-		visible, err := listMore.Visible()
-		if err != nil {
-			return fmt.Errorf("listMore.Visible: %w", err)
-		}
+	return doc, nil
+}
 
-		if !visible {
-			break
-		}
+// next returns URL of next items, last loaded items point to it.
+func (n *httpView) next(s *goquery.Selection) string {
+	href, _ := s.Find("[data-next-url]").AddBackFiltered("[data-next-url]").Last().Attr("data-next-url")
+	return n.resolve(href)
+}
 
-		loading, err := listMore.Matches(".loading")
-		if err != nil {
-			return fmt.Errorf("listMore.Matches: %w", err)
-		}
+// resolve returns absolute URL of href relative to site.
+func (n *httpView) resolve(href string) string {
+	if href == "" {
+		return ""
+	}
 
-		if loading {
-			continue
-		}
+	base, err := url.Parse(n.URL)
+	if err != nil {
+		return ""
+	}
 
-		err = listMore.Timeout(5 * time.Second).ScrollIntoView()
-		if err != nil {
-			if errors.Is(err, context.DeadlineExceeded) {
-				break
-			}
-			return fmt.Errorf("listMore.ScrollIntoView: %w", err)
-		}
+	u, err := base.Parse(href)
+	if err != nil {
+		return ""
 	}
 
-	return nil
+	return u.String()
 }
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qsoulior/news/parser/pkg/httpclient"
+	"github.com/rs/zerolog"
+)
+
+// newTestSite returns server of pages by path.
+func newTestSite(t *testing.T, pages map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.RequestURI()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, page)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+type staticView []string
+
+func (v staticView) listURLs(ctx context.Context, path string) ([]string, error) {
+	return v, nil
+}
+
+func TestHTTPViewMore(t *testing.T) {
+	server := newTestSite(t, map[string]string{
+		"/search/": `<div class="list">
+			<div class="list-item"><a class="list-item__title" href="/a.html">a</a></div>
+			<div class="list-item" data-next-url="/more/?page=2"><a class="list-item__title" href="/b.html">b</a></div>
+		</div><div class="list-more">more</div>`,
+		"/more/?page=2": `<div class="list-item"><a class="list-item__title" href="/c.html">c</a></div>`,
+	})
+
+	view := &httpView{URL: server.URL, client: httpclient.New()}
+	urls, err := view.listURLs(context.Background(), "/search/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{server.URL + "/a.html", server.URL + "/b.html", server.URL + "/c.html"}
+	if fmt.Sprint(urls) != fmt.Sprint(want) {
+		t.Errorf("urls = %v, want %v", urls, want)
+	}
+}
+
+func TestHTTPViewNoPagination(t *testing.T) {
+	server := newTestSite(t, map[string]string{
+		// page loads more items by script only
+		"/search/": `<div class="list">
+			<div class="list-item"><a class="list-item__title" href="/a.html">a</a></div>
+		</div><div class="list-more">more</div>`,
+		// page shows all items
+		"/short/": `<div class="list">
+			<div class="list-item"><a class="list-item__title" href="/a.html">a</a></div>
+		</div>`,
+	})
+
+	logger := zerolog.Nop()
+	view := &fallbackView{
+		view:     &httpView{URL: server.URL, client: httpclient.New()},
+		fallback: staticView{"browser"},
+		logger:   &logger,
+	}
+
+	_, err := view.view.listURLs(context.Background(), "/search/")
+	if !errors.Is(err, errNoPagination) {
+		t.Fatalf("listURLs error = %v, want errNoPagination", err)
+	}
+
+	urls, err := view.listURLs(context.Background(), "/search/")
+	if err != nil || fmt.Sprint(urls) != "[browser]" {
+		t.Errorf("listURLs = %v, %v, want urls of browser", urls, err)
+	}
+
+	urls, err = view.listURLs(context.Background(), "/short/")
+	if err != nil || fmt.Sprint(urls) != fmt.Sprint([]string{server.URL + "/a.html"}) {
+		t.Errorf("listURLs of complete list = %v, %v, want urls of page", urls, err)
+	}
+}
@@ -0,0 +1,26 @@
+// Package source registers ria parser in parser binary hosting several sources.
+package source
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qsoulior/news/parser/app"
+	"github.com/qsoulior/news/parser/pkg/httpclient"
+	ria "github.com/qsoulior/news/ria-parser/internal/app"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	app.Register(ria.APP_ID, New)
+}
+
+// New returns parsers of ria source.
+func New(ctx context.Context, decode func(cfg any) error, opts []httpclient.Option, logger *zerolog.Logger) (*app.Source, error) {
+	cfg := new(ria.Config)
+	if err := decode(cfg); err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+
+	return ria.New(ctx, cfg, opts, logger)
+}